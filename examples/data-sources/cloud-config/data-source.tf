@@ -0,0 +1,12 @@
+data "cloud-config" "main" {
+  hostname = "domain"
+  fqdn     = "domain.lan"
+  timezone = "Asia/Tokyo"
+
+  runcmd = ["echo '11'", "cat /etc/hosts"]
+
+  users {
+    name  = "admin"
+    shell = "/bin/bash"
+  }
+}
@@ -0,0 +1,9 @@
+data "cloud-config_meta_data" "meta_data" {
+  format         = "json"
+  instance_id    = "i-0001"
+  local_hostname = "node1"
+
+  extra_yaml = <<-EOT
+    dsmode: local
+  EOT
+}
@@ -0,0 +1,16 @@
+data "cloud-config_network" "network" {
+  ethernets {
+    id    = "eth0"
+    dhcp4 = true
+  }
+}
+
+# Same network in version 1 format, for images with older cloud-init
+data "cloud-config_network" "legacy" {
+  version = 1
+
+  ethernets {
+    id    = "eth0"
+    dhcp4 = true
+  }
+}
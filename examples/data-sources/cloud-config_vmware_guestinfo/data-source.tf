@@ -0,0 +1,18 @@
+resource "cloud-config" "config" {
+  hostname = "node1"
+}
+
+resource "cloud-config_meta_data" "meta_data" {
+  instance_id = "i-0001"
+  hostname    = resource.cloud-config.config.hostname
+}
+
+data "cloud-config_vmware_guestinfo" "guestinfo" {
+  user_data = resource.cloud-config.config.content
+  meta_data = resource.cloud-config_meta_data.meta_data.content
+}
+
+# Pass it to `extra_config` of `vsphere_virtual_machine`
+output "extra_config" {
+  value = data.cloud-config_vmware_guestinfo.guestinfo.extra_config
+}
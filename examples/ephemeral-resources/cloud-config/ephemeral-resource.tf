@@ -0,0 +1,15 @@
+ephemeral "cloud-config" "main" {
+  hostname = "domain"
+  fqdn     = "domain.lan"
+
+  users {
+    name              = "admin"
+    shell             = "/bin/bash"
+    plain_text_passwd = var.admin_password
+  }
+}
+
+# Ephemeral values can only be used in ephemeral contexts, e.g. write-only arguments or provider blocks
+resource "example_instance" "main" {
+  user_data_wo = ephemeral.cloud-config.main.content
+}
@@ -0,0 +1,14 @@
+locals {
+  legacy = provider::cloud-config::decode(file("${path.module}/user-data.yaml"))
+}
+
+output "hostname" {
+  value = local.legacy.hostname
+}
+
+# Decoded object can be rendered back, e.g. after adjusting some values
+output "user_data" {
+  value = provider::cloud-config::render(merge(local.legacy, {
+    timezone = "Asia/Tokyo"
+  }))
+}
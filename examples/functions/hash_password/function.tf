@@ -0,0 +1,16 @@
+resource "random_password" "admin" {
+  length = 24
+}
+
+resource "random_string" "salt" {
+  length  = 16
+  special = false
+}
+
+resource "cloud-config" "config" {
+  users {
+    name          = "admin"
+    hashed_passwd = provider::cloud-config::hash_password(random_password.admin.result, "sha512crypt", random_string.salt.result)
+    lock_passwd   = false
+  }
+}
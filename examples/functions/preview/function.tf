@@ -0,0 +1,16 @@
+resource "cloud-config" "node" {
+  template = "jinja"
+  hostname = "{{ v1.local_hostname }}"
+  runcmd   = ["echo 'running in {{ v1.region }}'"]
+}
+
+# Rendered per-instance content, e.g. to be asserted by `terraform test`
+output "user_data" {
+  value = provider::cloud-config::preview(resource.cloud-config.node.content, jsonencode({
+    v1 = {
+      local_hostname = "node1"
+      region         = "eu-west-1"
+    }
+  }))
+  sensitive = true
+}
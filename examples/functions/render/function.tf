@@ -0,0 +1,19 @@
+locals {
+  hosts = ["one", "two"]
+
+  user_data = {
+    for host in local.hosts : host => provider::cloud-config::render({
+      hostname = host
+      fqdn     = "${host}.lan"
+      timezone = "Asia/Tokyo"
+
+      users = [
+        { name = "admin", shell = "/bin/bash" },
+      ]
+    })
+  }
+}
+
+output "user_data" {
+  value = local.user_data
+}
@@ -0,0 +1,15 @@
+provider "cloud-config" {
+  # Inherited by every `cloud-config` resource and data source
+  defaults {
+    timezone    = "UTC"
+    ntp_servers = ["ntp.example.com"]
+    packages    = ["curl", "qemu-guest-agent"]
+
+    users {
+      name                = "admin"
+      groups              = ["sudo"]
+      sudo                = ["ALL=(ALL) NOPASSWD:ALL"]
+      ssh_authorized_keys = ["ssh-ed25519 AAAA..."]
+    }
+  }
+}
@@ -0,0 +1,4 @@
+import {
+  to = cloud-config.example
+  id = "${path.module}/user-data.yaml"
+}
@@ -0,0 +1,5 @@
+# Import from a local cloud-config file
+terraform import cloud-config.example ./user-data.yaml
+
+# Import from base64 encoded content
+terraform import cloud-config.example "$(base64 -w0 user-data.yaml)"
@@ -0,0 +1,12 @@
+resource "cloud-config" "config" {
+  hostname = "node1"
+  fqdn     = "node1.example.com"
+}
+
+# local-hostname is derived from the same hostname and fqdn as user-data
+resource "cloud-config_meta_data" "meta_data" {
+  instance_id = "i-0001"
+  hostname    = resource.cloud-config.config.hostname
+  fqdn        = resource.cloud-config.config.fqdn
+  public_keys = ["ssh-ed25519 AAAA... admin@example.com"]
+}
@@ -0,0 +1,28 @@
+resource "cloud-config_multipart" "user_data" {
+  gzip          = true
+  base64_encode = true
+
+  part {
+    filename = "cloud-config.yaml"
+
+    cloud_config {
+      hostname = "vm"
+      fqdn     = "vm.lan"
+
+      package_update = true
+      packages       = ["qemu-guest-agent"]
+    }
+  }
+
+  part {
+    content_type = "text/x-shellscript"
+    filename     = "bootstrap.sh"
+    content      = file("${path.module}/bootstrap.sh")
+  }
+
+  part {
+    content_type = "text/cloud-config"
+    merge_type   = "list(append)+dict(no_replace,recurse_list)+str()"
+    content      = cloud-config.extra.content
+  }
+}
@@ -0,0 +1,47 @@
+resource "cloud-config_network" "network" {
+  ethernets {
+    id       = "uplink0"
+    set_name = "uplink0"
+
+    match {
+      macaddress = "52:54:00:12:34:56"
+    }
+  }
+
+  ethernets {
+    id       = "uplink1"
+    set_name = "uplink1"
+
+    match {
+      macaddress = "52:54:00:12:34:57"
+    }
+  }
+
+  bonds {
+    id         = "bond0"
+    interfaces = ["uplink0", "uplink1"]
+
+    parameters {
+      mode                 = "802.3ad"
+      lacp_rate            = "fast"
+      mii_monitor_interval = 100
+    }
+  }
+
+  vlans {
+    id        = "vlan42"
+    vlan_id   = 42
+    link      = "bond0"
+    addresses = ["192.168.42.10/24"]
+
+    routes {
+      to  = "default"
+      via = "192.168.42.1"
+    }
+
+    nameservers {
+      addresses = ["192.168.42.1"]
+      search    = ["lan"]
+    }
+  }
+}
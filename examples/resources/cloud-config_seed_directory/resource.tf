@@ -0,0 +1,18 @@
+resource "cloud-config" "config" {
+  hostname = "node1"
+}
+
+resource "cloud-config_meta_data" "meta_data" {
+  instance_id = "i-0001"
+  hostname    = resource.cloud-config.config.hostname
+}
+
+# Serve the directory with `python3 -m http.server --directory seed 8000`,
+# and boot QEMU with `-smbios type=1,serial=${resource.cloud-config_seed_directory.seed.smbios_serial}`
+resource "cloud-config_seed_directory" "seed" {
+  path            = "${path.module}/seed"
+  seed_url        = "http://10.0.2.2:8000/"
+  user_data       = resource.cloud-config.config.content
+  meta_data       = resource.cloud-config_meta_data.meta_data.content
+  file_permission = "0644"
+}
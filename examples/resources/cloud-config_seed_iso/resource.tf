@@ -0,0 +1,23 @@
+resource "cloud-config" "config" {
+  hostname = "node1"
+}
+
+resource "cloud-config_meta_data" "meta_data" {
+  instance_id = "i-0001"
+  hostname    = resource.cloud-config.config.hostname
+}
+
+resource "cloud-config_network" "network" {
+  ethernets {
+    id    = "eth0"
+    dhcp4 = true
+  }
+}
+
+resource "cloud-config_seed_iso" "seed" {
+  path            = "${path.module}/seed.iso"
+  user_data       = resource.cloud-config.config.content
+  meta_data       = resource.cloud-config_meta_data.meta_data.content
+  network_config  = resource.cloud-config_network.network.content
+  file_permission = "0644"
+}
@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -16,10 +15,10 @@ type AlpineRepo struct {
 }
 
 type AlpineRepoOutput struct {
-	CommunityEnabled bool   `yaml:"community_enabled,omitempty"`
-	TestingEnabled   bool   `yaml:"testing_enabled,omitempty"`
-	BaseUrl          string `yaml:"base_url,omitempty"`
-	Version          string `yaml:"version,omitempty"`
+	CommunityEnabled *bool   `yaml:"community_enabled,omitempty"`
+	TestingEnabled   *bool   `yaml:"testing_enabled,omitempty"`
+	BaseUrl          *string `yaml:"base_url,omitempty"`
+	Version          *string `yaml:"version,omitempty"`
 }
 
 type ApkRepo struct {
@@ -29,8 +28,8 @@ type ApkRepo struct {
 }
 
 type ApkRepoOutput struct {
-	PreserveRepositories bool              `yaml:"preserve_repositories,omitempty"`
-	LocalRepoBaseUrl     string            `yaml:"local_repo_base_url,omitempty"`
+	PreserveRepositories *bool             `yaml:"preserve_repositories,omitempty"`
+	LocalRepoBaseUrl     *string           `yaml:"local_repo_base_url,omitempty"`
 	AlpineRepo           *AlpineRepoOutput `yaml:"alpine_repo,omitempty"`
 }
 
@@ -49,7 +48,7 @@ func ApkConfigureBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"apk_repos": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("apk_repos")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: "This module handles configuration of the Alpine Package Keeper (APK) /etc/apk/repositories file.",
 				Attributes: map[string]schema.Attribute{
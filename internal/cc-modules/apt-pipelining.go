@@ -32,22 +32,24 @@ func AptPipeliningBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"apt_pipelining": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("apt_pipelining")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: "This module configures APT’s `Acquire::http::Pipeline-Depth` option, which controls how APT handles HTTP pipelining. It may be useful for pipelining to be disabled, because some web servers (such as S3) do not pipeline properly (LP: #948461).",
 				Attributes: map[string]schema.Attribute{
 					"os": schema.BoolAttribute{
-						MarkdownDescription: "Use distro default. This is default behaivor",
+						MarkdownDescription: "Use distro default. This is default behaivor. Only `true` is accepted, cloud-init ignores `apt_pipelining: true`",
 						Optional:            true,
 						Validators: []validator.Bool{
+							boolvalidator.Equals(true),
 							boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("disable")),
 							boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("depth")),
 						},
 					},
 					"disable": schema.BoolAttribute{
-						MarkdownDescription: "Disable pipelining altogether",
+						MarkdownDescription: "Disable pipelining altogether. Only `true` is accepted, cloud-init ignores `apt_pipelining: true`",
 						Optional:            true,
 						Validators: []validator.Bool{
+							boolvalidator.Equals(true),
 							boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("os")),
 							boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("depth")),
 						},
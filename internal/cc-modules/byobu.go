@@ -12,7 +12,7 @@ type ByobuModel struct {
 }
 
 type ByobuOutputModel struct {
-	ByobuByDefault string `yaml:"byobu_by_default,omitempty"`
+	ByobuByDefault *string `yaml:"byobu_by_default,omitempty"`
 }
 
 // Byobu
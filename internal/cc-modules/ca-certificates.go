@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -14,7 +13,7 @@ type CACerts struct {
 }
 
 type CACertsOutput struct {
-	RemoveDefaults bool      `yaml:"remove_defaults,omitempty"`
+	RemoveDefaults *bool     `yaml:"remove_defaults,omitempty"`
 	Trusted        *[]string `yaml:"trusted,omitempty"`
 }
 
@@ -33,7 +32,7 @@ func CACertificatesBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"ca_certs": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("ca_certs")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module adds CA certificates to the system’s CA store and updates any related files using the appropriate OS-specific utility. The default CA certificates can be disabled/deleted from use by the system with the configuration option remove_defaults.
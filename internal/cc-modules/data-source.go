@@ -0,0 +1,18 @@
+package ccmodules
+
+import (
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// DataSourceAttributes
+// Same attributes as `Attributes()`, but in a form suitable for data sources.
+// Plan modifiers and defaults are dropped, because data sources do not plan.
+func (cc *CCModuleFlat) DataSourceAttributes() map[string]dsschema.Attribute {
+	return convertSchema[map[string]dsschema.Attribute](dataSourceTarget, cc.attributes)
+}
+
+// DataSourceBlock
+// Same blocks as `Block()`, but in a form suitable for data sources.
+func (cc *CCModuleNested) DataSourceBlock() map[string]dsschema.Block {
+	return convertSchema[map[string]dsschema.Block](dataSourceTarget, cc.block)
+}
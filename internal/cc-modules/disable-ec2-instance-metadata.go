@@ -10,7 +10,7 @@ type DisableEC2InstanceMetadataModel struct {
 }
 
 type DisableEC2InstanceMetadataOutputModel struct {
-	DisableEC2Metadata bool `yaml:"disable_ec2_metadata,omitempty"`
+	DisableEC2Metadata *bool `yaml:"disable_ec2_metadata,omitempty"`
 }
 
 // DisableEC2InstanceMetadata
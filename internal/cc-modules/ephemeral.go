@@ -0,0 +1,19 @@
+package ccmodules
+
+import (
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+)
+
+// EphemeralAttributes
+// Same attributes as `Attributes()`, but in a form suitable for ephemeral resources.
+// Plan modifiers and defaults are dropped, because ephemeral resources do not plan,
+// write-only attributes become regular ones, because nothing is persisted anyway.
+func (cc *CCModuleFlat) EphemeralAttributes() map[string]ephemeralschema.Attribute {
+	return convertSchema[map[string]ephemeralschema.Attribute](ephemeralTarget, cc.attributes)
+}
+
+// EphemeralBlock
+// Same blocks as `Block()`, but in a form suitable for ephemeral resources.
+func (cc *CCModuleNested) EphemeralBlock() map[string]ephemeralschema.Block {
+	return convertSchema[map[string]ephemeralschema.Block](ephemeralTarget, cc.block)
+}
@@ -0,0 +1,54 @@
+package ccmodules
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	ExtraYAMLConflictError     = "error"
+	ExtraYAMLConflictTypedWins = "typed_wins"
+	ExtraYAMLConflictRawWins   = "raw_wins"
+
+	ExtraYAMLListsAppend  = "append"
+	ExtraYAMLListsReplace = "replace"
+)
+
+type ExtraYAMLModel struct {
+	ExtraYAML          types.String `tfsdk:"extra_yaml"`
+	ExtraYAMLConflicts types.String `tfsdk:"extra_yaml_conflicts"`
+	ExtraYAMLLists     types.String `tfsdk:"extra_yaml_lists"`
+}
+
+// ExtraYAML
+// Not a module, escape hatch for keys which are not modelled by the provider yet
+func ExtraYAML() CCModuleFlat {
+	return CCModuleFlat{
+		attributes: map[string]schema.Attribute{
+			"extra_yaml": schema.StringAttribute{
+				MarkdownDescription: `
+Raw YAML, deep-merged into the rendered document. Use it for keys cloud-init supports, but this provider doesn't model yet.
+
+Mappings are merged key by key, new keys are added after the rendered ones. How lists and conflicting values are merged is controlled by **extra_yaml_lists** and **extra_yaml_conflicts**.
+        `,
+				Optional: true,
+			},
+			"extra_yaml_conflicts": schema.StringAttribute{
+				MarkdownDescription: "What to do when **extra_yaml** sets a key to a different value than the rendered document: `error`, `typed_wins` (keep the value of attributes and blocks) or `raw_wins` (keep the value of **extra_yaml**). *Default*: `error`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ExtraYAMLConflictError, ExtraYAMLConflictTypedWins, ExtraYAMLConflictRawWins),
+				},
+			},
+			"extra_yaml_lists": schema.StringAttribute{
+				MarkdownDescription: "How lists present in both **extra_yaml** and the rendered document are merged: `append` (items of **extra_yaml** go after the rendered ones) or `replace` (lists are treated as conflicting values, see **extra_yaml_conflicts**). *Default*: `append`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(ExtraYAMLListsAppend, ExtraYAMLListsReplace),
+				},
+			},
+		},
+	}
+}
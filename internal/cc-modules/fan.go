@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -14,8 +13,8 @@ type Fan struct {
 }
 
 type FanOutput struct {
-	Config     string `yaml:"config,omitempty"`
-	ConfigPath string `yaml:"config_path,omitempty"`
+	Config     *string `yaml:"config,omitempty"`
+	ConfigPath *string `yaml:"config_path,omitempty"`
 }
 
 type FanModel struct {
@@ -33,7 +32,7 @@ func FanBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"fan": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("fan")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module installs, configures and starts the Ubuntu fan network system ([Read more about Ubuntu Fan](https://wiki.ubuntu.com/FanNetworking)).
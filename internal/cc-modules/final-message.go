@@ -10,7 +10,7 @@ type FinalMessageModel struct {
 }
 
 type FinalMessageOutputModel struct {
-	FinalMessage string `yaml:"final_message,omitempty"`
+	FinalMessage *string `yaml:"final_message,omitempty"`
 }
 
 // FinalMessage
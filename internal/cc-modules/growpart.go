@@ -2,7 +2,6 @@ package ccmodules
 
 import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -17,9 +16,9 @@ type Growpart struct {
 }
 
 type GrowpartOutput struct {
-	Mode                   string    `yaml:"mode"`
-	Devices                *[]string `yaml:"devices"`
-	IgnoreGrowrootDisabled bool      `yaml:"ignore_growroot_disabled"`
+	Mode                   *string   `yaml:"mode,omitempty"`
+	Devices                *[]string `yaml:"devices,omitempty"`
+	IgnoreGrowrootDisabled *bool     `yaml:"ignore_growroot_disabled,omitempty"`
 }
 
 type GrowpartModel struct {
@@ -37,7 +36,7 @@ func GrowpartBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"growpart": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("growpart")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 Growpart resizes partitions to fill the available disk space. This is useful for cloud instances with a larger amount of disk space available than the pristine image uses, as it allows the instance to automatically make use of the extra space.
@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -16,10 +15,10 @@ type GRUBDpkg struct {
 }
 
 type GRUBDpkgOutput struct {
-	Enabled                    bool   `yaml:"enabled,omitempty"`
-	GRUBPC_InstallDevices      string `yaml:"grub-pc/install_devices,omitempty"`
-	GRUBPC_InstallDevicesEmpty bool   `yaml:"grub-pc/install_devices_empty,omitempty"`
-	GRUBEFI_InstallDevices     string `yaml:"grub-efi/install_devices,omitempty"`
+	Enabled                    *bool   `yaml:"enabled,omitempty"`
+	GRUBPC_InstallDevices      *string `yaml:"grub-pc/install_devices,omitempty"`
+	GRUBPC_InstallDevicesEmpty *bool   `yaml:"grub-pc/install_devices_empty,omitempty"`
+	GRUBEFI_InstallDevices     *string `yaml:"grub-efi/install_devices,omitempty"`
 }
 
 type GRUBDpkgModel struct {
@@ -37,7 +36,7 @@ func GRUBDpkgBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"grub_dpkg": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("grub_dpkg")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 Configure which device is used as the target for GRUB installation. This module can be enabled/disabled using the enabled config key in the grub_dpkg config dict. This module automatically selects a disk using grub-probe if no installation device is specified.
@@ -3,7 +3,6 @@ package ccmodules
 import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -42,7 +41,7 @@ func InstallHotplugBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"updates": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("updates")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module will install the udev rules to enable hotplug if supported by the datasource and enabled in the user-data. The udev rules will be installed as /etc/udev/rules.d/90-cloud-init-hook-hotplug.rules.
@@ -56,7 +55,7 @@ Currently supported datasources: **Openstack, EC2**
 				Blocks: map[string]schema.Block{
 					"network": schema.SingleNestedBlock{
 						PlanModifiers: []planmodifier.Object{
-							utils.NullWhen(path.Root("network")),
+							utils.NullWhen(),
 						},
 						Attributes: map[string]schema.Attribute{
 							"when": schema.ListAttribute{
@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -16,10 +15,10 @@ type Keyboard struct {
 }
 
 type KeyboardOutput struct {
-	Layout  string `yaml:"layout,omitempty"`
-	Model   string `yaml:"model,omitempty"`
-	Variant string `yaml:"variant,omitempty"`
-	Options string `yaml:"options,omitempty"`
+	Layout  *string `yaml:"layout,omitempty"`
+	Model   *string `yaml:"model,omitempty"`
+	Variant *string `yaml:"variant,omitempty"`
+	Options *string `yaml:"options,omitempty"`
 }
 
 type KeyboardModel struct {
@@ -37,7 +36,7 @@ func KeyboardBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"keyboard": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("keyboard")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: "Handle keyboard configuration.",
 				Attributes: map[string]schema.Attribute{
@@ -2,7 +2,6 @@ package ccmodules
 
 import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -11,29 +10,30 @@ import (
 )
 
 type Client struct {
-	URL             types.String `tfsdk:"url"`
-	PingURL         types.String `tfsdk:"ping_url"`
-	DataPath        types.String `tfsdk:"data_path"`
-	LogLevel        types.String `tfsdk:"log_level"`
-	ComputerTitle   types.String `tfsdk:"computer_title"`
-	AccountName     types.String `tfsdk:"account_name"`
-	RegistrationKey types.String `tfsdk:"registration_key"`
-	Tags            types.String `tfsdk:"tags"`
-	HTTPProxy       types.String `tfsdk:"http_proxy"`
-	HTTPSProxy      types.String `tfsdk:"https_proxy"`
+	URL               types.String `tfsdk:"url"`
+	PingURL           types.String `tfsdk:"ping_url"`
+	DataPath          types.String `tfsdk:"data_path"`
+	LogLevel          types.String `tfsdk:"log_level"`
+	ComputerTitle     types.String `tfsdk:"computer_title"`
+	AccountName       types.String `tfsdk:"account_name"`
+	RegistrationKey   types.String `tfsdk:"registration_key"`
+	RegistrationKeyWO types.String `tfsdk:"registration_key_wo"`
+	Tags              types.String `tfsdk:"tags"`
+	HTTPProxy         types.String `tfsdk:"http_proxy"`
+	HTTPSProxy        types.String `tfsdk:"https_proxy"`
 }
 
 type ClientOutput struct {
-	URL             string `yaml:"url,omitempty"`
-	PingURL         string `yaml:"ping_url,omitempty"`
-	DataPath        string `yaml:"data_path,omitempty"`
-	LogLevel        string `yaml:"log_level,omitempty"`
-	ComputerTitle   string `yaml:"computer_title,omitempty"`
-	AccountName     string `yaml:"account_name,omitempty"`
-	RegistrationKey string `yaml:"registration_key,omitempty"`
-	Tags            string `yaml:"tags,omitempty"`
-	HTTPProxy       string `yaml:"http_proxy,omitempty"`
-	HTTPSProxy      string `yaml:"https_proxy,omitempty"`
+	URL             *string `yaml:"url,omitempty"`
+	PingURL         *string `yaml:"ping_url,omitempty"`
+	DataPath        *string `yaml:"data_path,omitempty"`
+	LogLevel        *string `yaml:"log_level,omitempty"`
+	ComputerTitle   *string `yaml:"computer_title,omitempty"`
+	AccountName     *string `yaml:"account_name,omitempty"`
+	RegistrationKey *string `yaml:"registration_key,omitempty"`
+	Tags            *string `yaml:"tags,omitempty"`
+	HTTPProxy       *string `yaml:"http_proxy,omitempty"`
+	HTTPSProxy      *string `yaml:"https_proxy,omitempty"`
 }
 
 type Landscape struct {
@@ -59,7 +59,7 @@ func LandscapeBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"landscape": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("landscape")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module installs and configures landscape-client. The Landscape client will only be installed if the key landscape is present in config.
@@ -87,10 +87,12 @@ If tags is defined, its contents should be a string delimited with a comma (“,
 							},
 							"computer_title":   schema.StringAttribute{MarkdownDescription: "The title of this computer.", Optional: true},
 							"account_name":     schema.StringAttribute{MarkdownDescription: "The account this computer belongs to.", Optional: true},
-							"registration_key": schema.StringAttribute{MarkdownDescription: "The account-wide key used for registering clients.", Optional: true},
+							"registration_key": schema.StringAttribute{MarkdownDescription: "The account-wide key used for registering clients.", Optional: true, Sensitive: true},
 							"tags":             schema.StringAttribute{MarkdownDescription: "Comma separated list of tag names to be sent to the server.", Optional: true},
 							"http_proxy":       schema.StringAttribute{MarkdownDescription: "The URL of the HTTP proxy, if one is needed.", Optional: true},
 							"https_proxy":      schema.StringAttribute{MarkdownDescription: "The URL of the HTTPS proxy, if one is needed.", Optional: true},
+
+							"registration_key_wo": writeOnly("registration_key"),
 						},
 					},
 				},
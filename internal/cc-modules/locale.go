@@ -11,8 +11,8 @@ type LocaleModel struct {
 }
 
 type LocaleOutputModel struct {
-	Locale           string `yaml:"locale,omitempty"`
-	LocaleConfigfile string `yaml:"locale_configfile,omitempty"`
+	Locale           *string `yaml:"locale,omitempty"`
+	LocaleConfigfile *string `yaml:"locale_configfile,omitempty"`
 }
 
 // Locale
@@ -0,0 +1,86 @@
+package ccmodules
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	MetaDataFormatYAML = "yaml"
+	MetaDataFormatJSON = "json"
+)
+
+type MetaDataModel struct {
+	Format        types.String `tfsdk:"format"`
+	InstanceID    types.String `tfsdk:"instance_id"`
+	LocalHostname types.String `tfsdk:"local_hostname"`
+	PublicKeys    types.List   `tfsdk:"public_keys"`
+
+	Hostname               types.String `tfsdk:"hostname"`
+	FQDN                   types.String `tfsdk:"fqdn"`
+	PreferFQDNOverHostname types.Bool   `tfsdk:"prefer_fqdn_over_hostname"`
+
+	ExtraYAMLModel
+}
+
+type MetaDataOutputModel struct {
+	InstanceID    string    `yaml:"instance-id"`
+	LocalHostname *string   `yaml:"local-hostname,omitempty"`
+	PublicKeys    *[]string `yaml:"public-keys,omitempty"`
+}
+
+// MetaData
+// Not a module, `meta-data` of NoCloud datasource
+// @see https://cloudinit.readthedocs.io/en/latest/reference/datasources/nocloud.html
+func MetaData() CCModuleFlat {
+	attributes := map[string]schema.Attribute{
+		"format": schema.StringAttribute{
+			MarkdownDescription: "Format of rendered content: `yaml` or `json`. *Default*: `yaml`.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.OneOf(MetaDataFormatYAML, MetaDataFormatJSON),
+			},
+		},
+		"instance_id": schema.StringAttribute{
+			MarkdownDescription: "ID of the instance. cloud-init runs per-instance modules again, when it changes.",
+			Required:            true,
+		},
+		"local_hostname": schema.StringAttribute{
+			MarkdownDescription: "Hostname of the instance. Unless set, it is derived from **hostname** and **fqdn** the same way `set_hostname` module does, so user-data and meta-data agree.",
+			Optional:            true,
+			Computed:            true,
+			Validators: []validator.String{
+				stringvalidator.ConflictsWith(path.MatchRoot("hostname"), path.MatchRoot("fqdn")),
+			},
+		},
+		"public_keys": schema.ListAttribute{
+			MarkdownDescription: "SSH public keys of the default user.",
+			ElementType:         types.StringType,
+			Optional:            true,
+		},
+		"hostname": schema.StringAttribute{
+			MarkdownDescription: "Same as **hostname** of `cloud-config`, used to derive **local_hostname**.",
+			Optional:            true,
+		},
+		"fqdn": schema.StringAttribute{
+			MarkdownDescription: "Same as **fqdn** of `cloud-config`, used to derive **local_hostname**. Its first label is the hostname, unless **hostname** is set.",
+			Optional:            true,
+		},
+		"prefer_fqdn_over_hostname": schema.BoolAttribute{
+			MarkdownDescription: "Same as **prefer_fqdn_over_hostname** of `cloud-config`. If true, **fqdn** is used as **local_hostname** when it is set.",
+			Optional:            true,
+		},
+	}
+
+	extra := ExtraYAML()
+	for name, attribute := range extra.attributes {
+		attributes[name] = attribute
+	}
+
+	return CCModuleFlat{
+		attributes: attributes,
+	}
+}
@@ -0,0 +1,672 @@
+package ccmodules
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+type NetworkNameservers struct {
+	Addresses types.List `tfsdk:"addresses"`
+	Search    types.List `tfsdk:"search"`
+}
+
+type NetworkNameserversOutput struct {
+	Addresses *[]string `yaml:"addresses,omitempty"`
+	Search    *[]string `yaml:"search,omitempty"`
+}
+
+type NetworkRoute struct {
+	To     types.String `tfsdk:"to"`
+	Via    types.String `tfsdk:"via"`
+	Metric types.Int64  `tfsdk:"metric"`
+	Table  types.Int64  `tfsdk:"table"`
+	OnLink types.Bool   `tfsdk:"on_link"`
+	Type   types.String `tfsdk:"type"`
+}
+
+type NetworkRouteOutput struct {
+	To     *string `yaml:"to,omitempty"`
+	Via    *string `yaml:"via,omitempty"`
+	Metric *int64  `yaml:"metric,omitempty"`
+	Table  *int64  `yaml:"table,omitempty"`
+	OnLink *bool   `yaml:"on-link,omitempty"`
+	Type   *string `yaml:"type,omitempty"`
+}
+
+type NetworkRoutingPolicy struct {
+	From          types.String `tfsdk:"from"`
+	To            types.String `tfsdk:"to"`
+	Table         types.Int64  `tfsdk:"table"`
+	Priority      types.Int64  `tfsdk:"priority"`
+	Mark          types.Int64  `tfsdk:"mark"`
+	TypeOfService types.Int64  `tfsdk:"type_of_service"`
+}
+
+type NetworkRoutingPolicyOutput struct {
+	From          *string `yaml:"from,omitempty"`
+	To            *string `yaml:"to,omitempty"`
+	Table         *int64  `yaml:"table,omitempty"`
+	Priority      *int64  `yaml:"priority,omitempty"`
+	Mark          *int64  `yaml:"mark,omitempty"`
+	TypeOfService *int64  `yaml:"type-of-service,omitempty"`
+}
+
+// NetworkDevice
+// Properties shared by every kind of device
+type NetworkDevice struct {
+	ID            types.String            `tfsdk:"id"`
+	DHCP4         types.Bool              `tfsdk:"dhcp4"`
+	DHCP6         types.Bool              `tfsdk:"dhcp6"`
+	AcceptRA      types.Bool              `tfsdk:"accept_ra"`
+	Addresses     types.List              `tfsdk:"addresses"`
+	Gateway4      types.String            `tfsdk:"gateway4"`
+	Gateway6      types.String            `tfsdk:"gateway6"`
+	MTU           types.Int64             `tfsdk:"mtu"`
+	MACAddress    types.String            `tfsdk:"macaddress"`
+	Optional      types.Bool              `tfsdk:"optional"`
+	Nameservers   *NetworkNameservers     `tfsdk:"nameservers"`
+	Routes        *[]NetworkRoute         `tfsdk:"routes"`
+	RoutingPolicy *[]NetworkRoutingPolicy `tfsdk:"routing_policy"`
+}
+
+type NetworkDeviceOutput struct {
+	DHCP4         *bool                         `yaml:"dhcp4,omitempty"`
+	DHCP6         *bool                         `yaml:"dhcp6,omitempty"`
+	AcceptRA      *bool                         `yaml:"accept-ra,omitempty"`
+	Addresses     *[]string                     `yaml:"addresses,omitempty"`
+	Gateway4      *string                       `yaml:"gateway4,omitempty"`
+	Gateway6      *string                       `yaml:"gateway6,omitempty"`
+	MTU           *int64                        `yaml:"mtu,omitempty"`
+	MACAddress    *string                       `yaml:"macaddress,omitempty"`
+	Optional      *bool                         `yaml:"optional,omitempty"`
+	Nameservers   *NetworkNameserversOutput     `yaml:"nameservers,omitempty"`
+	Routes        *[]NetworkRouteOutput         `yaml:"routes,omitempty"`
+	RoutingPolicy *[]NetworkRoutingPolicyOutput `yaml:"routing-policy,omitempty"`
+}
+
+type NetworkMatch struct {
+	Name       types.String `tfsdk:"name"`
+	MACAddress types.String `tfsdk:"macaddress"`
+	Driver     types.String `tfsdk:"driver"`
+}
+
+type NetworkMatchOutput struct {
+	Name       *string `yaml:"name,omitempty"`
+	MACAddress *string `yaml:"macaddress,omitempty"`
+	Driver     *string `yaml:"driver,omitempty"`
+}
+
+type NetworkEthernet struct {
+	NetworkDevice
+
+	Match     *NetworkMatch `tfsdk:"match"`
+	SetName   types.String  `tfsdk:"set_name"`
+	WakeOnLAN types.Bool    `tfsdk:"wakeonlan"`
+}
+
+type NetworkEthernetOutput struct {
+	Match     *NetworkMatchOutput `yaml:"match,omitempty"`
+	SetName   *string             `yaml:"set-name,omitempty"`
+	WakeOnLAN *bool               `yaml:"wakeonlan,omitempty"`
+
+	NetworkDeviceOutput `yaml:",inline"`
+}
+
+type NetworkBondParameters struct {
+	Mode               types.String `tfsdk:"mode"`
+	LACPRate           types.String `tfsdk:"lacp_rate"`
+	MIIMonitorInterval types.Int64  `tfsdk:"mii_monitor_interval"`
+	MinLinks           types.Int64  `tfsdk:"min_links"`
+	TransmitHashPolicy types.String `tfsdk:"transmit_hash_policy"`
+	UpDelay            types.Int64  `tfsdk:"up_delay"`
+	DownDelay          types.Int64  `tfsdk:"down_delay"`
+	Primary            types.String `tfsdk:"primary"`
+}
+
+type NetworkBondParametersOutput struct {
+	Mode               *string `yaml:"mode,omitempty"`
+	LACPRate           *string `yaml:"lacp-rate,omitempty"`
+	MIIMonitorInterval *int64  `yaml:"mii-monitor-interval,omitempty"`
+	MinLinks           *int64  `yaml:"min-links,omitempty"`
+	TransmitHashPolicy *string `yaml:"transmit-hash-policy,omitempty"`
+	UpDelay            *int64  `yaml:"up-delay,omitempty"`
+	DownDelay          *int64  `yaml:"down-delay,omitempty"`
+	Primary            *string `yaml:"primary,omitempty"`
+}
+
+type NetworkBond struct {
+	NetworkDevice
+
+	Interfaces types.List             `tfsdk:"interfaces"`
+	Parameters *NetworkBondParameters `tfsdk:"parameters"`
+}
+
+type NetworkBondOutput struct {
+	Interfaces *[]string                    `yaml:"interfaces,omitempty"`
+	Parameters *NetworkBondParametersOutput `yaml:"parameters,omitempty"`
+
+	NetworkDeviceOutput `yaml:",inline"`
+}
+
+type NetworkBridgeParameters struct {
+	AgeingTime   types.Int64 `tfsdk:"ageing_time"`
+	Priority     types.Int64 `tfsdk:"priority"`
+	ForwardDelay types.Int64 `tfsdk:"forward_delay"`
+	HelloTime    types.Int64 `tfsdk:"hello_time"`
+	MaxAge       types.Int64 `tfsdk:"max_age"`
+	STP          types.Bool  `tfsdk:"stp"`
+}
+
+type NetworkBridgeParametersOutput struct {
+	AgeingTime   *int64 `yaml:"ageing-time,omitempty"`
+	Priority     *int64 `yaml:"priority,omitempty"`
+	ForwardDelay *int64 `yaml:"forward-delay,omitempty"`
+	HelloTime    *int64 `yaml:"hello-time,omitempty"`
+	MaxAge       *int64 `yaml:"max-age,omitempty"`
+	STP          *bool  `yaml:"stp,omitempty"`
+}
+
+type NetworkBridge struct {
+	NetworkDevice
+
+	Interfaces types.List               `tfsdk:"interfaces"`
+	Parameters *NetworkBridgeParameters `tfsdk:"parameters"`
+}
+
+type NetworkBridgeOutput struct {
+	Interfaces *[]string                      `yaml:"interfaces,omitempty"`
+	Parameters *NetworkBridgeParametersOutput `yaml:"parameters,omitempty"`
+
+	NetworkDeviceOutput `yaml:",inline"`
+}
+
+type NetworkVLAN struct {
+	NetworkDevice
+
+	VLANID types.Int64  `tfsdk:"vlan_id"`
+	Link   types.String `tfsdk:"link"`
+}
+
+type NetworkVLANOutput struct {
+	VLANID *int64  `yaml:"id,omitempty"`
+	Link   *string `yaml:"link,omitempty"`
+
+	NetworkDeviceOutput `yaml:",inline"`
+}
+
+type NetworkConfigModel struct {
+	Version   types.Int64        `tfsdk:"version"`
+	Renderer  types.String       `tfsdk:"renderer"`
+	Ethernets *[]NetworkEthernet `tfsdk:"ethernets"`
+	Bonds     *[]NetworkBond     `tfsdk:"bonds"`
+	Bridges   *[]NetworkBridge   `tfsdk:"bridges"`
+	VLANs     *[]NetworkVLAN     `tfsdk:"vlans"`
+}
+
+// NetworkConfigOutputModel
+// Devices are keyed by their ID, as in netplan
+type NetworkConfigOutputModel struct {
+	Version   int                              `yaml:"version"`
+	Renderer  *string                          `yaml:"renderer,omitempty"`
+	Ethernets map[string]NetworkEthernetOutput `yaml:"ethernets,omitempty"`
+	Bonds     map[string]NetworkBondOutput     `yaml:"bonds,omitempty"`
+	Bridges   map[string]NetworkBridgeOutput   `yaml:"bridges,omitempty"`
+	VLANs     map[string]NetworkVLANOutput     `yaml:"vlans,omitempty"`
+}
+
+// NetworkV1SubnetOutput
+// Addressing of an interface in version 1, one per DHCP protocol or static address
+type NetworkV1SubnetOutput struct {
+	Type           string    `yaml:"type"`
+	Address        *string   `yaml:"address,omitempty"`
+	Gateway        *string   `yaml:"gateway,omitempty"`
+	DNSNameservers *[]string `yaml:"dns_nameservers,omitempty"`
+	DNSSearch      *[]string `yaml:"dns_search,omitempty"`
+}
+
+type NetworkV1BondParametersOutput struct {
+	Mode               *string `yaml:"bond-mode,omitempty"`
+	LACPRate           *string `yaml:"bond-lacp-rate,omitempty"`
+	MIIMonitorInterval *int64  `yaml:"bond-miimon,omitempty"`
+	MinLinks           *int64  `yaml:"bond-min-links,omitempty"`
+	TransmitHashPolicy *string `yaml:"bond-xmit-hash-policy,omitempty"`
+	UpDelay            *int64  `yaml:"bond-updelay,omitempty"`
+	DownDelay          *int64  `yaml:"bond-downdelay,omitempty"`
+	Primary            *string `yaml:"bond-primary,omitempty"`
+}
+
+type NetworkV1BridgeParametersOutput struct {
+	AgeingTime   *int64 `yaml:"bridge_ageing,omitempty"`
+	Priority     *int64 `yaml:"bridge_bridgeprio,omitempty"`
+	ForwardDelay *int64 `yaml:"bridge_fd,omitempty"`
+	HelloTime    *int64 `yaml:"bridge_hello,omitempty"`
+	MaxAge       *int64 `yaml:"bridge_maxage,omitempty"`
+	STP          *bool  `yaml:"bridge_stp,omitempty"`
+}
+
+// NetworkV1ConfigOutput
+// Entry of version 1 config, `type` defines which of the keys are used
+type NetworkV1ConfigOutput struct {
+	Type             string                   `yaml:"type"`
+	Name             *string                  `yaml:"name,omitempty"`
+	MACAddress       *string                  `yaml:"mac_address,omitempty"`
+	MTU              *int64                   `yaml:"mtu,omitempty"`
+	AcceptRA         *bool                    `yaml:"accept-ra,omitempty"`
+	WakeOnLAN        *bool                    `yaml:"wakeonlan,omitempty"`
+	BondInterfaces   *[]string                `yaml:"bond_interfaces,omitempty"`
+	BridgeInterfaces *[]string                `yaml:"bridge_interfaces,omitempty"`
+	VLANLink         *string                  `yaml:"vlan_link,omitempty"`
+	VLANID           *int64                   `yaml:"vlan_id,omitempty"`
+	Params           any                      `yaml:"params,omitempty"`
+	Subnets          *[]NetworkV1SubnetOutput `yaml:"subnets,omitempty"`
+	Address          *[]string                `yaml:"address,omitempty"`
+	Search           *[]string                `yaml:"search,omitempty"`
+	Destination      *string                  `yaml:"destination,omitempty"`
+	Gateway          *string                  `yaml:"gateway,omitempty"`
+	Metric           *int64                   `yaml:"metric,omitempty"`
+}
+
+// NetworkV1OutputModel
+// @see https://cloudinit.readthedocs.io/en/latest/reference/network-config-format-v1.html
+type NetworkV1OutputModel struct {
+	Version int                     `yaml:"version"`
+	Config  []NetworkV1ConfigOutput `yaml:"config"`
+}
+
+// NetworkConfig
+// @see https://cloudinit.readthedocs.io/en/latest/reference/network-config-format-v2.html
+func NetworkConfig() CCModuleFlat {
+	return CCModuleFlat{
+		attributes: map[string]schema.Attribute{
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "Format of rendered network-config. Version `1` is understood by older cloud-init releases, blocks are converted into its entries, and anything it can't express is reported as an error. *Default*: `2`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.OneOf(1, 2),
+				},
+			},
+			"renderer": schema.StringAttribute{
+				MarkdownDescription: "Backend which applies the configuration, `networkd` or `NetworkManager`. *Default*: chosen by the distribution.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("networkd", "NetworkManager"),
+				},
+			},
+		},
+	}
+}
+
+// NetworkConfigBlock
+// @see https://cloudinit.readthedocs.io/en/latest/reference/network-config-format-v2.html
+func NetworkConfigBlock() CCModuleNested {
+	ethernet := networkDevice("Physical interface, either matched by **match** or named by **id**.")
+	ethernet.NestedObject.Attributes["set_name"] = schema.StringAttribute{
+		MarkdownDescription: "Rename the matched interface. Requires **match**.",
+		Optional:            true,
+		Validators: []validator.String{
+			stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("match")),
+		},
+	}
+	ethernet.NestedObject.Attributes["wakeonlan"] = schema.BoolAttribute{
+		MarkdownDescription: "Enable wake on LAN. *Default*: `false`.",
+		Optional:            true,
+	}
+	ethernet.NestedObject.Blocks["match"] = schema.SingleNestedBlock{
+		MarkdownDescription: "Select interfaces by their properties rather than by **id**. Every property set has to match.",
+		PlanModifiers: []planmodifier.Object{
+			utils.NullWhen(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Current interface name, shell wildcards are supported, e.g. `en*`.",
+				Optional:            true,
+			},
+			"macaddress": schema.StringAttribute{
+				MarkdownDescription: "Hardware address of the interface.",
+				Optional:            true,
+				Validators: []validator.String{
+					utils.MACAddress(),
+				},
+			},
+			"driver": schema.StringAttribute{
+				MarkdownDescription: "Kernel driver of the interface, shell wildcards are supported, e.g. `ixgbe`.",
+				Optional:            true,
+			},
+		},
+	}
+
+	bond := networkDevice("Bonded (aggregated) interface.")
+	bond.NestedObject.Attributes["interfaces"] = networkInterfaces("Devices (by **id**) to bond.")
+	bond.NestedObject.Blocks["parameters"] = schema.SingleNestedBlock{
+		MarkdownDescription: "Bonding parameters.",
+		PlanModifiers: []planmodifier.Object{
+			utils.NullWhen(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "Bonding mode. *Default*: `balance-rr`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"),
+				},
+			},
+			"lacp_rate": schema.StringAttribute{
+				MarkdownDescription: "How often LACPDUs are sent in `802.3ad` mode, `slow` (every 30 seconds) or `fast` (every second). *Default*: `slow`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("slow", "fast"),
+				},
+			},
+			"mii_monitor_interval": schema.Int64Attribute{
+				MarkdownDescription: "Interval of MII link monitoring in milliseconds, `0` disables it.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"min_links": schema.Int64Attribute{
+				MarkdownDescription: "Minimal number of links up to consider the bond up.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"transmit_hash_policy": schema.StringAttribute{
+				MarkdownDescription: "Hash policy to select a link in `balance-xor`, `802.3ad` and `balance-tlb` modes.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("layer2", "layer3+4", "layer2+3", "encap2+3", "encap3+4"),
+				},
+			},
+			"up_delay": schema.Int64Attribute{
+				MarkdownDescription: "Delay before enabling a link after it comes up, in milliseconds.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"down_delay": schema.Int64Attribute{
+				MarkdownDescription: "Delay before disabling a link after it goes down, in milliseconds.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"primary": schema.StringAttribute{
+				MarkdownDescription: "Device (by **id**) preferred in `active-backup`, `balance-alb` and `balance-tlb` modes.",
+				Optional:            true,
+			},
+		},
+	}
+
+	bridge := networkDevice("Bridge interface.")
+	bridge.NestedObject.Attributes["interfaces"] = networkInterfaces("Devices (by **id**) to add to the bridge.")
+	bridge.NestedObject.Blocks["parameters"] = schema.SingleNestedBlock{
+		MarkdownDescription: "Bridge parameters.",
+		PlanModifiers: []planmodifier.Object{
+			utils.NullWhen(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"ageing_time": schema.Int64Attribute{
+				MarkdownDescription: "Time in seconds to keep a MAC address in the forwarding database.",
+				Optional:            true,
+			},
+			"priority": schema.Int64Attribute{
+				MarkdownDescription: "Priority of the bridge, used to elect the root bridge. Lower wins.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+			},
+			"forward_delay": schema.Int64Attribute{
+				MarkdownDescription: "Time in seconds to spend in listening and learning states.",
+				Optional:            true,
+			},
+			"hello_time": schema.Int64Attribute{
+				MarkdownDescription: "Interval in seconds between hello packets.",
+				Optional:            true,
+			},
+			"max_age": schema.Int64Attribute{
+				MarkdownDescription: "Maximal age in seconds of a hello packet.",
+				Optional:            true,
+			},
+			"stp": schema.BoolAttribute{
+				MarkdownDescription: "Enable Spanning Tree Protocol. *Default*: `true`.",
+				Optional:            true,
+			},
+		},
+	}
+
+	vlan := networkDevice("VLAN interface.")
+	vlan.NestedObject.Attributes["vlan_id"] = schema.Int64Attribute{
+		MarkdownDescription: "VLAN ID.",
+		Required:            true,
+		Validators: []validator.Int64{
+			int64validator.Between(0, 4094),
+		},
+	}
+	vlan.NestedObject.Attributes["link"] = schema.StringAttribute{
+		MarkdownDescription: "Device (by **id**) the VLAN is created on.",
+		Required:            true,
+	}
+
+	return CCModuleNested{
+		block: map[string]schema.Block{
+			"ethernets": ethernet,
+			"bonds":     bond,
+			"bridges":   bridge,
+			"vlans":     vlan,
+		},
+	}
+}
+
+// networkDevice
+// Block of a device with properties shared by every kind of device
+func networkDevice(description string) schema.ListNestedBlock {
+	attributes := map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "Device ID, the interface name unless **match** is used. Other devices refer to it, e.g. in **interfaces** or **link**.",
+			Required:            true,
+		},
+		"dhcp4": schema.BoolAttribute{
+			MarkdownDescription: "Enable DHCP for IPv4. *Default*: `false`.",
+			Optional:            true,
+		},
+		"dhcp6": schema.BoolAttribute{
+			MarkdownDescription: "Enable DHCP for IPv6. *Default*: `false`.",
+			Optional:            true,
+		},
+		"accept_ra": schema.BoolAttribute{
+			MarkdownDescription: "Accept IPv6 Router Advertisements. *Default*: kernel default.",
+			Optional:            true,
+		},
+		"addresses": schema.ListAttribute{
+			ElementType:         types.StringType,
+			MarkdownDescription: "Static addresses with prefix length, e.g. `192.168.1.10/24` or `2001:db8::10/64`.",
+			Optional:            true,
+			Validators: []validator.List{
+				listvalidator.ValueStringsAre(utils.CIDR()),
+			},
+		},
+		"gateway4": schema.StringAttribute{
+			MarkdownDescription: "Default IPv4 gateway. Deprecated by netplan in favour of **routes** with `to = \"default\"`.",
+			Optional:            true,
+			Validators: []validator.String{
+				utils.IPv4Address(),
+			},
+		},
+		"gateway6": schema.StringAttribute{
+			MarkdownDescription: "Default IPv6 gateway. Deprecated by netplan in favour of **routes** with `to = \"default\"`.",
+			Optional:            true,
+			Validators: []validator.String{
+				utils.IPv6Address(),
+			},
+		},
+		"mtu": schema.Int64Attribute{
+			MarkdownDescription: "Maximum transmission unit in bytes.",
+			Optional:            true,
+			Validators: []validator.Int64{
+				int64validator.AtLeast(68),
+			},
+		},
+		"macaddress": schema.StringAttribute{
+			MarkdownDescription: "Set hardware address of the device.",
+			Optional:            true,
+			Validators: []validator.String{
+				utils.MACAddress(),
+			},
+		},
+		"optional": schema.BoolAttribute{
+			MarkdownDescription: "Do not wait for the device to come up during boot. *Default*: `false`.",
+			Optional:            true,
+		},
+	}
+
+	blocks := map[string]schema.Block{
+		"nameservers": schema.SingleNestedBlock{
+			MarkdownDescription: "DNS servers and search domains.",
+			PlanModifiers: []planmodifier.Object{
+				utils.NullWhen(),
+			},
+			Attributes: map[string]schema.Attribute{
+				"addresses": schema.ListAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "Addresses of DNS servers.",
+					Optional:            true,
+					Validators: []validator.List{
+						listvalidator.ValueStringsAre(utils.IPAddress()),
+					},
+				},
+				"search": schema.ListAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "Search domains.",
+					Optional:            true,
+				},
+			},
+		},
+		"routes": schema.ListNestedBlock{
+			MarkdownDescription: "Static routes.",
+			NestedObject: schema.NestedBlockObject{
+				Attributes: map[string]schema.Attribute{
+					"to": schema.StringAttribute{
+						MarkdownDescription: "Destination, an address with prefix length, a single address or `default`.",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.Any(
+								utils.CIDR(),
+								utils.IPAddress(),
+								stringvalidator.OneOf("default"),
+							),
+						},
+					},
+					"via": schema.StringAttribute{
+						MarkdownDescription: "Gateway address.",
+						Optional:            true,
+						Validators: []validator.String{
+							utils.IPAddress(),
+						},
+					},
+					"metric": schema.Int64Attribute{
+						MarkdownDescription: "Metric of the route, lower is preferred.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"table": schema.Int64Attribute{
+						MarkdownDescription: "Routing table to add the route to.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"on_link": schema.BoolAttribute{
+						MarkdownDescription: "Gateway is directly reachable through the device, even if it is outside of its subnets. *Default*: `false`.",
+						Optional:            true,
+					},
+					"type": schema.StringAttribute{
+						MarkdownDescription: "Type of the route. *Default*: `unicast`.",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf("unicast", "anycast", "blackhole", "broadcast", "local", "multicast", "nat", "prohibit", "throw", "unreachable", "xresolve"),
+						},
+					},
+				},
+			},
+		},
+		"routing_policy": schema.ListNestedBlock{
+			MarkdownDescription: "Policy routing rules.",
+			NestedObject: schema.NestedBlockObject{
+				Attributes: map[string]schema.Attribute{
+					"from": schema.StringAttribute{
+						MarkdownDescription: "Source addresses with prefix length.",
+						Optional:            true,
+						Validators: []validator.String{
+							utils.CIDR(),
+						},
+					},
+					"to": schema.StringAttribute{
+						MarkdownDescription: "Destination addresses with prefix length.",
+						Optional:            true,
+						Validators: []validator.String{
+							utils.CIDR(),
+						},
+					},
+					"table": schema.Int64Attribute{
+						MarkdownDescription: "Routing table to look up matching packets in.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"priority": schema.Int64Attribute{
+						MarkdownDescription: "Priority of the rule, lower is evaluated first.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"mark": schema.Int64Attribute{
+						MarkdownDescription: "Firewall mark of matching packets.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"type_of_service": schema.Int64Attribute{
+						MarkdownDescription: "Type of service of matching packets.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.Between(0, 255),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return schema.ListNestedBlock{
+		MarkdownDescription: description,
+		NestedObject: schema.NestedBlockObject{
+			Attributes: attributes,
+			Blocks:     blocks,
+		},
+	}
+}
+
+// networkInterfaces
+// List of devices which are members of a bond or a bridge
+func networkInterfaces(description string) schema.ListAttribute {
+	return schema.ListAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: description,
+		Optional:            true,
+	}
+}
@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -17,11 +16,11 @@ type NTPConfig struct {
 }
 
 type NTPConfigOutput struct {
-	Confpath    string    `yaml:"confpath,omitempty"`
-	CheckExe    string    `yaml:"check_exe,omitempty"`
+	Confpath    *string   `yaml:"confpath,omitempty"`
+	CheckExe    *string   `yaml:"check_exe,omitempty"`
 	Packages    *[]string `yaml:"packages,omitempty"`
-	ServiceName string    `yaml:"service_name,omitempty"`
-	Template    string    `yaml:"template,omitempty"`
+	ServiceName *string   `yaml:"service_name,omitempty"`
+	Template    *string   `yaml:"template,omitempty"`
 }
 
 type NTP struct {
@@ -39,7 +38,7 @@ type NTPOutput struct {
 	Servers   *[]string        `yaml:"servers,omitempty"`
 	Peers     *[]string        `yaml:"peers,omitempty"`
 	Allow     *[]string        `yaml:"allow,omitempty"`
-	NTPClient string           `yaml:"ntp_client,omitempty"`
+	NTPClient *string          `yaml:"ntp_client,omitempty"`
 	Enabled   *bool            `yaml:"enabled,omitempty"`
 	Config    *NTPConfigOutput `yaml:"config,omitempty"`
 }
@@ -59,7 +58,7 @@ func NTPBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"ntp": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("ntp")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 Handle Network Time Protocol (NTP) configuration. If ntp is not installed on the system and NTP configuration is specified, ntp will be installed.
@@ -105,7 +104,7 @@ If no NTP servers or pools are provided, 4 pools will be used in the format:
 				Blocks: map[string]schema.Block{
 					"config": schema.SingleNestedBlock{
 						PlanModifiers: []planmodifier.Object{
-							utils.NullWhen(path.Root("config")),
+							utils.NullWhen(),
 						},
 						Attributes: map[string]schema.Attribute{
 							"confpath": schema.StringAttribute{
@@ -3,7 +3,6 @@ package ccmodules
 import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -18,8 +17,8 @@ type PhoneHome struct {
 }
 
 type PhoneHomeOutput struct {
-	URL   string    `yaml:"url,omitempty"`
-	Tries int       `yaml:"tries,omitempty"`
+	URL   *string   `yaml:"url,omitempty"`
+	Tries *int64    `yaml:"tries,omitempty"`
 	Post  *[]string `yaml:"post,omitempty"`
 }
 
@@ -38,7 +37,7 @@ func PhoneHomeBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"phone_home": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("phone_home")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module can be used to post data to a remote host after boot is complete.
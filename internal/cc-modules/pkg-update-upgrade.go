@@ -14,9 +14,9 @@ type PkgUpdateUpgradeModel struct {
 }
 
 type PkgUpdateUpgradeOutputModel struct {
-	PackageUpdate           bool `yaml:"package_update,omitempty"`
-	PackageUpgrade          bool `yaml:"package_upgrade,omitempty"`
-	PackageRebootIfRequired bool `yaml:"package_reboot_if_required,omitempty"`
+	PackageUpdate           *bool `yaml:"package_update,omitempty"`
+	PackageUpgrade          *bool `yaml:"package_upgrade,omitempty"`
+	PackageRebootIfRequired *bool `yaml:"package_reboot_if_required,omitempty"`
 
 	Packages *[]string `yaml:"packages,omitempty"`
 }
@@ -23,11 +23,11 @@ type PowerState struct {
 }
 
 type PowerStateOutput struct {
-	Delay     any    `yaml:"delay,omitempty"`
-	Mode      string `yaml:"mode,omitempty"`
-	Message   string `yaml:"message,omitempty"`
-	Timeout   int64  `yaml:"timeout,omitempty"`
-	Condition any    `yaml:"condition,omitempty"`
+	Delay     any     `yaml:"delay,omitempty"`
+	Mode      *string `yaml:"mode,omitempty"`
+	Message   *string `yaml:"message,omitempty"`
+	Timeout   *int64  `yaml:"timeout,omitempty"`
+	Condition any     `yaml:"condition,omitempty"`
 }
 
 type PowerStateModel struct {
@@ -44,7 +44,7 @@ func PowerStateChangeBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"power_state": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("power_state")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module handles shutdown/reboot after all config modules have been run. By default it will take no action, and the system will keep running unless a package installation/upgrade requires a system reboot (e.g. installing a new kernel) and package_reboot_if_required is true.
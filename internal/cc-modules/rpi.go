@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -14,8 +13,8 @@ type RPISerial struct {
 }
 
 type RPISerialOutput struct {
-	Console  bool `yaml:"console,omitempty"`
-	Hardware bool `yaml:"hardware,omitempty"`
+	Console  *bool `yaml:"console,omitempty"`
+	Hardware *bool `yaml:"hardware,omitempty"`
 }
 
 type RPIInterface struct {
@@ -28,12 +27,12 @@ type RPIInterface struct {
 }
 
 type RPIInterfaceOutput struct {
-	SPI        bool             `yaml:"spi,omitempty"`
-	I2C        bool             `yaml:"i2c,omitempty"`
-	SSH        bool             `yaml:"ssh,omitempty"`
+	SPI        *bool            `yaml:"spi,omitempty"`
+	I2C        *bool            `yaml:"i2c,omitempty"`
+	SSH        *bool            `yaml:"ssh,omitempty"`
 	Serial     *RPISerialOutput `yaml:"serial,omitempty"`
-	Onewire    bool             `yaml:"onewire,omitempty"`
-	RemoteGPIO bool             `yaml:"remote_gpio,omitempty"`
+	Onewire    *bool            `yaml:"onewire,omitempty"`
+	RemoteGPIO *bool            `yaml:"remote_gpio,omitempty"`
 }
 
 type RPI struct {
@@ -43,7 +42,7 @@ type RPI struct {
 
 type RPIOutput struct {
 	Interfaces       *RPIInterfaceOutput `yaml:"interfaces,omitempty"`
-	EnableRPIConnect bool                `yaml:"enable_rpi_connect,omitempty"`
+	EnableRPIConnect *bool               `yaml:"enable_rpi_connect,omitempty"`
 }
 
 type RPIModel struct {
@@ -61,7 +60,7 @@ func RPIBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"rpi": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("rpi")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module handles ARM interface configuration for Raspberry Pi.
@@ -79,12 +78,12 @@ This only works on Raspberry Pi OS (bookworm and later).
 				Blocks: map[string]schema.Block{
 					"interfaces": schema.SingleNestedBlock{
 						PlanModifiers: []planmodifier.Object{
-							utils.NullWhen(path.Root("interfaces")),
+							utils.NullWhen(),
 						},
 						Blocks: map[string]schema.Block{
 							"serial": schema.SingleNestedBlock{
 								PlanModifiers: []planmodifier.Object{
-									utils.NullWhen(path.Root("serial")),
+									utils.NullWhen(),
 								},
 								Attributes: map[string]schema.Attribute{
 									"console": schema.BoolAttribute{
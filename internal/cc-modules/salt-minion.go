@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -14,19 +13,20 @@ type SaltMinion struct {
 	ConfigDir   types.String `tfsdk:"config_dir"`
 	// Conf        *types.Dynamic `tfsdk:"conf"`
 	// Grains      *types.Dynamic `tfsdk:"grains"`
-	PublicKey  types.String `tfsdk:"public_key"`
-	PrivateKey types.String `tfsdk:"private_key"`
-	PkiDir     types.String `tfsdk:"pki_dir"`
+	PublicKey    types.String `tfsdk:"public_key"`
+	PrivateKey   types.String `tfsdk:"private_key"`
+	PrivateKeyWO types.String `tfsdk:"private_key_wo"`
+	PkiDir       types.String `tfsdk:"pki_dir"`
 }
 type SaltMinionOutput struct {
-	PkgName     string `yaml:"pkg_name,omitempty"`
-	ServiceName string `yaml:"service_name,omitempty"`
-	ConfigDir   string `yaml:"config_dir,omitempty"`
+	PkgName     *string `yaml:"pkg_name,omitempty"`
+	ServiceName *string `yaml:"service_name,omitempty"`
+	ConfigDir   *string `yaml:"config_dir,omitempty"`
 	// Conf        *map[string]any `yaml:"conf,omitempty"`
 	// Grains      *map[string]any `yaml:"grains,omitempty"`
-	PublicKey  string `yaml:"public_key,omitempty"`
-	PrivateKey string `yaml:"private_key,omitempty"`
-	PkiDir     string `yaml:"pki_dir,omitempty"`
+	PublicKey  *string `yaml:"public_key,omitempty"`
+	PrivateKey *string `yaml:"private_key,omitempty"`
+	PkiDir     *string `yaml:"pki_dir,omitempty"`
 }
 
 type SaltMinionModel struct {
@@ -44,7 +44,7 @@ func SaltMinionBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"salt_minion": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("salt_minion")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module installs, configures and starts Salt Minion. If the salt_minion key is present in the config parts, then Salt Minion will be installed and started.
@@ -86,7 +86,9 @@ Salt keys can be manually generated by salt-key --gen-keys=GEN_KEYS, where GEN_K
 					"private_key": schema.StringAttribute{
 						MarkdownDescription: "Private key to be used by salt minion.",
 						Optional:            true,
+						Sensitive:           true,
 					},
+					"private_key_wo": writeOnly("private_key"),
 					"pki_dir": schema.StringAttribute{
 						MarkdownDescription: "Directory to write key files. Default: `config_dir/pki/minion`.",
 						Optional:            true,
@@ -0,0 +1,108 @@
+package ccmodules
+
+import (
+	"fmt"
+	"reflect"
+
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// schemaTarget
+// Schema package, which resource attributes and blocks are converted into. Its types have the same names
+// and a subset of fields of `resource/schema` ones: plan modifiers, defaults and write-only flags are dropped
+type schemaTarget struct {
+	// persisted, whether values of write-only attributes end up in state
+	persisted bool
+	types     map[string]reflect.Type
+}
+
+var dataSourceTarget = newSchemaTarget(true,
+	dsschema.StringAttribute{},
+	dsschema.BoolAttribute{},
+	dsschema.Int32Attribute{},
+	dsschema.Int64Attribute{},
+	dsschema.ListAttribute{},
+	dsschema.MapAttribute{},
+	dsschema.DynamicAttribute{},
+	dsschema.SingleNestedBlock{},
+	dsschema.ListNestedBlock{},
+)
+
+var ephemeralTarget = newSchemaTarget(false,
+	ephemeralschema.StringAttribute{},
+	ephemeralschema.BoolAttribute{},
+	ephemeralschema.Int32Attribute{},
+	ephemeralschema.Int64Attribute{},
+	ephemeralschema.ListAttribute{},
+	ephemeralschema.MapAttribute{},
+	ephemeralschema.DynamicAttribute{},
+	ephemeralschema.SingleNestedBlock{},
+	ephemeralschema.ListNestedBlock{},
+)
+
+func newSchemaTarget(persisted bool, values ...any) schemaTarget {
+	target := schemaTarget{persisted: persisted, types: make(map[string]reflect.Type, len(values))}
+
+	for _, value := range values {
+		target.types[reflect.TypeOf(value).Name()] = reflect.TypeOf(value)
+	}
+
+	return target
+}
+
+// convertSchema
+// Converts resource attributes or blocks, e.g. `map[string]schema.Attribute`, into `T` of the target package
+func convertSchema[T any](target schemaTarget, source any) T {
+	return target.convert(reflect.ValueOf(source), reflect.TypeFor[T]()).Interface().(T)
+}
+
+func (t schemaTarget) convert(source reflect.Value, target reflect.Type) reflect.Value {
+	if source.Kind() == reflect.Interface {
+		source = source.Elem()
+	}
+
+	switch target.Kind() {
+	case reflect.Map:
+		result := reflect.MakeMapWithSize(target, source.Len())
+
+		for entry := source.MapRange(); entry.Next(); {
+			value := entry.Value()
+
+			if attribute, ok := value.Interface().(schema.StringAttribute); ok {
+				attribute.MarkdownDescription = writeOnlyDescription(entry.Key().String(), attribute, t.persisted)
+				value = reflect.ValueOf(attribute)
+			}
+
+			result.SetMapIndex(entry.Key(), t.convert(value, target.Elem()))
+		}
+
+		return result
+	case reflect.Interface:
+		concrete, ok := t.types[source.Type().Name()]
+		if !ok {
+			// NOTE: every attribute and block type used by modules must be listed in targets
+			panic(fmt.Sprintf("unsupported schema type %s", source.Type()))
+		}
+
+		return t.convert(source, concrete)
+	case reflect.Struct:
+		result := reflect.New(target).Elem()
+
+		for i := range target.NumField() {
+			field := target.Field(i)
+			value := source.FieldByName(field.Name)
+
+			if value.Type() == field.Type {
+				result.Field(i).Set(value)
+			} else {
+				result.Field(i).Set(t.convert(value, field.Type))
+			}
+		}
+
+		return result
+	}
+
+	panic(fmt.Sprintf("unsupported schema type %s", target))
+}
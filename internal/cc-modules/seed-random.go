@@ -2,7 +2,6 @@ package ccmodules
 
 import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -19,11 +18,11 @@ type RandomSeed struct {
 }
 
 type RandomSeedOutput struct {
-	File            string    `yaml:"file,omitempty"`
-	Data            string    `yaml:"data,omitempty"`
-	Encoding        string    `yaml:"encoding,omitempty"`
+	File            *string   `yaml:"file,omitempty"`
+	Data            *string   `yaml:"data,omitempty"`
+	Encoding        *string   `yaml:"encoding,omitempty"`
 	Command         *[]string `yaml:"command,omitempty"`
-	CommandRequired bool      `yaml:"command_required,omitempty"`
+	CommandRequired *bool     `yaml:"command_required,omitempty"`
 }
 
 type SeedRandomModel struct {
@@ -41,7 +40,7 @@ func SeedRandomBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"random_seed": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("random_seed")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 All cloud instances started from the same image will produce similar data when they are first booted as they are all starting with the same seed for the kernel’s entropy keyring. To avoid this, random seed data can be provided to the instance, either as a string or by specifying a command to run to generate the data.
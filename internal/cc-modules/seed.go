@@ -0,0 +1,51 @@
+package ccmodules
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SeedModel struct {
+	UserData       types.String `tfsdk:"user_data"`
+	MetaData       types.String `tfsdk:"meta_data"`
+	NetworkConfig  types.String `tfsdk:"network_config"`
+	VendorData     types.String `tfsdk:"vendor_data"`
+	FilePermission types.String `tfsdk:"file_permission"`
+}
+
+// Seed
+// Not a module, files of NoCloud seed
+// @see https://cloudinit.readthedocs.io/en/latest/reference/datasources/nocloud.html
+func Seed() CCModuleFlat {
+	return CCModuleFlat{
+		attributes: map[string]schema.Attribute{
+			"user_data": schema.StringAttribute{
+				MarkdownDescription: "Content of `user-data`, e.g. `content` of `cloud-config` or `cloud-config_multipart`.",
+				Required:            true,
+			},
+			"meta_data": schema.StringAttribute{
+				MarkdownDescription: "Content of `meta-data`, e.g. `content` of `cloud-config_meta_data`. cloud-init requires the file, so it is written empty, when not set.",
+				Optional:            true,
+			},
+			"network_config": schema.StringAttribute{
+				MarkdownDescription: "Content of `network-config`, e.g. `content` of `cloud-config_network`.",
+				Optional:            true,
+			},
+			"vendor_data": schema.StringAttribute{
+				MarkdownDescription: "Content of `vendor-data`.",
+				Optional:            true,
+			},
+			"file_permission": schema.StringAttribute{
+				MarkdownDescription: "Permissions of written files in octal notation, e.g. `0644`. *Default*: `0600`, as user-data often contains secrets.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^0?[0-7]{3}$`), "must be permissions in octal notation, e.g. `0644`"),
+				},
+			},
+		},
+	}
+}
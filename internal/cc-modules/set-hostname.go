@@ -15,11 +15,11 @@ type SetHostnameModel struct {
 }
 
 type SetHostnameOutputModel struct {
-	Hostname string `yaml:"hostname,omitempty"`
-	FQDN     string `yaml:"fqdn,omitempty"`
+	Hostname *string `yaml:"hostname,omitempty"`
+	FQDN     *string `yaml:"fqdn,omitempty"`
 
-	PreferFQDNOverHostname bool  `yaml:"prefer_fqdn_over_hostname,omitempty"`
-	PreserveHostname       bool  `yaml:"preserve_hostname,omitempty"`
+	PreferFQDNOverHostname *bool `yaml:"prefer_fqdn_over_hostname,omitempty"`
+	PreserveHostname       *bool `yaml:"preserve_hostname,omitempty"`
 	CreateHostnameFile     *bool `yaml:"create_hostname_file,omitempty"` // WARN: Pointer, because default value is `true`
 }
 
@@ -2,7 +2,6 @@ package ccmodules
 
 import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -11,20 +10,22 @@ import (
 )
 
 type ChangePasswordUser struct {
-	Name     types.String `tfsdk:"name"`
-	Password types.String `tfsdk:"password"`
-	Type     types.String `tfsdk:"type"`
+	Name       types.String `tfsdk:"name"`
+	Password   types.String `tfsdk:"password"`
+	PasswordWO types.String `tfsdk:"password_wo"`
+	Type       types.String `tfsdk:"type"`
 }
 
 type ChangePasswordUserOutput struct {
-	Name     string `yaml:"name,omitempty"`
-	Password string `yaml:"password,omitempty"`
-	Type     string `yaml:"type,omitempty"`
+	Name     *string `yaml:"name,omitempty"`
+	Password *string `yaml:"password,omitempty"`
+	Type     *string `yaml:"type,omitempty"`
 }
 
 type ChangePassword struct {
-	Users  *[]ChangePasswordUser `tfsdk:"users"`
-	Expire types.Bool            `tfsdk:"expire"`
+	Users         *[]ChangePasswordUser `tfsdk:"users"`
+	Expire        types.Bool            `tfsdk:"expire"`
+	HashPlaintext types.Bool            `tfsdk:"hash_plaintext"`
 }
 
 type ChangePasswordOutput struct {
@@ -67,7 +68,7 @@ func SetPasswordsBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"chpasswd": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("chpasswd")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 The chpasswd config key accepts a dictionary containing either (or both) of users and expire.
@@ -80,6 +81,10 @@ The chpasswd config key accepts a dictionary containing either (or both) of user
 						MarkdownDescription: "Whether to expire all user passwords such that a password will need to be reset on the user’s next login. Default: `true`.",
 						Optional:            true,
 					},
+					"hash_plaintext": schema.BoolAttribute{
+						MarkdownDescription: "Render passwords of users with `type = \"text\"` as salted SHA-512-crypt hashes with `type = \"hash\"`, so the clear text never appears in the user-data. The salt is derived from `hash_salt` and the user's **name**, so the hash stays the same across plans and when users are reordered. Default: `false`.",
+						Optional:            true,
+					},
 				},
 				Blocks: map[string]schema.Block{
 					"users": schema.ListNestedBlock{
@@ -93,7 +98,9 @@ The chpasswd config key accepts a dictionary containing either (or both) of user
 								"password": schema.StringAttribute{ // TODO: Do proper validation
 									MarkdownDescription: "User's password",
 									Optional:            true,
+									Sensitive:           true,
 								},
+								"password_wo": writeOnly("password"),
 								"type": schema.StringAttribute{
 									MarkdownDescription: "The *type* key has a default value of 'hash', and may alternatively be set to 'text' or 'RANDOM'.",
 									Optional:            true,
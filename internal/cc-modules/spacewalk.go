@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -15,9 +14,9 @@ type Spacewalk struct {
 }
 
 type SpacewalkOutput struct {
-	Server        string `yaml:"server,omitempty"`
-	Proxy         string `yaml:"proxy,omitempty"`
-	ActivationKey string `yaml:"activation_key,omitempty"`
+	Server        *string `yaml:"server,omitempty"`
+	Proxy         *string `yaml:"proxy,omitempty"`
+	ActivationKey *string `yaml:"activation_key,omitempty"`
 }
 
 type SpacewalkModel struct {
@@ -35,7 +34,7 @@ func SpacewalkBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"spacewalk": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("spacewalk")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 This module installs Spacewalk and applies basic configuration. 
@@ -0,0 +1,35 @@
+package ccmodules
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const TemplateJinja = "jinja"
+
+type TemplateModel struct {
+	Template types.String `tfsdk:"template"`
+}
+
+// Template
+// Not a module, cloud-init renders the document as a template before reading it
+// @see https://cloudinit.readthedocs.io/en/latest/explanation/instancedata.html#using-instance-data
+func Template() CCModuleFlat {
+	return CCModuleFlat{
+		attributes: map[string]schema.Attribute{
+			"template": schema.StringAttribute{
+				MarkdownDescription: `
+Renders the document as a template on the instance. With ` + "`jinja`" + `, content starts with ` + "`## template: jinja`" + ` header, and string attributes may reference instance-data, e.g. ` + "`hostname = \"{{ v1.local_hostname }}\"`" + `.
+
+Values with jinja expressions are written as YAML literal blocks, so expressions are never quoted or escaped. References to ` + "`v1`" + ` keys and top-level variables, e.g. ` + "`{{ region }}`" + `, are checked against documented instance-data keys. Variables set by ` + "`for`" + ` and ` + "`set`" + ` statements are allowed anywhere in the template, text and ` + "`raw`" + ` blocks are not checked.
+        `,
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(TemplateJinja),
+				},
+			},
+		},
+	}
+}
@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -13,7 +12,7 @@ type Autoinstall struct {
 }
 
 type AutoinstallOutput struct {
-	Version int32 `yaml:"version,omitempty"`
+	Version *int32 `yaml:"version,omitempty"`
 }
 
 type UbuntuAutoinstallModel struct {
@@ -30,7 +29,7 @@ func UbuntuAutoinstallBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"autoinstall": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("autoinstall")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 **Cloud-init ignores this key and its values. It is used by Subiquity, the Ubuntu Autoinstaller. See: https://ubuntu.com/server/docs/install/autoinstall-reference.**
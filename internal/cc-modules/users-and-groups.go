@@ -24,6 +24,9 @@ type User struct {
 	Passwd            types.String `tfsdk:"passwd"`
 	HashedPasswd      types.String `tfsdk:"hashed_passwd"`
 	PlainTextPasswd   types.String `tfsdk:"plain_text_passwd"`
+	HashedPasswdWO    types.String `tfsdk:"hashed_passwd_wo"`
+	PlainTextPasswdWO types.String `tfsdk:"plain_text_passwd_wo"`
+	HashPlaintext     types.Bool   `tfsdk:"hash_plaintext"`
 	CreateGroups      types.Bool   `tfsdk:"create_groups"`
 	PrimaryGroup      types.String `tfsdk:"primary_group"`
 	SELinuxUser       types.String `tfsdk:"selinux_user"`
@@ -39,28 +42,28 @@ type User struct {
 }
 
 type UserOutput struct {
-	Name              string    `yaml:"name,omitempty"`
+	Name              *string   `yaml:"name,omitempty"`
 	Doas              *[]string `yaml:"doas,omitempty"`
-	ExpireDate        string    `yaml:"expiredate,omitempty"`
-	Gecos             string    `yaml:"gecos,omitempty"`
-	HomeDir           string    `yaml:"homedir,omitempty"`
-	Inactive          string    `yaml:"inactive,omitempty"`
+	ExpireDate        *string   `yaml:"expiredate,omitempty"`
+	Gecos             *string   `yaml:"gecos,omitempty"`
+	HomeDir           *string   `yaml:"homedir,omitempty"`
+	Inactive          *string   `yaml:"inactive,omitempty"`
 	LockPassword      *bool     `yaml:"lock_passwd,omitempty"`
-	NoCreateHome      bool      `yaml:"no_create_home,omitempty"`
-	NoLogInit         bool      `yaml:"no_log_init,omitempty"`
-	NoUserGroup       bool      `yaml:"no_user_group,omitempty"`
-	Passwd            string    `yaml:"passwd,omitempty"`
-	HashedPasswd      string    `yaml:"hashed_passwd,omitempty"`
-	PlainTextPasswd   string    `yaml:"plain_text_passwd,omitempty"`
-	CreateGroups      bool      `yaml:"create_groups,omitempty"`
-	PrimaryGroup      string    `yaml:"primary_group,omitempty"`
-	SELinuxUser       string    `yaml:"selinux_user,omitempty"`
-	Shell             string    `yaml:"shell,omitempty"`
-	SnapUser          string    `yaml:"snapuser,omitempty"`
+	NoCreateHome      *bool     `yaml:"no_create_home,omitempty"`
+	NoLogInit         *bool     `yaml:"no_log_init,omitempty"`
+	NoUserGroup       *bool     `yaml:"no_user_group,omitempty"`
+	Passwd            *string   `yaml:"passwd,omitempty"`
+	HashedPasswd      *string   `yaml:"hashed_passwd,omitempty"`
+	PlainTextPasswd   *string   `yaml:"plain_text_passwd,omitempty"`
+	CreateGroups      *bool     `yaml:"create_groups,omitempty"`
+	PrimaryGroup      *string   `yaml:"primary_group,omitempty"`
+	SELinuxUser       *string   `yaml:"selinux_user,omitempty"`
+	Shell             *string   `yaml:"shell,omitempty"`
+	SnapUser          *string   `yaml:"snapuser,omitempty"`
 	SSHAuthorizedKeys *[]string `yaml:"ssh_authorized_keys,omitempty"`
 	SSHImportId       *[]string `yaml:"ssh_import_id,omitempty"`
-	SSHRedirectUser   bool      `yaml:"ssh_redirect_user,omitempty"`
-	System            bool      `yaml:"system,omitempty"`
+	SSHRedirectUser   *bool     `yaml:"ssh_redirect_user,omitempty"`
+	System            *bool     `yaml:"system,omitempty"`
 	UID               *int32    `yaml:"uid,omitempty"`
 	Sudo              *[]string `yaml:"sudo,omitempty"`
 	Groups            *[]string `yaml:"groups,omitempty"`
@@ -170,6 +173,16 @@ func UsersAndGroupsBlock() CCModuleNested {
 			Optional:            true,
 			Sensitive:           true,
 		},
+		"hashed_passwd_wo":     writeOnly("hashed_passwd"),
+		"plain_text_passwd_wo": writeOnly("plain_text_passwd"),
+		"hash_plaintext": schema.BoolAttribute{
+			MarkdownDescription: "Render **plain_text_passwd** (or **plain_text_passwd_wo**) as a salted SHA-512-crypt **hashed_passwd**, so the clear text never appears in the user-data. The salt is derived from `hash_salt` and the user's **name**, so the hash stays the same across plans and when users are reordered. Can not be combined with **hashed_passwd** or **hashed_passwd_wo**. Default: `false`.",
+			Optional:            true,
+			Validators: []validator.Bool{
+				boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("hashed_passwd")),
+				boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("hashed_passwd_wo")),
+			},
+		},
 		"create_groups": schema.BoolAttribute{ // TODO: True is default value
 			MarkdownDescription: "Boolean set `false` to disable creation of specified user groups. Default: `true`.",
 			Optional:            true,
@@ -214,7 +227,7 @@ func UsersAndGroupsBlock() CCModuleNested {
 				MarkdownDescription: "The user dictionary values override the `default_user` configuration from `/etc/cloud/cloud.cfg`. The user dictionary keys supported for the `default_user` are the same as the users schema.",
 				Attributes:          userAttributes,
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("user")),
+					utils.NullWhen(),
 				},
 			},
 			"users": schema.ListNestedBlock{
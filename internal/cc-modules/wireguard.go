@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -20,9 +19,9 @@ type Wireguard struct {
 }
 
 type InterfaceOutput struct {
-	Name       string `yaml:"name,omitempty"`
-	ConfigPath string `yaml:"config_path,omitempty"`
-	Content    string `yaml:"content,omitempty"`
+	Name       *string `yaml:"name,omitempty"`
+	ConfigPath *string `yaml:"config_path,omitempty"`
+	Content    *string `yaml:"content,omitempty"`
 }
 
 type WireguardOutput struct {
@@ -45,7 +44,7 @@ func WireguardBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"wireguard": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("wireguard")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 The WireGuard module provides a dynamic interface for configuring WireGuard (as a peer or server) in a straightforward way.
@@ -13,7 +13,7 @@ type WriteFileSource struct {
 }
 
 type WriteFileSourceOutput struct {
-	URI     string             `yaml:"uri,omitempty"`
+	URI     *string            `yaml:"uri,omitempty"`
 	Headers *map[string]string `yaml:"headers,omitempty"`
 }
 
@@ -29,13 +29,13 @@ type WriteFile struct {
 }
 
 type WriteFileOutput struct {
-	Path        string                 `yaml:"path,omitempty"`
-	Content     string                 `yaml:"content,omitempty"`
-	Owner       string                 `yaml:"owner,omitempty"`
-	Permissions string                 `yaml:"permissions,omitempty"`
-	Encoding    string                 `yaml:"encoding,omitempty"`
-	Append      bool                   `yaml:"append,omitempty"`
-	Defer       bool                   `yaml:"defer,omitempty"`
+	Path        *string                `yaml:"path,omitempty"`
+	Content     *string                `yaml:"content,omitempty"`
+	Owner       *string                `yaml:"owner,omitempty"`
+	Permissions *string                `yaml:"permissions,omitempty"`
+	Encoding    *string                `yaml:"encoding,omitempty"`
+	Append      *bool                  `yaml:"append,omitempty"`
+	Defer       *bool                  `yaml:"defer,omitempty"`
 	Source      *WriteFileSourceOutput `yaml:"source,omitempty"`
 }
 
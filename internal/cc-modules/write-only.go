@@ -0,0 +1,40 @@
+package ccmodules
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// writeOnly
+// Write-only counterpart of a secret attribute: it is never stored in state, so `content` isn't either
+func writeOnly(attribute string) schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "Write-only variant of **" + attribute + "**. The value is not stored in state, only a hash of it is (see `write_only_hash`), which doesn't protect weak values. As `content` would carry it, `content` of the `cloud-config` resource and its encoded variants are null when it is set, use the ephemeral `cloud-config` resource to render the document. Not supported in parts of `cloud-config_multipart`. Conflicts with **" + attribute + "**.",
+		Optional:            true,
+		Sensitive:           true,
+		WriteOnly:           true,
+		Validators: []validator.String{
+			stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName(attribute)),
+		},
+	}
+}
+
+// writeOnlyDescription
+// Description of a write-only attribute outside of managed resources, where it is a regular one
+func writeOnlyDescription(name string, attribute schema.StringAttribute, persisted bool) string {
+	if !attribute.WriteOnly {
+		return attribute.MarkdownDescription
+	}
+
+	original := strings.TrimSuffix(name, "_wo")
+
+	if persisted {
+		return "Same as **" + original + "**, for parity with the `cloud-config` resource. Data sources have no write-only attributes, so the value is used to render `content` and is stored in state like **" + original + "**. Conflicts with **" + original + "**."
+	}
+
+	return "Same as **" + original + "**, for parity with the `cloud-config` resource. The value is used to render `content`, nothing is stored in state. Conflicts with **" + original + "**."
+}
@@ -1,7 +1,6 @@
 package ccmodules
 
 import (
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -14,8 +13,8 @@ type ZypperRepository struct {
 }
 
 type ZypperRepositoryOutput struct {
-	ID      string `yaml:"id,omitempty"`
-	BaseURL string `yaml:"baseurl,omitempty"`
+	ID      *string `yaml:"id,omitempty"`
+	BaseURL *string `yaml:"baseurl,omitempty"`
 }
 
 type Zypper struct {
@@ -43,7 +42,7 @@ func ZypperBlock() CCModuleNested {
 		block: map[string]schema.Block{
 			"zypper": schema.SingleNestedBlock{
 				PlanModifiers: []planmodifier.Object{
-					utils.NullWhen(path.Root("zypper")),
+					utils.NullWhen(),
 				},
 				MarkdownDescription: `
 Zypper behavior can be configured using the config key, which will modify /etc/zypp/zypp.conf. The configuration writer will only append the provided configuration options to the configuration file. Any duplicate options will be resolved by the way the zypp.conf INI file is parsed.
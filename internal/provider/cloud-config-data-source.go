@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &CloudConfigDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &CloudConfigDataSource{}
+
+func NewCloudConfigDataSource() datasource.DataSource {
+	return &CloudConfigDataSource{}
+}
+
+// CloudConfigDataSource
+// Stateless alternative to `CloudConfigResource`, renders content on every read
+type CloudConfigDataSource struct {
+	provider *ProviderData
+}
+
+func (d *CloudConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName
+}
+
+func (d *CloudConfigDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "Cloud-config file rendered at read time, the stateless counterpart of the `cloud-config` resource",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "YAML content of cloud-init file",
+			},
+			"content_redacted": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content` with secrets (passwords of **users**, **user** and **chpasswd**, **salt_minion** private key and **landscape** registration key) replaced by `<redacted>`, so the document can be reviewed in plans",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "`content`, encoded with base64",
+			},
+			"content_gzip_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "`content`, compressed with gzip and encoded with base64. Compression is deterministic, so the value only changes along with `content`. Useful to fit into user-data size limits, e.g. 16 KiB on EC2.",
+			},
+			"write_only_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "HMAC-SHA256 of `_wo` attributes (e.g. **plain_text_passwd_wo**) keyed with `hash_salt`. Null when none of them is set.",
+			},
+			"hash_salt": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Seed of salts used to hash passwords with **hash_plaintext**. Required by **hash_plaintext**, as a data source has no state to keep a generated one in.",
+			},
+			"target_platform": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Platform the user-data is meant for. Rendered content is checked against the platform's user-data size limit: an error is reported if it doesn't fit, a warning if only `content_gzip_base64` does. Limits: `aws` 16 KiB, `azure` 64 KiB (base64 encoded), `gce` 256 KiB, `openstack` 65535 bytes (base64 encoded), `nocloud` and `vmware` have no limit.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(targetPlatforms()...),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{},
+	}
+
+	for _, module := range flatModules() {
+		maps.Insert(schema.Attributes, maps.All(module.DataSourceAttributes()))
+	}
+
+	for _, module := range blockModules() {
+		maps.Insert(schema.Blocks, maps.All(module.DataSourceBlock()))
+	}
+
+	resp.Schema = schema
+}
+
+func (d *CloudConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = providerData
+}
+
+func (d *CloudConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CloudConfigResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read a data source")
+
+	content, err := ExportContent(ctx, data, d.provider.defaults())
+	if err != nil {
+		resp.Diagnostics.Append(err...)
+		return
+	}
+
+	resp.Diagnostics.Append(checkContent(ctx, data, content)...)
+	resp.Diagnostics.Append(setContent(&data, content)...)
+	data.WriteOnlyHash = writeOnlyHash(data.CloudConfigModel, data.HashSalt)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *CloudConfigDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.Conflicting(
+			path.MatchRoot("manage_etc_hosts"),
+			path.MatchRoot("manage_etc_hosts_localhost"),
+		),
+	}
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const dataSourceName = "data.cloud-config.test"
+
+func TestAccCloudConfigDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "cloud-config" "test" {
+  hostname = "one"
+  fqdn     = "one.lan"
+
+  runcmd = ["echo '11'"]
+
+  users {
+    name  = "admin"
+    shell = "/bin/bash"
+  }
+
+  write_files {
+    path    = "/etc/motd"
+    content = "hello"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						dataSourceName,
+						tfjsonpath.New("hostname"),
+						knownvalue.StringExact("one"),
+					),
+					statecheck.ExpectKnownValue(
+						dataSourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+hostname: one
+fqdn: one.lan
+runcmd:
+    - echo '11'
+users:
+    - name: admin
+      shell: /bin/bash
+write_files:
+    - path: /etc/motd
+      content: hello
+`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccCloudConfigDataSourceMatchesResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExampleResourceConfig("one") + `
+data "cloud-config" "test" {
+  hostname                  = "one"
+  fqdn                      = "one.lan"
+  prefer_fqdn_over_hostname = true
+  preserve_hostname         = true
+  create_hostname_file      = false
+
+  locale            = "en_one"
+  locale_configfile = "/etc/locale"
+
+  timezone = "Asia/one"
+
+  runcmd = ["echo '11'", "cat one"]
+
+  manage_etc_hosts_localhost = true
+
+  ssh_authorized_keys = ["one"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.CompareValuePairs(
+						dataSourceName,
+						tfjsonpath.New("content"),
+						resourceName,
+						tfjsonpath.New("content"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccCloudConfigDataSourceHashPlaintext(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Data source has no state to keep a generated salt in
+			{
+				Config: `
+data "cloud-config" "test" {
+  users {
+    name              = "alice"
+    plain_text_passwd = "secret"
+    hash_plaintext    = true
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("`hash_salt` must be set to hash plaintext\\s+passwords"),
+			},
+			{
+				Config: `
+data "cloud-config" "test" {
+  hash_salt = "seed"
+
+  users {
+    name              = "alice"
+    plain_text_passwd = "secret"
+    hash_plaintext    = true
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						dataSourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+users:
+    - name: alice
+      hashed_passwd: $6$MOKrOyKChN686m6J$UcP311lCH5MldB1YJjJGQGbVgQ/aW8Uff.sfQcxW6KAgT6jGKXXaqmFNegxjo15qCSsTAwJHswys9GB9qSa2u1
+`)),
+					),
+				},
+			},
+		},
+	})
+}
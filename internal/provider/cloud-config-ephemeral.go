@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/ephemeralvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ ephemeral.EphemeralResource = &CloudConfigEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &CloudConfigEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigValidators = &CloudConfigEphemeralResource{}
+
+func NewCloudConfigEphemeralResource() ephemeral.EphemeralResource {
+	return &CloudConfigEphemeralResource{}
+}
+
+// CloudConfigEphemeralResource
+// Renders content the same way as `CloudConfigResource`, but the result is never stored in plan or state
+type CloudConfigEphemeralResource struct {
+	provider *ProviderData
+}
+
+func (e *CloudConfigEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName
+}
+
+func (e *CloudConfigEphemeralResource) Schema(ctx context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "Cloud-config file rendered on every run, the ephemeral counterpart of the `cloud-config` resource. Rendered content never lands in plan or state, so secrets can be passed to ephemeral-aware arguments, e.g. write-only `user_data`. Requires Terraform 1.10 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "YAML content of cloud-init file",
+			},
+			"content_redacted": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content` with secrets (passwords of **users**, **user** and **chpasswd**, **salt_minion** private key and **landscape** registration key) replaced by `<redacted>`. Not sensitive, so it can be shown where secrets must not appear, e.g. in logs of provisioners. Like every value of ephemeral resources, it is neither part of plans nor stored in state",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "`content`, encoded with base64",
+			},
+			"content_gzip_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "`content`, compressed with gzip and encoded with base64. Compression is deterministic, so the value only changes along with `content`. Useful to fit into user-data size limits, e.g. 16 KiB on EC2.",
+			},
+			"write_only_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "HMAC-SHA256 of write-only attributes of the `cloud-config` resource (e.g. **plain_text_passwd_wo**) keyed with `hash_salt`. Null when none of them is set.",
+			},
+			"hash_salt": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Seed of salts used to hash passwords with **hash_plaintext**. Unless set, a random one is generated on every run, so the hashes change every time.",
+			},
+			"target_platform": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Platform the user-data is meant for. Rendered content is checked against the platform's user-data size limit: an error is reported if it doesn't fit, a warning if only `content_gzip_base64` does. Limits: `aws` 16 KiB, `azure` 64 KiB (base64 encoded), `gce` 256 KiB, `openstack` 65535 bytes (base64 encoded), `nocloud` and `vmware` have no limit.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(targetPlatforms()...),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{},
+	}
+
+	for _, module := range flatModules() {
+		maps.Insert(schema.Attributes, maps.All(module.EphemeralAttributes()))
+	}
+
+	for _, module := range blockModules() {
+		maps.Insert(schema.Blocks, maps.All(module.EphemeralBlock()))
+	}
+
+	resp.Schema = schema
+}
+
+func (e *CloudConfigEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.provider = providerData
+}
+
+func (e *CloudConfigEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data CloudConfigResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "opened an ephemeral resource")
+
+	salt, diagnostics := hashSalt(data.HashSalt)
+	resp.Diagnostics.Append(diagnostics...)
+	data.HashSalt = salt
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, err := ExportContent(ctx, data, e.provider.defaults())
+	if err != nil {
+		resp.Diagnostics.Append(err...)
+		return
+	}
+
+	resp.Diagnostics.Append(checkContent(ctx, data, content)...)
+	resp.Diagnostics.Append(setContent(&data, content)...)
+	data.WriteOnlyHash = writeOnlyHash(data.CloudConfigModel, data.HashSalt)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (e *CloudConfigEphemeralResource) ConfigValidators(ctx context.Context) []ephemeral.ConfigValidator {
+	return []ephemeral.ConfigValidator{
+		ephemeralvalidator.Conflicting(
+			path.MatchRoot("manage_etc_hosts"),
+			path.MatchRoot("manage_etc_hosts_localhost"),
+		),
+	}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccCloudConfigEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Ephemeral resources are available since 1.10.0
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+ephemeral "cloud-config" "test" {
+  hostname = "one"
+
+  users {
+    name              = "admin"
+    plain_text_passwd = "secret"
+  }
+}
+
+provider "echo" {
+  data = ephemeral.cloud-config.test.content
+}
+
+resource "echo" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"echo.test",
+						tfjsonpath.New("data"),
+						knownvalue.StringExact(expectedOutput(`
+hostname: one
+users:
+    - name: admin
+      plain_text_passwd: secret
+`)),
+					),
+				},
+			},
+			// Attributes which are write-only on the resource are regular ones here
+			{
+				Config: `
+ephemeral "cloud-config" "test" {
+  salt_minion {
+    private_key_wo = "private"
+  }
+}
+
+provider "echo" {
+  data = ephemeral.cloud-config.test.content_base64
+}
+
+resource "echo" "test_base64" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"echo.test_base64",
+						tfjsonpath.New("data"),
+						knownvalue.StringExact(base64.StdEncoding.EncodeToString([]byte(expectedOutput(`
+salt_minion:
+    private_key: private
+`)))),
+					),
+				},
+			},
+		},
+	})
+}
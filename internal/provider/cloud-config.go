@@ -2,31 +2,56 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"maps"
+	"os"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
 )
 
 var _ resource.Resource = &CloudConfigResource{}
+var _ resource.ResourceWithModifyPlan = &CloudConfigResource{}
 
-// var _ resource.ResourceWithImportState = &CloudConfigResource{}
+var _ resource.ResourceWithImportState = &CloudConfigResource{}
 
 func NewCloudConfigResource() resource.Resource {
 	return &CloudConfigResource{}
 }
 
 type CloudConfigResource struct {
+	provider *ProviderData
 }
 
 type CloudConfigResourceModel struct {
-	Content types.String `tfsdk:"content"`
+	Content           types.String `tfsdk:"content"`
+	ContentRedacted   types.String `tfsdk:"content_redacted"`
+	ContentBase64     types.String `tfsdk:"content_base64"`
+	ContentGzipBase64 types.String `tfsdk:"content_gzip_base64"`
+	TargetPlatform    types.String `tfsdk:"target_platform"`
+	WriteOnlyHash     types.String `tfsdk:"write_only_hash"`
+	HashSalt          types.String `tfsdk:"hash_salt"`
+
+	CloudConfigModel
+}
 
+// CloudConfigModel
+// Every supported module, without rendered output
+type CloudConfigModel struct {
 	ccmodules.SetHostnameModel
 	ccmodules.LocaleModel
 	ccmodules.TimezoneModel
@@ -62,6 +87,8 @@ type CloudConfigResourceModel struct {
 	ccmodules.ZypperModel
 	ccmodules.WriteFileModel
 	ccmodules.SpacewalkModel
+	ccmodules.ExtraYAMLModel
+	ccmodules.TemplateModel
 }
 
 type ExportModel struct {
@@ -102,25 +129,10 @@ type ExportModel struct {
 	ccmodules.SpacewalkOutputModel                  `yaml:",inline"`
 }
 
-func (r *CloudConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName
-}
-
-func (r *CloudConfigResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
-	schema := schema.Schema{
-		MarkdownDescription: "Cloud-config file in-memory representation", // NOTE: https://github.com/nobbs/terraform-provider-sops/blob/main/internal/provider/file_function.go
-
-		Attributes: map[string]schema.Attribute{
-			"content": schema.StringAttribute{
-				Computed:            true,
-				Sensitive:           true,
-				MarkdownDescription: "YAML content of cloud-init file",
-			},
-		},
-		Blocks: map[string]schema.Block{},
-	}
-
-	flat_modules := []ccmodules.CCModuleFlat{
+// flatModules
+// Modules represented by top-level attributes, shared by every cloud-config schema
+func flatModules() []ccmodules.CCModuleFlat {
+	return []ccmodules.CCModuleFlat{
 		ccmodules.SetHostname(),
 		ccmodules.Locale(),
 		ccmodules.Timezone(),
@@ -136,13 +148,15 @@ func (r *CloudConfigResource) Schema(ctx context.Context, _ resource.SchemaReque
 		ccmodules.FinalMessage(),
 		ccmodules.KeysToConsole(),
 		ccmodules.Resizefs(),
+		ccmodules.ExtraYAML(),
+		ccmodules.Template(),
 	}
+}
 
-	for _, module := range flat_modules {
-		maps.Insert(schema.Attributes, maps.All(module.Attributes()))
-	}
-
-	block_modules := []ccmodules.CCModuleNested{
+// blockModules
+// Modules represented by blocks, shared by every cloud-config schema
+func blockModules() []ccmodules.CCModuleNested {
+	return []ccmodules.CCModuleNested{
 		ccmodules.SetPasswordsBlock(),
 		ccmodules.UsersAndGroupsBlock(),
 		ccmodules.ApkConfigureBlock(),
@@ -167,8 +181,64 @@ func (r *CloudConfigResource) Schema(ctx context.Context, _ resource.SchemaReque
 		ccmodules.WriteFileBlock(),
 		ccmodules.SpacewalkBlock(),
 	}
+}
+
+func (r *CloudConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName
+}
 
-	for _, module := range block_modules {
+func (r *CloudConfigResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "Cloud-config file in-memory representation", // NOTE: https://github.com/nobbs/terraform-provider-sops/blob/main/internal/provider/file_function.go
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "YAML content of cloud-init file. Null when any write-only attribute (e.g. **plain_text_passwd_wo**) is set, so their values never reach state: review the document with `content_redacted` and render it with the ephemeral `cloud-config` resource.",
+			},
+			"content_redacted": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content` with secrets (passwords of **users**, **user** and **chpasswd**, **salt_minion** private key and **landscape** registration key) replaced by `<redacted>`, so the document can be reviewed in plans",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "`content`, encoded with base64. Null along with `content`.",
+			},
+			"content_gzip_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "`content`, compressed with gzip and encoded with base64. Compression is deterministic, so the value only changes along with `content`. Useful to fit into user-data size limits, e.g. 16 KiB on EC2. Null along with `content`.",
+			},
+			"write_only_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "HMAC-SHA256 of write-only attributes (e.g. **plain_text_passwd_wo**) keyed with `hash_salt`, stored instead of their values to detect changes. As `hash_salt` is stored in state as well, weak values, e.g. short passwords, can be brute-forced from the hash by anyone who can read state. Null when none of them is set.",
+			},
+			"hash_salt": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Seed of salts used to hash passwords with **hash_plaintext**. Unless set, a random one is generated on creation and kept in state, so hashes stay the same across plans. With **hash_plaintext**, `content` of a new resource is therefore only known after apply.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"target_platform": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Platform the user-data is meant for. Rendered content is checked against the platform's user-data size limit: an error is reported if it doesn't fit, a warning if only `content_gzip_base64` does. Limits: `aws` 16 KiB, `azure` 64 KiB (base64 encoded), `gce` 256 KiB, `openstack` 65535 bytes (base64 encoded), `nocloud` and `vmware` have no limit.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(targetPlatforms()...),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{},
+	}
+
+	for _, module := range flatModules() {
+		maps.Insert(schema.Attributes, maps.All(module.Attributes()))
+	}
+
+	for _, module := range blockModules() {
 		maps.Insert(schema.Blocks, maps.All(module.Block()))
 	}
 
@@ -180,13 +250,26 @@ func (r *CloudConfigResource) Configure(ctx context.Context, req resource.Config
 	if req.ProviderData == nil {
 		return
 	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.provider = providerData
 }
 
 func (r *CloudConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data CloudConfigResourceModel
+	var data, config CloudConfigResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// NOTE: write-only attributes are null in the plan, they are only available in configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -196,13 +279,34 @@ func (r *CloudConfigResource) Create(ctx context.Context, req resource.CreateReq
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
 
-	content, err := ExportContent(ctx, data)
+	var diagnostics diag.Diagnostics
+	data.HashSalt, diagnostics = hashSalt(data.HashSalt)
+	resp.Diagnostics.Append(diagnostics...)
+	config.HashSalt = data.HashSalt
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, err := ExportContent(ctx, config, r.provider.defaults())
 	if err != nil {
 		resp.Diagnostics.Append(err...)
 		return
 	}
 
-	data.Content = types.StringValue(content)
+	data.WriteOnlyHash = writeOnlyHash(config.CloudConfigModel, data.HashSalt)
+
+	// NOTE: content is checked at plan time, unless it was unknown back then
+	if data.Content.IsUnknown() {
+		resp.Diagnostics.Append(checkContent(ctx, data, content)...)
+	}
+
+	resp.Diagnostics.Append(setContent(&data, content)...)
+	withoutWriteOnly(&data, config.CloudConfigModel)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -222,21 +326,45 @@ func (r *CloudConfigResource) Read(ctx context.Context, req resource.ReadRequest
 }
 
 func (r *CloudConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data CloudConfigResourceModel
+	var data, config CloudConfigResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// NOTE: write-only attributes are null in the plan, they are only available in configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diagnostics diag.Diagnostics
+	data.HashSalt, diagnostics = hashSalt(data.HashSalt)
+	resp.Diagnostics.Append(diagnostics...)
+	config.HashSalt = data.HashSalt
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	content, err := ExportContent(ctx, data)
+
+	content, err := ExportContent(ctx, config, r.provider.defaults())
 	if err != nil {
 		resp.Diagnostics.Append(err...)
 		return
 	}
 
-	data.Content = types.StringValue(content)
+	data.WriteOnlyHash = writeOnlyHash(config.CloudConfigModel, data.HashSalt)
+
+	// NOTE: content is checked at plan time, unless it was unknown back then
+	if data.Content.IsUnknown() {
+		resp.Diagnostics.Append(checkContent(ctx, data, content)...)
+	}
+
+	resp.Diagnostics.Append(setContent(&data, content)...)
+	withoutWriteOnly(&data, config.CloudConfigModel)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -253,6 +381,55 @@ func (r *CloudConfigResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 }
 
+func (r *CloudConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// NOTE: `content` can only be rendered when every input (including provider defaults)
+	// is already known, otherwise it stays "known after apply"
+	if !req.Config.Raw.IsFullyKnown() || !r.provider.known() {
+		return
+	}
+
+	var data, config CloudConfigResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NOTE: `hash_salt` is generated on creation, passwords can't be hashed before that
+	if data.HashSalt.IsUnknown() && usesHashPlaintext(config.CloudConfigModel) {
+		return
+	}
+
+	config.HashSalt = data.HashSalt
+
+	content, err := ExportContent(ctx, config, r.provider.defaults())
+	if err != nil {
+		resp.Diagnostics.Append(err...)
+		return
+	}
+
+	resp.Diagnostics.Append(checkContent(ctx, data, content)...)
+	resp.Diagnostics.Append(setContent(&data, content)...)
+	withoutWriteOnly(&data, config.CloudConfigModel)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), data.Content)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_redacted"), data.ContentRedacted)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_base64"), data.ContentBase64)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_gzip_base64"), data.ContentGzipBase64)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("write_only_hash"), writeOnlyHash(config.CloudConfigModel, data.HashSalt))...)
+}
+
 func (r *CloudConfigResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
 	return []resource.ConfigValidator{
 		resourcevalidator.Conflicting(
@@ -262,6 +439,106 @@ func (r *CloudConfigResource) ConfigValidators(ctx context.Context) []resource.C
 	}
 }
 
-// func (r *CloudConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-// 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-// }
+func (r *CloudConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	raw, err := readImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("Expected a path to a local cloud-config file or base64 encoded cloud-config content: %s", err),
+		)
+		return
+	}
+
+	data, diagnostics := ParseContent(ctx, raw)
+	resp.Diagnostics.Append(diagnostics...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "imported a resource")
+
+	// NOTE: salt of hashed passwords can't be recovered from content, a new one is generated,
+	// so there is no diff after import
+	data.HashSalt, diagnostics = hashSalt(data.HashSalt)
+	resp.Diagnostics.Append(diagnostics...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NOTE: `content` is re-rendered rather than copied from the source,
+	// so the imported resource has no diff against the generated configuration
+	content, diagnostics := ExportContent(ctx, data, r.provider.defaults())
+	if diagnostics.HasError() {
+		resp.Diagnostics.Append(diagnostics...)
+		return
+	}
+
+	resp.Diagnostics.Append(setContent(&data, content)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// computedAttributes
+// Attributes rendered by the provider, never a part of the input
+var computedAttributes = []string{"content", "content_redacted", "content_base64", "content_gzip_base64", "write_only_hash"}
+
+// checkContent
+// Validates rendered content against cloud-init schema and limits of the target platform
+func checkContent(ctx context.Context, data CloudConfigResourceModel, content string) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	diagnostics.Append(validateSchema(ctx, content, path.Empty(), data)...)
+	diagnostics.Append(checkPlatformLimits(data.TargetPlatform, content)...)
+
+	return diagnostics
+}
+
+// setContent
+// Sets `content` along with its redacted and encoded representations
+func setContent(data *CloudConfigResourceModel, content string) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	redactedContent, diagnostics := RedactContent(content)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	compressed, err := utils.Gzip([]byte(content))
+	if err != nil {
+		diagnostics.AddError("Cannot compress content", err.Error())
+		return diagnostics
+	}
+
+	data.Content = types.StringValue(content)
+	data.ContentRedacted = types.StringValue(redactedContent)
+	data.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString([]byte(content)))
+	data.ContentGzipBase64 = types.StringValue(base64.StdEncoding.EncodeToString(compressed))
+
+	return diagnostics
+}
+
+// readImportID
+// Import ID is either a path to a local file or base64 encoded content
+func readImportID(id string) (string, error) {
+	if info, err := os.Stat(id); err == nil && info.Mode().IsRegular() {
+		content, err := os.ReadFile(id)
+		if err != nil {
+			return "", err
+		}
+
+		return string(content), nil
+	}
+
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSpace(id))
+	if err != nil {
+		return "", errors.New("no such file, and not a valid base64 string")
+	}
+
+	return string(content), nil
+}
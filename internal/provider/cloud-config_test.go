@@ -1,16 +1,30 @@
 package provider
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-testing/compare"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
 )
 
@@ -78,6 +92,1035 @@ ssh_authorized_keys:
 	})
 }
 
+func TestAccContentPlanned(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Content is rendered during plan
+			{
+				Config: testAccExampleResourceConfig("one"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(
+							resourceName,
+							tfjsonpath.New("content"),
+							knownvalue.StringExact(strings.TrimSpace(`
+#cloud-config
+hostname: one
+fqdn: one.lan
+prefer_fqdn_over_hostname: true
+preserve_hostname: true
+create_hostname_file: false
+locale: en_one
+locale_configfile: /etc/locale
+timezone: Asia/one
+runcmd:
+    - echo '11'
+    - cat one
+manage_etc_hosts: localhost
+ssh_authorized_keys:
+    - one
+              `)),
+						),
+					},
+				},
+			},
+			// Re-applying the same configuration is a no-op
+			{
+				Config: testAccExampleResourceConfig("one"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccContentEncoded(t *testing.T) {
+	content := expectedOutput(`
+hostname: one
+runcmd:
+    - echo '11'
+`)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+hostname = "one"
+runcmd = ["echo '11'"]
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content_base64"),
+						knownvalue.StringExact(base64.StdEncoding.EncodeToString([]byte(content))),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content_gzip_base64"),
+						knownvalue.StringFunc(func(v string) error {
+							compressed, err := base64.StdEncoding.DecodeString(v)
+							if err != nil {
+								return err
+							}
+
+							reader, err := gzip.NewReader(bytes.NewReader(compressed))
+							if err != nil {
+								return err
+							}
+
+							decompressed, err := io.ReadAll(reader)
+							if err != nil {
+								return err
+							}
+
+							if string(decompressed) != content {
+								return fmt.Errorf("expected %q, got %q", content, decompressed)
+							}
+
+							return nil
+						}),
+					),
+				},
+			},
+			// Compression is deterministic, so nothing changes
+			{
+				Config: wrapInput(`
+hostname = "one"
+runcmd = ["echo '11'"]
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionNoop),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccTargetPlatform(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+target_platform = "unknown"
+hostname = "one"
+`),
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			// Fits into the limit
+			{
+				Config: wrapInput(`
+target_platform = "aws"
+hostname = "one"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput("hostname: one")),
+					),
+				},
+			},
+			// Only compressed content fits, which is a warning
+			{
+				Config: wrapInput(fmt.Sprintf(`
+target_platform = "aws"
+write_files {
+  path = "/etc/big"
+  content = %q
+}
+`, strings.Repeat("a", 20*1024))),
+			},
+			// Doesn't fit at all
+			{
+				Config: wrapInput(fmt.Sprintf(`
+target_platform = "gce"
+write_files {
+  path = "/etc/big"
+  content = %q
+}
+`, strings.Repeat("a", 300*1024))),
+				ExpectError: regexp.MustCompile("Content exceeds gce user-data limit"),
+			},
+		},
+	})
+}
+
+func TestAccSchemaValidation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+chpasswd {
+  users {
+    name = "root"
+  }
+}
+`),
+				ExpectError: regexp.MustCompile("missing property 'password'"),
+			},
+			// Items of `users` are either strings or user mappings
+			{
+				Config: wrapInput(`
+users {
+  name = "bob"
+}
+extra_yaml = <<-EOT
+  users:
+    - default
+EOT
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+users:
+    - name: bob
+    - default
+`)),
+					),
+				},
+			},
+			// Users of provider defaults go first, errors still point to the user of the resource
+			{
+				Config: `
+provider "cloud-config" {
+  defaults {
+    users {
+      name = "admin"
+    }
+  }
+}
+` + wrapInput(`
+users {
+  name = "alice"
+}
+
+users {
+  name   = "bob"
+  groups = []
+}
+`),
+				ExpectError: regexp.MustCompile("(?s)\\d+:\\s+groups = \\[\\]\\s+Key `users\\[2\\]\\.groups`\\s+of\\s+rendered\\s+cloud-config"),
+			},
+		},
+	})
+}
+
+func TestAccExtraYAML(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Lists are appended, new keys go after the rendered ones
+			{
+				Config: wrapInput(`
+hostname = "one"
+runcmd = ["echo '11'"]
+extra_yaml = <<-EOT
+  runcmd:
+    - echo '22'
+  ansible:
+    install_method: pip
+EOT
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+hostname: one
+runcmd:
+    - echo '11'
+    - echo '22'
+ansible:
+    install_method: pip
+`)),
+					),
+				},
+			},
+			{
+				Config: wrapInput(`
+hostname = "one"
+runcmd = ["echo '11'"]
+extra_yaml = <<-EOT
+  hostname: two
+  runcmd:
+    - echo '22'
+EOT
+extra_yaml_conflicts = "raw_wins"
+extra_yaml_lists = "replace"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+hostname: two
+runcmd:
+    - echo '22'
+`)),
+					),
+				},
+			},
+			{
+				Config: wrapInput(`
+hostname = "one"
+extra_yaml = <<-EOT
+  hostname: two
+EOT
+extra_yaml_conflicts = "typed_wins"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput("hostname: one")),
+					),
+				},
+			},
+			{
+				Config: wrapInput(`
+hostname = "one"
+extra_yaml = <<-EOT
+  hostname: two
+EOT
+`),
+				ExpectError: regexp.MustCompile("Conflicting extra_yaml"),
+			},
+			{
+				Config: wrapInput(`
+extra_yaml = "- one"
+`),
+				ExpectError: regexp.MustCompile("Expected a YAML mapping"),
+			},
+		},
+	})
+}
+
+func TestAccJinjaTemplate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Values with jinja are written as literal blocks, the rest are untouched
+			{
+				Config: wrapInput(`
+template = "jinja"
+hostname = "{{ v1.local_hostname }}"
+runcmd = [
+  "echo '{{ v1['instance-id'] }}: {{ v1.region }}'",
+  "echo done",
+]
+write_files {
+  path    = "/etc/cloud-info"
+  content = "{% if v1.cloud_name == 'aws' %}aws{% endif %}"
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(`## template: jinja
+#cloud-config
+hostname: |-
+    {{ v1.local_hostname }}
+runcmd:
+    - |-
+      echo '{{ v1['instance-id'] }}: {{ v1.region }}'
+    - echo done
+write_files:
+    - path: /etc/cloud-info
+      content: |-
+        {% if v1.cloud_name == 'aws' %}aws{% endif %}`),
+					),
+				},
+			},
+			{
+				Config: wrapInput(`
+template = "jinja"
+hostname = "{{ v1.instance_name }}"
+`),
+				ExpectError: regexp.MustCompile("`v1.instance_name` is not a documented instance-data key"),
+			},
+			// Top-level aliases of `v1` keys, and variables of the template itself
+			{
+				Config: wrapInput(`
+template = "jinja"
+hostname = "{{ local_hostname }}"
+runcmd = [
+  "echo {{ region }} {{ ds.meta_data.name }}",
+  "{% for key in public_ssh_keys %}echo {{ key }}; {% endfor %}",
+  "{% set zone = availability_zone | default('none') %}echo {{ zone }}",
+]
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringRegexp(regexp.MustCompile(`hostname: \|-\n    \{\{ local_hostname \}\}`)),
+					),
+				},
+			},
+			{
+				Config: wrapInput(`
+template = "jinja"
+hostname = "{{ regoin }}"
+`),
+				ExpectError: regexp.MustCompile("`regoin` is not a documented instance-data variable"),
+			},
+			{
+				Config: wrapInput(`
+template = "jinja"
+runcmd = ["{% for key in public_ssh_keys %}echo {{ kye }}{% endfor %}"]
+`),
+				ExpectError: regexp.MustCompile("`kye` is not a documented instance-data variable"),
+			},
+			// Only tags are checked, `raw` blocks and text are written as is
+			{
+				Config: wrapInput(`
+template = "jinja"
+runcmd   = ["echo {{ combined_cloud_config['hostname'] }} v1.instance_name"]
+write_files {
+  path    = "/etc/template.j2"
+  content = "{% raw %}{{ v1.instance_name }}{% endraw %}"
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringRegexp(regexp.MustCompile(`content: \|-\n        \{% raw %\}\{\{ v1\.instance_name \}\}\{% endraw %\}`)),
+					),
+				},
+			},
+			// Templates, which can't be parsed, are still checked for `v1` keys
+			{
+				Config: wrapInput(`
+template = "jinja"
+runcmd   = ["{% macro x() %}{{ v1.instance_name }}{% endmacro %}"]
+`),
+				ExpectError: regexp.MustCompile("`v1.instance_name` is not a documented instance-data key"),
+			},
+		},
+	})
+}
+
+func TestAccProviderDefaults(t *testing.T) {
+	providerBlock := `
+provider "cloud-config" {
+  defaults {
+    timezone = "UTC"
+    packages = ["curl", "vim"]
+
+    users {
+      name = "admin"
+      sudo = ["ALL=(ALL) NOPASSWD:ALL"]
+    }
+
+    users {
+      name = "ops"
+    }
+  }
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerBlock + wrapInput(`
+hostname = "one"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+hostname: one
+timezone: UTC
+packages:
+    - curl
+    - vim
+users:
+    - name: admin
+      sudo:
+        - ALL=(ALL) NOPASSWD:ALL
+    - name: ops
+`)),
+					),
+				},
+			},
+			// Resource values take precedence
+			{
+				Config: providerBlock + wrapInput(`
+timezone = "Europe/Berlin"
+packages = ["vim", "git"]
+
+users {
+  name  = "ops"
+  shell = "/bin/zsh"
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+timezone: Europe/Berlin
+packages:
+    - curl
+    - vim
+    - git
+users:
+    - name: admin
+      sudo:
+        - ALL=(ALL) NOPASSWD:ALL
+    - name: ops
+      shell: /bin/zsh
+`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccExplicitZeroValues(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Explicit `false`, `0`, `""` and `[]` are rendered, `null` is omitted
+			{
+				Config: wrapInput(`
+final_message = ""
+ssh_key_console_blacklist = []
+package_update = false
+package_upgrade = null
+disable_ec2_metadata = false
+resize_rootfs = false
+
+phone_home {
+  url   = "http://example.com/$INSTANCE_ID/"
+  tries = 0
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+package_update: false
+disable_ec2_metadata: false
+final_message: ""
+ssh_key_console_blacklist: []
+resize_rootfs: false
+phone_home:
+    url: http://example.com/$INSTANCE_ID/
+    tries: 0
+`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccWriteOnlyAttributes(t *testing.T) {
+	hash := func(values string) string {
+		mac := hmac.New(sha256.New, []byte("salt"))
+		mac.Write([]byte(values))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Write-only attributes are available since 1.11.0
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+users {
+  name                 = "alice"
+  plain_text_passwd    = "persisted"
+  plain_text_passwd_wo = "secret"
+}
+`),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			// Secrets are neither stored in state, nor rendered into persisted `content`
+			{
+				Config: wrapInput(`
+hash_salt = "salt"
+
+users {
+  name                 = "alice"
+  plain_text_passwd_wo = "secret"
+}
+
+salt_minion {
+  private_key_wo = "private"
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content_gzip_base64"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content_redacted"),
+						knownvalue.StringExact(expectedOutput(`
+users:
+    - name: alice
+      plain_text_passwd: <redacted>
+salt_minion:
+    private_key: <redacted>
+`)),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("users").AtSliceIndex(0).AtMapKey("plain_text_passwd_wo"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("salt_minion").AtMapKey("private_key_wo"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("write_only_hash"),
+						knownvalue.StringExact(hash("users.0.plain_text_passwd_wo=secret\nsalt_minion.private_key_wo=private")),
+					),
+				},
+			},
+			// Changed secret is detected through the hash
+			{
+				Config: wrapInput(`
+hash_salt = "salt"
+
+users {
+  name                 = "alice"
+  plain_text_passwd_wo = "changed"
+}
+
+salt_minion {
+  private_key_wo = "private"
+}
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue(
+							resourceName,
+							tfjsonpath.New("write_only_hash"),
+							knownvalue.StringExact(hash("users.0.plain_text_passwd_wo=changed\nsalt_minion.private_key_wo=private")),
+						),
+					},
+				},
+			},
+			// Persisted attributes are not hashed
+			{
+				Config: wrapInput(`
+hash_salt = "salt"
+
+users {
+  name              = "alice"
+  plain_text_passwd = "persisted"
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("write_only_hash"),
+						knownvalue.Null(),
+					),
+				},
+			},
+			// Hash is keyed with a generated `hash_salt` by default
+			{
+				Config: wrapInput(`
+users {
+  name                 = "alice"
+  plain_text_passwd_wo = "secret"
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("write_only_hash"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccContentRedacted(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+hostname = "one"
+
+users {
+  name              = "alice"
+  hashed_passwd     = "$6$rounds=4096$salt$hash"
+  plain_text_passwd = "secret"
+}
+
+chpasswd {
+  users {
+    name     = "bob"
+    password = "changeme"
+    type     = "text"
+  }
+}
+
+salt_minion {
+  public_key  = "public"
+  private_key = "private"
+}
+
+landscape {
+  client {
+    account_name     = "account"
+    registration_key = "key"
+  }
+}
+
+extra_yaml = <<-EOT
+  password: raw
+  EOT
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content_redacted"),
+						knownvalue.StringExact(expectedOutput(`
+hostname: one
+chpasswd:
+    users:
+        - name: bob
+          password: <redacted>
+          type: text
+users:
+    - name: alice
+      hashed_passwd: <redacted>
+      plain_text_passwd: <redacted>
+salt_minion:
+    public_key: public
+    private_key: <redacted>
+landscape:
+    client:
+        account_name: account
+        registration_key: <redacted>
+password: <redacted>
+`)),
+					),
+				},
+			},
+			// Without secrets, redacted content is the same as content
+			{
+				Config: wrapInput(`
+hostname = "one"
+runcmd   = ["echo 'yes'"]
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.CompareValuePairs(
+						resourceName,
+						tfjsonpath.New("content"),
+						resourceName,
+						tfjsonpath.New("content_redacted"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccHashPlaintext(t *testing.T) {
+	sameSalt := statecheck.CompareValue(compare.ValuesSame())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Salt is generated on creation, so content is only known after apply
+			{
+				Config: wrapInput(`
+hostname = "one"
+
+users {
+  name              = "alice"
+  plain_text_passwd = "secret"
+  hash_plaintext    = true
+}
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue(resourceName, tfjsonpath.New("hash_salt")),
+						plancheck.ExpectUnknownValue(resourceName, tfjsonpath.New("content")),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameSalt.AddStateValue(resourceName, tfjsonpath.New("hash_salt")),
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringRegexp(regexp.MustCompile(`(?m)^      hashed_passwd: \$6\$[./0-9A-Za-z]{16}\$[./0-9A-Za-z]{86}$`)),
+					),
+				},
+			},
+			// Salt is kept in state, so content is known at plan time and the hash doesn't change
+			{
+				Config: wrapInput(`
+hostname = "two"
+
+users {
+  name              = "alice"
+  plain_text_passwd = "secret"
+  hash_plaintext    = true
+}
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(resourceName, tfjsonpath.New("content"), knownvalue.NotNull()),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					sameSalt.AddStateValue(resourceName, tfjsonpath.New("hash_salt")),
+				},
+			},
+			{
+				Config: wrapInput(`
+hash_salt = "seed"
+
+users {
+  name              = "alice"
+  plain_text_passwd = "secret"
+  hash_plaintext    = true
+}
+
+chpasswd {
+  hash_plaintext = true
+
+  users {
+    name     = "bob"
+    password = "hunter2"
+    type     = "text"
+  }
+
+  users {
+    name = "carol"
+    type = "RANDOM"
+  }
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+chpasswd:
+    users:
+        - name: bob
+          password: $6$MVe78boRn/SQBt2g$rcnNJMR8wr3SkIi6SP1Fa1WKSnNl7Q6HqO633Ll0DlFfn2k2r9gXMk6OvWAKpeDxN4vm9NUXzC4S9Cc2yEwDC.
+          type: hash
+        - name: carol
+          type: RANDOM
+users:
+    - name: alice
+      hashed_passwd: $6$MOKrOyKChN686m6J$UcP311lCH5MldB1YJjJGQGbVgQ/aW8Uff.sfQcxW6KAgT6jGKXXaqmFNegxjo15qCSsTAwJHswys9GB9qSa2u1
+`)),
+					),
+				},
+			},
+			// Salt is derived from the user's name, so inserting a user keeps the other hashes
+			{
+				Config: wrapInput(`
+hash_salt = "seed"
+
+users {
+  name              = "zed"
+  plain_text_passwd = "secret"
+  hash_plaintext    = true
+}
+
+users {
+  name              = "alice"
+  plain_text_passwd = "secret"
+  hash_plaintext    = true
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringRegexp(regexp.MustCompile(`(?m)^    - name: alice\n      hashed_passwd: \$6\$MOKrOyKChN686m6J\$UcP311lCH5MldB1YJjJGQGbVgQ/aW8Uff\.sfQcxW6KAgT6jGKXXaqmFNegxjo15qCSsTAwJHswys9GB9qSa2u1$`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccContentUnknownInput(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// `terraform_data` is available since 1.4.0
+			tfversion.SkipBelow(tfversion.Version1_4_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "terraform_data" "hostname" {
+  input = "unknown"
+}
+
+resource "cloud-config" "test" {
+  hostname = terraform_data.hostname.output
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue(resourceName, tfjsonpath.New("content")),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput("hostname: unknown")),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccImportState(t *testing.T) {
+	contentFile := filepath.Join(t.TempDir(), "user-data.yaml")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExampleResourceConfig("one"),
+			},
+			// Import from base64 encoded content
+			{
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					content := s.RootModule().Resources[resourceName].Primary.Attributes["content"]
+
+					return base64.StdEncoding.EncodeToString([]byte(content)), nil
+				},
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "content",
+				ImportStateVerifyIgnore:              []string{"hash_salt"},
+			},
+			// Import from a local file
+			{
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					content := s.RootModule().Resources[resourceName].Primary.Attributes["content"]
+
+					return contentFile, os.WriteFile(contentFile, []byte(content), 0o600)
+				},
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "content",
+				ImportStateVerifyIgnore:              []string{"hash_salt"},
+			},
+		},
+	})
+}
+
+func TestAccImportStateExtraYAML(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+hostname = "one"
+extra_yaml = "runcmd:\n    - - ls\n      - -l\napt:\n    preserve_sources_list: true"
+`),
+			},
+			// Unknown keys and legacy shapes are moved to `extra_yaml`
+			{
+				ResourceName: resourceName,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					content := s.RootModule().Resources[resourceName].Primary.Attributes["content"]
+
+					return base64.StdEncoding.EncodeToString([]byte(content)), nil
+				},
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "content",
+				ImportStateVerifyIgnore:              []string{"hash_salt"},
+			},
+		},
+	})
+}
+
+func TestAccImportStateInvalidID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExampleResourceConfig("one"),
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: "/does/not/exist.yaml",
+				ExpectError:   regexp.MustCompile("Invalid import ID"),
+			},
+		},
+	})
+}
+
 func testAccExampleResourceConfig(configurableAttribute string) string {
 	return fmt.Sprintf(`
 resource "cloud-config" "test" {
@@ -707,6 +1750,16 @@ create_hostname_file= false
 			},
 			expectedOutput: "prefer_fqdn_over_hostname: true\npreserve_hostname: true\ncreate_hostname_file: false\n",
 		},
+		{
+			name: "Explicit default value",
+			input: `
+create_hostname_file = true
+			`,
+			expectedValues: map[string]string{
+				"create_hostname_file": "true",
+			},
+			expectedOutput: "create_hostname_file: true\n",
+		},
 	}
 
 	resource.Test(t, assembleTestCase(testCases, t))
@@ -1000,6 +2053,7 @@ func TestPkgUpdateUpgradeModule(t *testing.T) {
 			expectedOutput: `
 package_update: true
 package_upgrade: true
+package_reboot_if_required: false
 packages:
     - qemu-guest-agent
     - ufw
@@ -1080,6 +2134,7 @@ user:
     homedir: /home/myname
     inactive: 90 days
     lock_passwd: false
+    no_create_home: false
     no_log_init: true
     no_user_group: true
     passwd: mypwd
@@ -1216,6 +2271,7 @@ users:
       homedir: /home/myname
       inactive: 90 days
       lock_passwd: false
+      no_create_home: false
       no_log_init: true
       no_user_group: true
       passwd: mypwd
@@ -1242,7 +2298,10 @@ users:
       gecos: This use is jsut for testing
       homedir: /home/myname
       inactive: 90 days
+      lock_passwd: true
       no_create_home: true
+      no_log_init: false
+      no_user_group: false
       passwd: mypwd
       hashed_passwd: hashed pwd
       plain_text_passwd: mypwd
@@ -1446,6 +2505,32 @@ ssh_authorized_keys:
 	resource.Test(t, assembleTestCase(testCases, t))
 }
 
+func TestAptPipeliningModuleFalse(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// cloud-init ignores `apt_pipelining: true`, so `false` has nothing to render
+			{
+				Config: wrapInput(`
+apt_pipelining {
+  os = false
+}
+`),
+				ExpectError: regexp.MustCompile(`Attribute\s+apt_pipelining.os\s+Value\s+must\s+be\s+"true"`),
+			},
+			{
+				Config: wrapInput(`
+apt_pipelining {
+  disable = false
+}
+`),
+				ExpectError: regexp.MustCompile(`Attribute\s+apt_pipelining.disable\s+Value\s+must\s+be\s+"true"`),
+			},
+		},
+	})
+}
+
 func TestByobuModule(t *testing.T) {
 	testCases := []testCase{
 		{
@@ -1762,6 +2847,21 @@ ssh_key_console_blacklist:
 ssh_fp_console_blacklist:
     - E25451E0221B5773DEBFF178ECDACB160995AA89
 		`},
+		{
+			name: "Explicit default value",
+			input: `
+ssh {
+  emit_keys_to_console = true
+}
+			`,
+			expectedValues: map[string]string{
+				"ssh.emit_keys_to_console": "true",
+			},
+			expectedOutput: `
+ssh:
+    emit_keys_to_console: true
+			`,
+		},
 		{
 			name: "Fail in older versions because `chapasswd` block needs to be deleted",
 			input: `
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &DecodeFunction{}
+
+func NewDecodeFunction() function.Function {
+	return &DecodeFunction{}
+}
+
+// DecodeFunction
+// Parses cloud-config YAML back into an object shaped like `cloud-config` resource
+type DecodeFunction struct {
+}
+
+func (f *DecodeFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decode"
+}
+
+func (f *DecodeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Decode cloud-config YAML",
+		MarkdownDescription: "Parses a cloud-config document into an object with the same attributes and blocks as the `cloud-config` resource, so the result can be passed to `render` or read attribute by attribute. The `#cloud-config` header is optional. Keys which the provider can't represent, either unknown or of a legacy shape (e.g. the `default` user or commands as argument lists), are moved to `extra_yaml` as is, so rendering the result produces an equivalent document. Pass `true` as `strict` to fail on such keys instead, the error lists every one of them. Provider-defined functions require Terraform 1.8 or later.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "content",
+				MarkdownDescription: "Cloud-config YAML document",
+			},
+		},
+		VariadicParameter: function.BoolParameter{
+			Name:                "strict",
+			MarkdownDescription: "Optional, when `true` keys which would be moved to `extra_yaml` are reported as errors",
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *DecodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content string
+	var strict []bool
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content, &strict))
+
+	if resp.Error != nil {
+		return
+	}
+
+	if len(strict) > 1 {
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("expected at most one `strict` argument, got %d", len(strict)))
+		return
+	}
+
+	data, diagnostics := ParseContent(ctx, content)
+
+	reported := diagnostics.Errors()
+
+	// NOTE: functions can't emit warnings, those only describe keys moved to `extra_yaml`, so strict mode reports them as errors
+	if len(strict) == 1 && strict[0] {
+		reported = diagnostics
+	}
+
+	for _, d := range reported {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, fmt.Sprintf("%s: %s", d.Summary(), d.Detail())))
+	}
+
+	if resp.Error != nil {
+		return
+	}
+
+	object, diagnostics := modelToObject(ctx, data)
+	if diagnostics.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diagnostics)
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, types.DynamicValue(object)))
+}
+
+// modelToObject
+// Converts `CloudConfigResourceModel` into an object without computed attributes
+func modelToObject(ctx context.Context, data CloudConfigResourceModel) (types.Object, diag.Diagnostics) {
+	objectType := cloudConfigSchema(ctx).Type().(types.ObjectType)
+
+	object, diagnostics := types.ObjectValueFrom(ctx, objectType.AttrTypes, data)
+	if diagnostics.HasError() {
+		return object, diagnostics
+	}
+
+	attributeTypes := maps.Clone(objectType.AttrTypes)
+	attributes := maps.Clone(object.Attributes())
+
+	for _, name := range computedAttributes {
+		delete(attributeTypes, name)
+		delete(attributes, name)
+	}
+
+	return types.ObjectValue(attributeTypes, attributes)
+}
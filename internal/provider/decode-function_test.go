@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestDecodeFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  config = provider::cloud-config::decode(<<-EOT
+    #cloud-config
+    hostname: one
+    manage_etc_hosts: localhost
+    runcmd:
+        - echo '11'
+    users:
+        - name: admin
+          uid: 1001
+    EOT
+  )
+}
+
+output "hostname" {
+  value = local.config.hostname
+}
+
+output "manage_etc_hosts_localhost" {
+  value = local.config.manage_etc_hosts_localhost
+}
+
+output "user" {
+  value = local.config.users[0].name
+}
+
+output "uid" {
+  value = local.config.users[0].uid
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("hostname", knownvalue.StringExact("one")),
+					statecheck.ExpectKnownOutputValue("manage_etc_hosts_localhost", knownvalue.Bool(true)),
+					statecheck.ExpectKnownOutputValue("user", knownvalue.StringExact("admin")),
+					statecheck.ExpectKnownOutputValue("uid", knownvalue.Int64Exact(1001)),
+				},
+			},
+		},
+	})
+}
+
+func TestDecodeFunctionRoundTrip(t *testing.T) {
+	content := expectedOutput(`
+hostname: one
+fqdn: one.lan
+create_hostname_file: true
+runcmd:
+    - echo '11'
+manage_etc_hosts: true
+chpasswd:
+    expire: false
+package_update: true
+apt_pipelining: false
+ssh:
+    emit_keys_to_console: true
+write_files:
+    - path: /etc/motd
+      content: hello
+      permissions: "0644"
+`)
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+variable "content" {
+  type = string
+}
+
+output "test" {
+  value = provider::cloud-config::render(provider::cloud-config::decode(var.content))
+}
+`,
+				ConfigVariables: config.Variables{
+					"content": config.StringVariable(content),
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(content)),
+				},
+			},
+		},
+	})
+}
+
+func TestDecodeFunctionUnsupportedKey(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  config = provider::cloud-config::decode("#cloud-config\nhostname: one\napt:\n  preserve_sources_list: true\n")
+}
+
+output "hostname" {
+  value = local.config.hostname
+}
+
+output "extra_yaml" {
+  value = local.config.extra_yaml
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("hostname", knownvalue.StringExact("one")),
+					statecheck.ExpectKnownOutputValue("extra_yaml", knownvalue.StringExact("apt:\n    preserve_sources_list: true")),
+				},
+			},
+			{
+				Config: `
+locals {
+  config = provider::cloud-config::decode("#cloud-config\nhostname: one\nntp:\n  enabled: true\n  unknown: 1\n")
+}
+
+output "ntp" {
+  value = local.config.ntp == null
+}
+
+output "extra_yaml" {
+  value = local.config.extra_yaml
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("ntp", knownvalue.Bool(true)),
+					statecheck.ExpectKnownOutputValue("extra_yaml", knownvalue.StringExact("ntp:\n    enabled: true\n    unknown: 1")),
+				},
+			},
+		},
+	})
+}
+
+func TestDecodeFunctionStrict(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::decode("#cloud-config\nhostname: one\napt:\n  preserve_sources_list: true\nusers:\n  - default\n", true)
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Key\s+` + "`apt`" + `\s+\(line\s+3\)\s+is\s+not\s+supported.*Value\s+of\s+` + "`users`" + `\s+\(line\s+5\)\s+cannot\s+be\s+represented`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::decode("#cloud-config\nhostname: one\napt:\n  preserve_sources_list: true\n", false).extra_yaml
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("apt:\n    preserve_sources_list: true")),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::decode("#cloud-config\nhostname: one\n", true).hostname
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact("one")),
+				},
+			},
+		},
+	})
+}
+
+func TestDecodeFunctionLegacyShapes(t *testing.T) {
+	for name, content := range map[string]string{
+		"default user": expectedOutput(`
+hostname: one
+users:
+    - default
+    - name: bob
+`),
+		"argv runcmd": expectedOutput(`
+hostname: one
+runcmd:
+    - - ls
+      - -l
+    - echo '11'
+`),
+		"versioned packages": expectedOutput(`
+hostname: one
+packages:
+    - - pkg
+      - "1.0"
+    - curl
+`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+					tfversion.SkipBelow(tfversion.Version1_8_0),
+				},
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: `
+variable "content" {
+  type = string
+}
+
+output "test" {
+  value = provider::cloud-config::render(provider::cloud-config::decode(var.content))
+}
+`,
+						ConfigVariables: config.Variables{
+							"content": config.StringVariable(content),
+						},
+						ConfigStateChecks: []statecheck.StateCheck{
+							statecheck.ExpectKnownOutputValue("test", knownvalue.StringExact(content)),
+						},
+					},
+				},
+			})
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderDefaultsModel
+// Organisation-wide defaults, inherited by every `cloud-config` resource and data source
+type ProviderDefaultsModel struct {
+	Timezone          types.String           `tfsdk:"timezone"`
+	NTPServers        types.List             `tfsdk:"ntp_servers"`
+	CACerts           types.List             `tfsdk:"ca_certs"`
+	SSHAuthorizedKeys types.List             `tfsdk:"ssh_authorized_keys"`
+	Packages          types.List             `tfsdk:"packages"`
+	Users             *[]ProviderDefaultUser `tfsdk:"users"`
+}
+
+type ProviderDefaultUser struct {
+	Name              types.String `tfsdk:"name"`
+	Gecos             types.String `tfsdk:"gecos"`
+	Shell             types.String `tfsdk:"shell"`
+	LockPassword      types.Bool   `tfsdk:"lock_passwd"`
+	Groups            types.List   `tfsdk:"groups"`
+	Sudo              types.List   `tfsdk:"sudo"`
+	SSHAuthorizedKeys types.List   `tfsdk:"ssh_authorized_keys"`
+}
+
+// defaultsBlock
+// Schema of `defaults` block of the provider
+func defaultsBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: `
+Defaults inherited by every ` + "`cloud-config`" + ` resource and data source. Values of the resource (or data source) take precedence:
+
+ - single values (e.g. **timezone**) are only used when the resource doesn't set them
+ - lists (e.g. **packages**) are combined, defaults go first and items the resource already has are skipped
+ - **users** are combined the same way, but a default user is skipped if the resource has a user with the same name
+
+Provider-defined functions and ` + "`cloud-config_multipart`" + ` don't inherit defaults.
+        `,
+		Attributes: map[string]schema.Attribute{
+			"timezone": schema.StringAttribute{
+				MarkdownDescription: "The timezone to use as represented in /usr/share/zoneinfo.",
+				Optional:            true,
+			},
+			"ntp_servers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "List of ntp servers, rendered as `ntp.servers`.",
+				Optional:            true,
+			},
+			"ca_certs": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "List of trusted CA certificates to add, rendered as `ca_certs.trusted`.",
+				Optional:            true,
+			},
+			"ssh_authorized_keys": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Public SSH keys added to the default user.",
+				Optional:            true,
+			},
+			"packages": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "List of packages to install.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"users": schema.ListNestedBlock{
+				MarkdownDescription: "Users to create, e.g. admin accounts. Note that cloud-init doesn't create the distro's default user when `users` is set, unless the resource adds a user named `default`.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The user's login name.",
+							Required:            true,
+						},
+						"gecos": schema.StringAttribute{
+							MarkdownDescription: "Optional comment about the user, usually a comma-separated string of real name and contact information.",
+							Optional:            true,
+						},
+						"shell": schema.StringAttribute{
+							MarkdownDescription: "Path to the user's login shell.",
+							Optional:            true,
+						},
+						"lock_passwd": schema.BoolAttribute{
+							MarkdownDescription: "Disable password login. Default: `true`.",
+							Optional:            true,
+						},
+						"groups": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "Groups to add the user to.",
+							Optional:            true,
+						},
+						"sudo": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "Sudo rules to use.",
+							Optional:            true,
+						},
+						"ssh_authorized_keys": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "List of SSH keys to add to user's authkeys file.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// model
+// Defaults in the shape of a resource, so they are rendered the same way.
+// Users which are already present in `document` are skipped
+func (d *ProviderDefaultsModel) model(document *yaml.Node) CloudConfigResourceModel {
+	var model CloudConfigResourceModel
+
+	model.Timezone = d.Timezone
+	model.SSHAuthorizedKeys = d.SSHAuthorizedKeys
+	model.Packages = d.Packages
+
+	if !d.NTPServers.IsNull() {
+		model.NTP = &ccmodules.NTP{Servers: d.NTPServers}
+	}
+
+	if !d.CACerts.IsNull() {
+		model.CACerts = &ccmodules.CACerts{Trusted: d.CACerts}
+	}
+
+	if d.Users != nil {
+		existing := userNames(document)
+		users := []ccmodules.User{}
+
+		for _, user := range *d.Users {
+			if existing[user.Name.ValueString()] {
+				continue
+			}
+
+			users = append(users, ccmodules.User{
+				Name:              user.Name,
+				Gecos:             user.Gecos,
+				Shell:             user.Shell,
+				LockPassword:      user.LockPassword,
+				Groups:            user.Groups,
+				Sudo:              user.Sudo,
+				SSHAuthorizedKeys: user.SSHAuthorizedKeys,
+			})
+		}
+
+		model.Users = &users
+	}
+
+	return model
+}
+
+// mergeDefaults
+// Deep-merges provider defaults into the rendered document, see `defaultsBlock` for the rules
+func mergeDefaults(ctx context.Context, document *yaml.Node, defaults *ProviderDefaultsModel) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	output, diagnostics := transform(ctx, defaults.model(document))
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	var node yaml.Node
+	if err := node.Encode(output); err != nil {
+		diagnostics.AddError("Cannot marshal YAML", err.Error())
+		return diagnostics
+	}
+
+	options := utils.MergeOptions{
+		KeepDst:      true,
+		PrependLists: true,
+		UniqueItems:  true,
+	}
+
+	if err := utils.MergeYAML(document, &node, options); err != nil {
+		diagnostics.AddError("Cannot merge provider defaults", err.Error())
+	}
+
+	return diagnostics
+}
+
+// userNames
+// Names of users (or `default` entries) of the rendered document
+func userNames(document *yaml.Node) map[string]bool {
+	names := map[string]bool{}
+
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if document.Content[i].Value != "users" {
+			continue
+		}
+
+		for _, user := range document.Content[i+1].Content {
+			if user.Kind == yaml.ScalarNode {
+				names[user.Value] = true
+				continue
+			}
+
+			for j := 0; j+1 < len(user.Content); j += 2 {
+				if user.Content[j].Value == "name" {
+					names[user.Content[j+1].Value] = true
+				}
+			}
+		}
+	}
+
+	return names
+}
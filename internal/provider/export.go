@@ -2,35 +2,49 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
 
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	hat = "#cloud-config"
+	hat      = "#cloud-config"
+	redacted = "<redacted>"
 )
 
+// castArray
+// Null list is omitted, while an explicitly empty one is rendered as `[]`
 func castArray[T any](ctx context.Context, arr types.List) (*[]T, diag.Diagnostics) {
-	elems := arr.Elements()
+	if arr.IsNull() || arr.IsUnknown() {
+		return nil, nil
+	}
 
-	if len(elems) > 0 {
-		cmds := make([]T, len(elems))
-		diagnostics := arr.ElementsAs(ctx, &cmds, false)
+	cmds := make([]T, 0, len(arr.Elements()))
+	diagnostics := arr.ElementsAs(ctx, &cmds, false)
 
-		if diagnostics.HasError() {
-			return nil, diagnostics
-		}
+	if diagnostics.HasError() {
+		return nil, diagnostics
+	}
 
-		return &cmds, nil
+	return &cmds, nil
+}
+
+// writeOnlyOr
+// Value of the write-only attribute when it is set, value of its persisted counterpart otherwise
+func writeOnlyOr(writeOnly types.String, value types.String) *string {
+	if !writeOnly.IsNull() {
+		return writeOnly.ValueStringPointer()
 	}
 
-	return nil, nil
+	return value.ValueStringPointer()
 }
 
 func transformWriteFiles(ctx context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
@@ -51,18 +65,18 @@ func transformWriteFiles(ctx context.Context, output *ExportModel, model CloudCo
 
 	for k, v := range *res {
 		item := ccmodules.WriteFileOutput{
-			Path:        v.Path.ValueString(),
-			Content:     v.Content.ValueString(),
-			Owner:       v.Owner.ValueString(),
-			Permissions: v.Permissions.ValueString(),
-			Encoding:    v.Encoding.ValueString(),
-			Append:      v.Append.ValueBool(),
-			Defer:       v.Defer.ValueBool(),
+			Path:        v.Path.ValueStringPointer(),
+			Content:     v.Content.ValueStringPointer(),
+			Owner:       v.Owner.ValueStringPointer(),
+			Permissions: v.Permissions.ValueStringPointer(),
+			Encoding:    v.Encoding.ValueStringPointer(),
+			Append:      v.Append.ValueBoolPointer(),
+			Defer:       v.Defer.ValueBoolPointer(),
 		}
 
 		if v.Source != nil {
 			src := ccmodules.WriteFileSourceOutput{
-				URI: v.Source.URI.ValueString(),
+				URI: v.Source.URI.ValueStringPointer(),
 			}
 
 			if !v.Source.Headers.IsUnknown() {
@@ -100,11 +114,16 @@ func transformZypper(ctx context.Context, output *ExportModel, model CloudConfig
 			return diagnostics
 		}
 
+		// NOTE: omitted blocks are null in `render` function
+		if res == nil {
+			res = &[]ccmodules.ZypperRepository{}
+		}
+
 		repos := make([]ccmodules.ZypperRepositoryOutput, len(*res))
 		for k, v := range *res {
 			repos[k] = ccmodules.ZypperRepositoryOutput{
-				ID:      v.ID.ValueString(),
-				BaseURL: v.BaseURL.ValueString(),
+				ID:      v.ID.ValueStringPointer(),
+				BaseURL: v.BaseURL.ValueStringPointer(),
 			}
 		}
 
@@ -147,12 +166,17 @@ func transformWireguard(ctx context.Context, output *ExportModel, model CloudCon
 		if diagnostics.HasError() {
 			return diagnostics
 		}
+
+		// NOTE: omitted blocks are null in `render` function
+		if res == nil {
+			res = &[]ccmodules.Interface{}
+		}
 		interfaces := make([]ccmodules.InterfaceOutput, len(*res))
 		for k, v := range *res {
 			interfaces[k] = ccmodules.InterfaceOutput{
-				Name:       v.Name.ValueString(),
-				ConfigPath: v.ConfigPath.ValueString(),
-				Content:    v.Content.ValueString(),
+				Name:       v.Name.ValueStringPointer(),
+				ConfigPath: v.ConfigPath.ValueStringPointer(),
+				Content:    v.Content.ValueStringPointer(),
 			}
 		}
 
@@ -171,22 +195,22 @@ func transformRPI(_ context.Context, output *ExportModel, model CloudConfigResou
 
 	rpi := ccmodules.RPIOutput{}
 
-	rpi.EnableRPIConnect = model.RPI.EnableRPIConnect.ValueBool()
+	rpi.EnableRPIConnect = model.RPI.EnableRPIConnect.ValueBoolPointer()
 
 	if model.RPI.Interfaces != nil {
 		interfaces := ccmodules.RPIInterfaceOutput{}
 
-		interfaces.SPI = model.RPI.Interfaces.SPI.ValueBool()
-		interfaces.I2C = model.RPI.Interfaces.I2C.ValueBool()
-		interfaces.SSH = model.RPI.Interfaces.SSH.ValueBool()
-		interfaces.Onewire = model.RPI.Interfaces.Onewire.ValueBool()
-		interfaces.RemoteGPIO = model.RPI.Interfaces.RemoteGPIO.ValueBool()
+		interfaces.SPI = model.RPI.Interfaces.SPI.ValueBoolPointer()
+		interfaces.I2C = model.RPI.Interfaces.I2C.ValueBoolPointer()
+		interfaces.SSH = model.RPI.Interfaces.SSH.ValueBoolPointer()
+		interfaces.Onewire = model.RPI.Interfaces.Onewire.ValueBoolPointer()
+		interfaces.RemoteGPIO = model.RPI.Interfaces.RemoteGPIO.ValueBoolPointer()
 
 		if model.RPI.Interfaces.Serial != nil {
 			serial := ccmodules.RPISerialOutput{}
 
-			serial.Console = model.RPI.Interfaces.Serial.Console.ValueBool()
-			serial.Hardware = model.RPI.Interfaces.Serial.Hardware.ValueBool()
+			serial.Console = model.RPI.Interfaces.Serial.Console.ValueBoolPointer()
+			serial.Hardware = model.RPI.Interfaces.Serial.Hardware.ValueBoolPointer()
 
 			interfaces.Serial = &serial
 		}
@@ -214,11 +238,11 @@ func transformSeedRandom(ctx context.Context, output *ExportModel, model CloudCo
 		seed.Command = res
 	}
 
-	seed.File = model.RandomSeed.File.ValueString()
-	seed.Data = model.RandomSeed.Data.ValueString()
-	seed.Encoding = model.RandomSeed.Encoding.ValueString()
+	seed.File = model.RandomSeed.File.ValueStringPointer()
+	seed.Data = model.RandomSeed.Data.ValueStringPointer()
+	seed.Encoding = model.RandomSeed.Encoding.ValueStringPointer()
 
-	seed.CommandRequired = model.RandomSeed.CommandRequired.ValueBool()
+	seed.CommandRequired = model.RandomSeed.CommandRequired.ValueBoolPointer()
 
 	output.RandomSeed = &seed
 
@@ -264,7 +288,7 @@ func transformNTP(ctx context.Context, output *ExportModel, model CloudConfigRes
 		ntp.Allow = res
 	}
 
-	ntp.NTPClient = model.NTP.NTPClient.ValueString()
+	ntp.NTPClient = model.NTP.NTPClient.ValueStringPointer()
 	ntp.Enabled = model.NTP.Enabled.ValueBoolPointer()
 
 	if model.NTP.Config != nil {
@@ -278,10 +302,10 @@ func transformNTP(ctx context.Context, output *ExportModel, model CloudConfigRes
 			config.Packages = res
 		}
 
-		config.Confpath = model.NTP.Config.Confpath.ValueString()
-		config.CheckExe = model.NTP.Config.CheckExe.ValueString()
-		config.ServiceName = model.NTP.Config.ServiceName.ValueString()
-		config.Template = model.NTP.Config.Template.ValueString()
+		config.Confpath = model.NTP.Config.Confpath.ValueStringPointer()
+		config.CheckExe = model.NTP.Config.CheckExe.ValueStringPointer()
+		config.ServiceName = model.NTP.Config.ServiceName.ValueStringPointer()
+		config.Template = model.NTP.Config.Template.ValueStringPointer()
 
 		ntp.Config = &config
 	}
@@ -304,16 +328,16 @@ func transformLandscape(_ context.Context, output *ExportModel, model CloudConfi
 
 	client := ccmodules.ClientOutput{}
 
-	client.URL = model.Landscape.Client.URL.ValueString()
-	client.PingURL = model.Landscape.Client.PingURL.ValueString()
-	client.DataPath = model.Landscape.Client.DataPath.ValueString()
-	client.LogLevel = model.Landscape.Client.LogLevel.ValueString()
-	client.ComputerTitle = model.Landscape.Client.ComputerTitle.ValueString()
-	client.AccountName = model.Landscape.Client.AccountName.ValueString()
-	client.RegistrationKey = model.Landscape.Client.RegistrationKey.ValueString()
-	client.Tags = model.Landscape.Client.Tags.ValueString()
-	client.HTTPProxy = model.Landscape.Client.HTTPProxy.ValueString()
-	client.HTTPSProxy = model.Landscape.Client.HTTPSProxy.ValueString()
+	client.URL = model.Landscape.Client.URL.ValueStringPointer()
+	client.PingURL = model.Landscape.Client.PingURL.ValueStringPointer()
+	client.DataPath = model.Landscape.Client.DataPath.ValueStringPointer()
+	client.LogLevel = model.Landscape.Client.LogLevel.ValueStringPointer()
+	client.ComputerTitle = model.Landscape.Client.ComputerTitle.ValueStringPointer()
+	client.AccountName = model.Landscape.Client.AccountName.ValueStringPointer()
+	client.RegistrationKey = writeOnlyOr(model.Landscape.Client.RegistrationKeyWO, model.Landscape.Client.RegistrationKey)
+	client.Tags = model.Landscape.Client.Tags.ValueStringPointer()
+	client.HTTPProxy = model.Landscape.Client.HTTPProxy.ValueStringPointer()
+	client.HTTPSProxy = model.Landscape.Client.HTTPSProxy.ValueStringPointer()
 
 	config.Client = &client
 	output.Landscape = &config
@@ -322,25 +346,22 @@ func transformLandscape(_ context.Context, output *ExportModel, model CloudConfi
 }
 
 func transformSetHostname(_ context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
-	output.Hostname = model.Hostname.ValueString()
-	output.FQDN = model.FQDN.ValueString()
-	output.PreserveHostname = model.PreserveHostname.ValueBool()
-	output.PreferFQDNOverHostname = model.PreferFQDNOverHostname.ValueBool()
+	output.Hostname = model.Hostname.ValueStringPointer()
+	output.FQDN = model.FQDN.ValueStringPointer()
+	output.PreserveHostname = model.PreserveHostname.ValueBoolPointer()
+	output.PreferFQDNOverHostname = model.PreferFQDNOverHostname.ValueBoolPointer()
 
-	// NOTE: default value is anyway `true`
-	if !model.CreateHostnameFile.IsNull() && !model.CreateHostnameFile.ValueBool() {
-		output.CreateHostnameFile = model.CreateHostnameFile.ValueBoolPointer()
-	}
+	output.CreateHostnameFile = model.CreateHostnameFile.ValueBoolPointer()
 
 	return nil
 }
 
 func transformLocale(_ context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
 	if !model.Locale.IsUnknown() {
-		output.Locale = model.Locale.ValueString()
+		output.Locale = model.Locale.ValueStringPointer()
 	}
 	if !model.LocaleConfigfile.IsUnknown() {
-		output.LocaleConfigfile = model.LocaleConfigfile.ValueString()
+		output.LocaleConfigfile = model.LocaleConfigfile.ValueStringPointer()
 	}
 	return nil
 }
@@ -402,19 +423,30 @@ func transformSetPasswords(_ context.Context, output *ExportModel, model CloudCo
 			output.ChPasswd.Expire = md.Expire.ValueBoolPointer()
 		}
 
-		if md.Users != nil {
+		if md.Users != nil && len(*md.Users) > 0 {
 			usrs := make([]ccmodules.ChangePasswordUserOutput, len(*md.Users))
 			for i, usr := range *md.Users {
 				newUsr := ccmodules.ChangePasswordUserOutput{}
 
 				if !usr.Name.IsNull() {
-					newUsr.Name = usr.Name.ValueString()
-				}
-				if !usr.Password.IsNull() {
-					newUsr.Password = usr.Password.ValueString()
+					newUsr.Name = usr.Name.ValueStringPointer()
 				}
+				newUsr.Password = writeOnlyOr(usr.PasswordWO, usr.Password)
 				if !usr.Type.IsNull() {
-					newUsr.Type = usr.Type.ValueString()
+					newUsr.Type = usr.Type.ValueStringPointer()
+				}
+
+				if md.HashPlaintext.ValueBool() && usr.Type.ValueString() == "text" && newUsr.Password != nil {
+					hashed, err := hashPlaintext(model, "chpasswd.users."+usr.Name.ValueString(), *newUsr.Password)
+					if err != nil {
+						var diagnostics diag.Diagnostics
+						diagnostics.AddAttributeError(path.Root("chpasswd").AtName("hash_plaintext"), "Cannot hash chpasswd password", err.Error())
+						return diagnostics
+					}
+
+					hashType := "hash"
+					newUsr.Password = &hashed
+					newUsr.Type = &hashType
 				}
 
 				usrs[i] = newUsr
@@ -428,9 +460,9 @@ func transformSetPasswords(_ context.Context, output *ExportModel, model CloudCo
 }
 
 func transformPkgUpdateUpgrade(ctx context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
-	output.PackageUpdate = model.PackageUpdate.ValueBool()
-	output.PackageUpgrade = model.PackageUpgrade.ValueBool()
-	output.PackageRebootIfRequired = model.PackageRebootIfRequired.ValueBool()
+	output.PackageUpdate = model.PackageUpdate.ValueBoolPointer()
+	output.PackageUpgrade = model.PackageUpgrade.ValueBoolPointer()
+	output.PackageRebootIfRequired = model.PackageRebootIfRequired.ValueBoolPointer()
 
 	if !model.Packages.IsUnknown() {
 		res, diagnostics := castArray[string](ctx, model.Packages)
@@ -443,33 +475,43 @@ func transformPkgUpdateUpgrade(ctx context.Context, output *ExportModel, model C
 }
 
 func transformUsersAndGroups(ctx context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
-	transformUser := func(user *ccmodules.User) (ccmodules.UserOutput, diag.Diagnostics) {
+	transformUser := func(user *ccmodules.User, at path.Path, prefix string) (ccmodules.UserOutput, diag.Diagnostics) {
 		out := ccmodules.UserOutput{}
 
-		out.Name = user.Name.ValueString()
-		out.ExpireDate = user.ExpireDate.ValueString()
-		out.Gecos = user.Gecos.ValueString()
-		out.HomeDir = user.HomeDir.ValueString()
-		out.Inactive = user.Inactive.ValueString()
-		out.Passwd = user.Passwd.ValueString()
-		out.HashedPasswd = user.HashedPasswd.ValueString()
-		out.PlainTextPasswd = user.PlainTextPasswd.ValueString()
-		out.PrimaryGroup = user.PrimaryGroup.ValueString()
-		out.SELinuxUser = user.SELinuxUser.ValueString()
-		out.Shell = user.Shell.ValueString()
-		out.SnapUser = user.SnapUser.ValueString()
-
-		// NOTE: default value is anyway `true`
-		if !user.LockPassword.IsNull() && !user.LockPassword.ValueBool() {
-			out.LockPassword = user.LockPassword.ValueBoolPointer()
+		out.Name = user.Name.ValueStringPointer()
+		out.ExpireDate = user.ExpireDate.ValueStringPointer()
+		out.Gecos = user.Gecos.ValueStringPointer()
+		out.HomeDir = user.HomeDir.ValueStringPointer()
+		out.Inactive = user.Inactive.ValueStringPointer()
+		out.Passwd = user.Passwd.ValueStringPointer()
+		out.HashedPasswd = writeOnlyOr(user.HashedPasswdWO, user.HashedPasswd)
+		out.PlainTextPasswd = writeOnlyOr(user.PlainTextPasswdWO, user.PlainTextPasswd)
+
+		if user.HashPlaintext.ValueBool() && out.PlainTextPasswd != nil {
+			hashed, err := hashPlaintext(model, prefix+"."+user.Name.ValueString(), *out.PlainTextPasswd)
+			if err != nil {
+				var diagnostics diag.Diagnostics
+				diagnostics.AddAttributeError(at.AtName("hash_plaintext"), "Cannot hash plain_text_passwd", err.Error())
+				return out, diagnostics
+			}
+
+			out.HashedPasswd = &hashed
+			out.PlainTextPasswd = nil
 		}
 
-		out.NoCreateHome = user.NoCreateHome.ValueBool()
-		out.NoLogInit = user.NoLogInit.ValueBool()
-		out.NoUserGroup = user.NoUserGroup.ValueBool()
-		out.CreateGroups = user.CreateGroups.ValueBool()
-		out.SSHRedirectUser = user.SSHRedirectUser.ValueBool()
-		out.System = user.System.ValueBool()
+		out.PrimaryGroup = user.PrimaryGroup.ValueStringPointer()
+		out.SELinuxUser = user.SELinuxUser.ValueStringPointer()
+		out.Shell = user.Shell.ValueStringPointer()
+		out.SnapUser = user.SnapUser.ValueStringPointer()
+
+		out.LockPassword = user.LockPassword.ValueBoolPointer()
+
+		out.NoCreateHome = user.NoCreateHome.ValueBoolPointer()
+		out.NoLogInit = user.NoLogInit.ValueBoolPointer()
+		out.NoUserGroup = user.NoUserGroup.ValueBoolPointer()
+		out.CreateGroups = user.CreateGroups.ValueBoolPointer()
+		out.SSHRedirectUser = user.SSHRedirectUser.ValueBoolPointer()
+		out.System = user.System.ValueBoolPointer()
 
 		if !user.UID.IsNull() {
 			out.UID = user.UID.ValueInt32Pointer()
@@ -528,17 +570,18 @@ func transformUsersAndGroups(ctx context.Context, output *ExportModel, model Clo
 	}
 
 	if model.User != nil {
-		user, diagnostics := transformUser(model.User)
+		user, diagnostics := transformUser(model.User, path.Root("user"), "user")
 		if diagnostics.HasError() {
 			return diagnostics
 		}
 		output.User = &user
 	}
 
-	if model.Users != nil {
+	// NOTE: list blocks are empty lists rather than null, when not configured
+	if model.Users != nil && len(*model.Users) > 0 {
 		usrs := make([]ccmodules.UserOutput, len(*model.Users))
 		for i, usr := range *model.Users {
-			user, diagnostics := transformUser(&usr)
+			user, diagnostics := transformUser(&usr, path.Root("users").AtListIndex(i), "users")
 			if diagnostics.HasError() {
 				return diagnostics
 			}
@@ -555,16 +598,16 @@ func transformApkConfigure(_ context.Context, output *ExportModel, model CloudCo
 	transformApkRepo := func(repo *ccmodules.ApkRepo) (ccmodules.ApkRepoOutput, diag.Diagnostics) {
 		apkRepo := ccmodules.ApkRepoOutput{}
 
-		apkRepo.PreserveRepositories = repo.PreserveRepositories.ValueBool()
-		apkRepo.LocalRepoBaseUrl = repo.LocalRepoBaseUrl.ValueString()
+		apkRepo.PreserveRepositories = repo.PreserveRepositories.ValueBoolPointer()
+		apkRepo.LocalRepoBaseUrl = repo.LocalRepoBaseUrl.ValueStringPointer()
 
 		if repo.AlpineRepo != nil {
 			alpineRepo := ccmodules.AlpineRepoOutput{}
-			alpineRepo.CommunityEnabled = repo.AlpineRepo.CommunityEnabled.ValueBool()
-			alpineRepo.TestingEnabled = repo.AlpineRepo.TestingEnabled.ValueBool()
+			alpineRepo.CommunityEnabled = repo.AlpineRepo.CommunityEnabled.ValueBoolPointer()
+			alpineRepo.TestingEnabled = repo.AlpineRepo.TestingEnabled.ValueBoolPointer()
 
-			alpineRepo.BaseUrl = repo.AlpineRepo.BaseUrl.ValueString()
-			alpineRepo.Version = repo.AlpineRepo.Version.ValueString()
+			alpineRepo.BaseUrl = repo.AlpineRepo.BaseUrl.ValueStringPointer()
+			alpineRepo.Version = repo.AlpineRepo.Version.ValueStringPointer()
 
 			apkRepo.AlpineRepo = &alpineRepo
 		}
@@ -587,11 +630,11 @@ func transformAptPipelining(_ context.Context, output *ExportModel, model CloudC
 		return nil
 	}
 
+	// NOTE: `false` of `os` and `disable` is rejected by validators, but `render` function skips them,
+	// nothing is rendered then, since cloud-init ignores `apt_pipelining: true`
 	if model.AptPipelining.OS.ValueBool() {
-		// if `os` is true, use value would be string
 		output.AptPipelining = "os"
 	} else if model.AptPipelining.Disable.ValueBool() {
-		// if `disable` is true, it should be false
 		output.AptPipelining = false
 	} else if !model.AptPipelining.Depth.IsNull() {
 		// if `depth` is configured, use as a number
@@ -608,7 +651,7 @@ func transformCACertificatesHosts(ctx context.Context, output *ExportModel, mode
 
 	caCerts := ccmodules.CACertsOutput{}
 
-	caCerts.RemoveDefaults = model.CACerts.RemoveDefaults.ValueBool()
+	caCerts.RemoveDefaults = model.CACerts.RemoveDefaults.ValueBoolPointer()
 
 	if !model.CACerts.Trusted.IsUnknown() {
 		res, diagnostics := castArray[string](ctx, model.CACerts.Trusted)
@@ -631,8 +674,8 @@ func transformFan(_ context.Context, output *ExportModel, model CloudConfigResou
 
 	fan := ccmodules.FanOutput{}
 
-	fan.Config = model.Fan.Config.ValueString()
-	fan.ConfigPath = model.Fan.ConfigPath.ValueString()
+	fan.Config = model.Fan.Config.ValueStringPointer()
+	fan.ConfigPath = model.Fan.ConfigPath.ValueStringPointer()
 
 	output.Fan = &fan
 
@@ -646,8 +689,8 @@ func transformGrowpart(ctx context.Context, output *ExportModel, model CloudConf
 
 	growpart := ccmodules.GrowpartOutput{}
 
-	growpart.IgnoreGrowrootDisabled = model.Growpart.IgnoreGrowrootDisabled.ValueBool()
-	growpart.Mode = model.Growpart.Mode.ValueString()
+	growpart.IgnoreGrowrootDisabled = model.Growpart.IgnoreGrowrootDisabled.ValueBoolPointer()
+	growpart.Mode = model.Growpart.Mode.ValueStringPointer()
 
 	if !model.Growpart.Devices.IsUnknown() {
 		res, diagnostics := castArray[string](ctx, model.Growpart.Devices)
@@ -669,10 +712,10 @@ func transformGRUBDpkg(_ context.Context, output *ExportModel, model CloudConfig
 
 	config := ccmodules.GRUBDpkgOutput{}
 
-	config.Enabled = model.GRUBDpkg.Enabled.ValueBool()
-	config.GRUBPC_InstallDevicesEmpty = model.GRUBDpkg.GRUBPC_InstallDevicesEmpty.ValueBool()
-	config.GRUBPC_InstallDevices = model.GRUBDpkg.GRUBPC_InstallDevices.ValueString()
-	config.GRUBEFI_InstallDevices = model.GRUBDpkg.GRUBEFI_InstallDevices.ValueString()
+	config.Enabled = model.GRUBDpkg.Enabled.ValueBoolPointer()
+	config.GRUBPC_InstallDevicesEmpty = model.GRUBDpkg.GRUBPC_InstallDevicesEmpty.ValueBoolPointer()
+	config.GRUBPC_InstallDevices = model.GRUBDpkg.GRUBPC_InstallDevices.ValueStringPointer()
+	config.GRUBEFI_InstallDevices = model.GRUBDpkg.GRUBEFI_InstallDevices.ValueStringPointer()
 
 	output.GRUBDpkg = &config
 
@@ -720,10 +763,10 @@ func transformKeyboard(_ context.Context, output *ExportModel, model CloudConfig
 	}
 
 	config := ccmodules.KeyboardOutput{
-		Layout:  model.Keyboard.Layout.ValueString(),
-		Model:   model.Keyboard.Model.ValueString(),
-		Variant: model.Keyboard.Variant.ValueString(),
-		Options: model.Keyboard.Options.ValueString(),
+		Layout:  model.Keyboard.Layout.ValueStringPointer(),
+		Model:   model.Keyboard.Model.ValueStringPointer(),
+		Variant: model.Keyboard.Variant.ValueStringPointer(),
+		Options: model.Keyboard.Options.ValueStringPointer(),
 	}
 
 	output.Keyboard = &config
@@ -732,12 +775,9 @@ func transformKeyboard(_ context.Context, output *ExportModel, model CloudConfig
 }
 
 func transformKeysToConsole(ctx context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
-	if model.SSH != nil {
-		// NOTE: default value is anyway `true`
-		if !model.SSH.EmitKeysToConsole.IsNull() && !model.SSH.EmitKeysToConsole.ValueBool() {
-			output.SSH = &ccmodules.SSHOutput{
-				EmitKeysToConsole: model.SSH.EmitKeysToConsole.ValueBoolPointer(),
-			}
+	if model.SSH != nil && !model.SSH.EmitKeysToConsole.IsNull() {
+		output.SSH = &ccmodules.SSHOutput{
+			EmitKeysToConsole: model.SSH.EmitKeysToConsole.ValueBoolPointer(),
 		}
 	}
 
@@ -787,11 +827,12 @@ func transformKeysToConsole(ctx context.Context, output *ExportModel, model Clou
 }
 
 func transformResizefs(_ context.Context, output *ExportModel, model CloudConfigResourceModel) diag.Diagnostics {
-	// NOTE: default value is anyway `true`
 	if !model.Resizefs.IsNull() && !model.Resizefs.ValueBool() {
 		output.Resizefs = model.Resizefs.ValueBoolPointer()
 	} else if model.ResizefsNoBlock.ValueBool() {
 		output.Resizefs = "noblock"
+	} else if !model.Resizefs.IsNull() {
+		output.Resizefs = model.Resizefs.ValueBoolPointer()
 	} else {
 		output.Resizefs = nil
 	}
@@ -806,12 +847,12 @@ func transformSaltMinion(_ context.Context, output *ExportModel, model CloudConf
 
 	config := ccmodules.SaltMinionOutput{}
 
-	config.PkgName = model.SaltMinion.PkgName.ValueString()
-	config.ServiceName = model.SaltMinion.ServiceName.ValueString()
-	config.ConfigDir = model.SaltMinion.ConfigDir.ValueString()
-	config.PublicKey = model.SaltMinion.PublicKey.ValueString()
-	config.PrivateKey = model.SaltMinion.PrivateKey.ValueString()
-	config.PkiDir = model.SaltMinion.PkiDir.ValueString()
+	config.PkgName = model.SaltMinion.PkgName.ValueStringPointer()
+	config.ServiceName = model.SaltMinion.ServiceName.ValueStringPointer()
+	config.ConfigDir = model.SaltMinion.ConfigDir.ValueStringPointer()
+	config.PublicKey = model.SaltMinion.PublicKey.ValueStringPointer()
+	config.PrivateKey = writeOnlyOr(model.SaltMinion.PrivateKeyWO, model.SaltMinion.PrivateKey)
+	config.PkiDir = model.SaltMinion.PkiDir.ValueStringPointer()
 
 	output.SaltMinion = &config
 
@@ -825,7 +866,7 @@ func transformUbuntuAutoinstall(_ context.Context, output *ExportModel, model Cl
 
 	config := ccmodules.AutoinstallOutput{}
 
-	config.Version = model.Autoinstall.Version.ValueInt32()
+	config.Version = model.Autoinstall.Version.ValueInt32Pointer()
 
 	output.Autoinstall = &config
 
@@ -839,10 +880,10 @@ func transformPowerStateChange(_ context.Context, output *ExportModel, model Clo
 
 	config := ccmodules.PowerStateOutput{}
 
-	config.Mode = model.PowerState.Mode.ValueString()
-	config.Message = model.PowerState.Message.ValueString()
+	config.Mode = model.PowerState.Mode.ValueStringPointer()
+	config.Message = model.PowerState.Message.ValueStringPointer()
 
-	config.Timeout = model.PowerState.Timeout.ValueInt64()
+	config.Timeout = model.PowerState.Timeout.ValueInt64Pointer()
 
 	if model.PowerState.NoDelay.ValueBool() {
 		// If `no_delay` is true - value is `now`
@@ -853,7 +894,7 @@ func transformPowerStateChange(_ context.Context, output *ExportModel, model Clo
 
 	if !model.PowerState.ConditionCmd.IsUnknown() && !model.PowerState.ConditionCmd.IsNull() {
 		config.Condition = model.PowerState.ConditionCmd.ValueString()
-	} else if !model.PowerState.Condition.IsUnknown() {
+	} else if !model.PowerState.Condition.IsUnknown() && !model.PowerState.Condition.IsNull() {
 		config.Condition = model.PowerState.Condition.ValueBool()
 	}
 
@@ -869,8 +910,8 @@ func transformPhoneHome(ctx context.Context, output *ExportModel, model CloudCon
 
 	config := ccmodules.PhoneHomeOutput{}
 
-	config.URL = model.PhoneHome.URL.ValueString()
-	config.Tries = int(model.PhoneHome.Tries.ValueInt64())
+	config.URL = model.PhoneHome.URL.ValueStringPointer()
+	config.Tries = model.PhoneHome.Tries.ValueInt64Pointer()
 
 	if !model.PhoneHome.Post.IsUnknown() {
 		res, diagnostics := castArray[string](ctx, model.PhoneHome.Post)
@@ -893,9 +934,9 @@ func transformSpacewalk(ctx context.Context, output *ExportModel, model CloudCon
 
 	config := ccmodules.SpacewalkOutput{}
 
-	config.Server = model.Spacewalk.Server.ValueString()
-	config.Proxy = model.Spacewalk.Proxy.ValueString()
-	config.ActivationKey = model.Spacewalk.ActivationKey.ValueString()
+	config.Server = model.Spacewalk.Server.ValueStringPointer()
+	config.Proxy = model.Spacewalk.Proxy.ValueStringPointer()
+	config.ActivationKey = model.Spacewalk.ActivationKey.ValueStringPointer()
 
 	output.Spacewalk = &config
 
@@ -915,7 +956,7 @@ func transform(ctx context.Context, model CloudConfigResourceModel) (ExportModel
 		return output, diagnostics
 	}
 
-	output.Timezone = model.Timezone.ValueString()
+	output.Timezone = model.Timezone.ValueStringPointer()
 
 	diagnostics = transformRunCMD(ctx, &output, model)
 	if diagnostics.HasError() {
@@ -952,7 +993,7 @@ func transform(ctx context.Context, model CloudConfigResourceModel) (ExportModel
 		return output, diagnostics
 	}
 
-	output.DisableEC2Metadata = model.DisableEC2Metadata.ValueBool()
+	output.DisableEC2Metadata = model.DisableEC2Metadata.ValueBoolPointer()
 
 	diagnostics = transformApkConfigure(ctx, &output, model)
 	if diagnostics.HasError() {
@@ -964,7 +1005,7 @@ func transform(ctx context.Context, model CloudConfigResourceModel) (ExportModel
 		return output, diagnostics
 	}
 
-	output.ByobuByDefault = model.ByobuByDefault.ValueString()
+	output.ByobuByDefault = model.ByobuByDefault.ValueStringPointer()
 
 	diagnostics = transformCACertificatesHosts(ctx, &output, model)
 	if diagnostics.HasError() {
@@ -976,7 +1017,7 @@ func transform(ctx context.Context, model CloudConfigResourceModel) (ExportModel
 		return output, diagnostics
 	}
 
-	output.FinalMessage = model.FinalMessage.ValueString()
+	output.FinalMessage = model.FinalMessage.ValueStringPointer()
 
 	diagnostics = transformGrowpart(ctx, &output, model)
 	if diagnostics.HasError() {
@@ -1071,12 +1112,52 @@ func transform(ctx context.Context, model CloudConfigResourceModel) (ExportModel
 	return output, nil
 }
 
-func ExportContent(ctx context.Context, model CloudConfigResourceModel) (string, diag.Diagnostics) {
+// ExportContent
+// Renders the model into cloud-config YAML, `defaults` of the provider are optional
+func ExportContent(ctx context.Context, model CloudConfigResourceModel, defaults *ProviderDefaultsModel) (string, diag.Diagnostics) {
 	output, diagnostics := transform(ctx, model)
 	if diagnostics != nil {
 		return "", diagnostics
 	}
-	yaml, err := yaml.Marshal(output)
+	var document any = output
+	header := hat
+
+	jinja := model.Template.ValueString() == ccmodules.TemplateJinja
+	if jinja {
+		header = jinjaHat + "\n" + hat
+	}
+
+	// NOTE: merging works on YAML nodes, which preserve the order of keys
+	if !model.ExtraYAML.IsNull() || defaults != nil || jinja {
+		var node yaml.Node
+		if err := node.Encode(output); err != nil {
+			return "", diag.Diagnostics{
+				diag.NewErrorDiagnostic("Cannot marshal YAML", err.Error()),
+			}
+		}
+
+		if !model.ExtraYAML.IsNull() {
+			diagnostics := mergeExtraYAML(&node, model.ExtraYAMLModel)
+			if diagnostics.HasError() {
+				return "", diagnostics
+			}
+		}
+
+		if defaults != nil {
+			diagnostics := mergeDefaults(ctx, &node, defaults)
+			if diagnostics.HasError() {
+				return "", diagnostics
+			}
+		}
+
+		if jinja {
+			jinjaLiteralStyle(&node)
+		}
+
+		document = &node
+	}
+
+	yaml, err := yaml.Marshal(document)
 
 	if err != nil {
 		return "", diag.Diagnostics{
@@ -1084,7 +1165,103 @@ func ExportContent(ctx context.Context, model CloudConfigResourceModel) (string,
 		}
 	}
 
-	return strings.TrimSpace(fmt.Sprintf(`%s
+	content := strings.TrimSpace(fmt.Sprintf(`%s
 %s
-  `, hat, yaml)), nil
+  `, header, yaml))
+
+	if jinja {
+		diagnostics := checkJinjaVariables(content)
+		if diagnostics.HasError() {
+			return "", diagnostics
+		}
+	}
+
+	return content, nil
+}
+
+// secretPaths
+// Values replaced in `content_redacted`, `*` matches every item of a list
+var secretPaths = []string{
+	"password",
+	"user.passwd",
+	"user.hashed_passwd",
+	"user.plain_text_passwd",
+	"users.*.passwd",
+	"users.*.hashed_passwd",
+	"users.*.plain_text_passwd",
+	"chpasswd.users.*.password",
+	"salt_minion.private_key",
+	"landscape.client.registration_key",
+}
+
+// RedactContent
+// Rendered content with secrets replaced by `<redacted>`, returned as is when there are no secrets
+func RedactContent(content string) (string, diag.Diagnostics) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &node); err != nil {
+		return "", diag.Diagnostics{
+			diag.NewErrorDiagnostic("Cannot unmarshal YAML", err.Error()),
+		}
+	}
+
+	if !utils.RedactYAML(&node, secretPaths, redacted) {
+		return content, nil
+	}
+
+	// NOTE: comments are preserved, so is the `#cloud-config` header
+	yaml, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", diag.Diagnostics{
+			diag.NewErrorDiagnostic("Cannot marshal YAML", err.Error()),
+		}
+	}
+
+	return strings.TrimSpace(string(yaml)), nil
+}
+
+// mergeExtraYAML
+// Deep-merges `extra_yaml` into the rendered document
+func mergeExtraYAML(document *yaml.Node, model ccmodules.ExtraYAMLModel) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	var extra yaml.Node
+	if err := yaml.Unmarshal([]byte(model.ExtraYAML.ValueString()), &extra); err != nil {
+		diagnostics.AddAttributeError(path.Root("extra_yaml"), "Cannot unmarshal YAML", err.Error())
+		return diagnostics
+	}
+
+	// NOTE: empty document, e.g. only comments
+	if len(extra.Content) == 0 || extra.Content[0].Tag == "!!null" {
+		return diagnostics
+	}
+
+	if extra.Content[0].Kind != yaml.MappingNode {
+		diagnostics.AddAttributeError(path.Root("extra_yaml"), "Invalid extra_yaml", "Expected a YAML mapping of keys.")
+		return diagnostics
+	}
+
+	options := utils.MergeOptions{
+		KeepDst:     model.ExtraYAMLConflicts.ValueString() == ccmodules.ExtraYAMLConflictTypedWins,
+		KeepSrc:     model.ExtraYAMLConflicts.ValueString() == ccmodules.ExtraYAMLConflictRawWins,
+		AppendLists: model.ExtraYAMLLists.ValueString() != ccmodules.ExtraYAMLListsReplace,
+	}
+
+	err := utils.MergeYAML(document, extra.Content[0], options)
+
+	var conflict *utils.MergeConflictError
+	if errors.As(err, &conflict) {
+		diagnostics.AddAttributeError(
+			path.Root("extra_yaml"),
+			"Conflicting extra_yaml",
+			fmt.Sprintf("Key `%s` is already set to a different value by attributes or blocks. Remove it from one of them, or set `extra_yaml_conflicts` to `typed_wins` or `raw_wins`.", conflict.Path),
+		)
+		return diagnostics
+	}
+
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("extra_yaml"), "Cannot merge extra_yaml", err.Error())
+		return diagnostics
+	}
+
+	return diagnostics
 }
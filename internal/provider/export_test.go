@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExportContentExplicitValues(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "apt_pipelining os",
+			content: "apt_pipelining: os",
+		},
+		{
+			name:    "apt_pipelining disabled",
+			content: "apt_pipelining: false",
+		},
+		{
+			name:    "apt_pipelining depth",
+			content: "apt_pipelining: 0",
+		},
+		{
+			name:    "create_hostname_file true",
+			content: "create_hostname_file: true",
+		},
+		{
+			name:    "create_hostname_file false",
+			content: "create_hostname_file: false",
+		},
+		{
+			name:    "emit_keys_to_console true",
+			content: "ssh:\n    emit_keys_to_console: true",
+		},
+		{
+			name:    "emit_keys_to_console false",
+			content: "ssh:\n    emit_keys_to_console: false",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			content := expectedOutput(tc.content)
+
+			model, diagnostics := ParseContent(ctx, content)
+			if diagnostics.HasError() {
+				t.Fatalf("ParseContent: %v", diagnostics)
+			}
+
+			rendered, diagnostics := ExportContent(ctx, model, nil)
+			if diagnostics.HasError() {
+				t.Fatalf("ExportContent: %v", diagnostics)
+			}
+
+			if rendered != content {
+				t.Errorf("expected:\n%s\ngot:\n%s", content, rendered)
+			}
+		})
+	}
+}
+
+func TestParseContentAptPipeliningTrue(t *testing.T) {
+	// NOTE: cloud-init ignores `true`, so it has no attribute to be parsed into
+	_, diagnostics := ParseContent(context.Background(), expectedOutput("apt_pipelining: true"))
+	if !diagnostics.HasError() {
+		t.Fatalf("expected error, got none")
+	}
+}
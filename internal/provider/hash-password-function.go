@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+var _ function.Function = &HashPasswordFunction{}
+
+const (
+	hashSHA512Crypt = "sha512crypt"
+	hashSHA256Crypt = "sha256crypt"
+	hashYescrypt    = "yescrypt"
+)
+
+// hashAlgorithms
+// Supported algorithms, keyed by name and alias as accepted by `mkpasswd --method`
+var hashAlgorithms = map[string]func(password string, salt string) (string, error){
+	hashSHA512Crypt: utils.SHA512Crypt,
+	"sha-512":       utils.SHA512Crypt,
+	hashSHA256Crypt: utils.SHA256Crypt,
+	"sha-256":       utils.SHA256Crypt,
+	hashYescrypt:    utils.Yescrypt,
+}
+
+func NewHashPasswordFunction() function.Function {
+	return &HashPasswordFunction{}
+}
+
+// HashPasswordFunction
+// Same as `mkpasswd`, hashes a password into crypt(3) format accepted by `hashed_passwd`
+type HashPasswordFunction struct {
+}
+
+func (f *HashPasswordFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "hash_password"
+}
+
+func (f *HashPasswordFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Hash password in crypt(3) format",
+		MarkdownDescription: "Hashes a password the same way as `mkpasswd`, the result can be used as **hashed_passwd** of `users` or as **password** of `chpasswd.users` with `type = \"hash\"`. The same password and salt always produce the same hash, so plans stay stable. Provider-defined functions require Terraform 1.8 or later.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "password",
+				MarkdownDescription: "Password to hash",
+			},
+			function.StringParameter{
+				Name:                "algorithm",
+				MarkdownDescription: "Hashing algorithm: `" + hashSHA512Crypt + "` (alias `sha-512`), `" + hashSHA256Crypt + "` (alias `sha-256`) or `" + hashYescrypt + "`. SHA-crypt uses the default 5000 rounds, yescrypt the default parameters of libxcrypt (`$y$j9T$`).",
+			},
+			function.StringParameter{
+				Name:                "salt",
+				MarkdownDescription: "Salt of characters `./0-9A-Za-z`, e.g. `random_string` with `special = false`. SHA-crypt uses up to 16 characters, yescrypt requires the salt to encode whole bytes, which holds for lengths divisible by 4, e.g. 16. Functions must return the same result for the same arguments, so there is no random salt.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HashPasswordFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var password, algorithm, salt string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &password, &algorithm, &salt))
+
+	if resp.Error != nil {
+		return
+	}
+
+	hash, ok := hashAlgorithms[strings.ToLower(algorithm)]
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("unsupported algorithm %q, expected one of `%s`, `%s` or `%s`", algorithm, hashSHA512Crypt, hashSHA256Crypt, hashYescrypt))
+		return
+	}
+
+	result, err := hash(password, salt)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestHashPasswordFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// NOTE: expected hashes are produced by crypt(3) of libxcrypt
+			{
+				Config: `
+output "sha512" {
+  value = provider::cloud-config::hash_password("password", "sha512crypt", "saltsalt")
+}
+
+output "sha256" {
+  value = provider::cloud-config::hash_password("password", "SHA-256", "saltsalt")
+}
+
+output "yescrypt" {
+  value = provider::cloud-config::hash_password("password", "yescrypt", "saltsaltsalt")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue(
+						"sha512",
+						knownvalue.StringExact("$6$saltsalt$qFmFH.bQmmtXzyBY0s9v7Oicd2z4XSIecDzlB5KiA2/jctKu9YterLp8wwnSq.qc.eoxqOmSuNp2xS0ktL3nh/"),
+					),
+					statecheck.ExpectKnownOutputValue(
+						"sha256",
+						knownvalue.StringExact("$5$saltsalt$gOjOtoMpVhru2uyjeJSEc/JaLQWOXMNmlOnj6T4AtC."),
+					),
+					statecheck.ExpectKnownOutputValue(
+						"yescrypt",
+						knownvalue.StringExact("$y$j9T$saltsaltsalt$WJhblAc/BKcuw1LHqgcyvlsjC8J4ha9Wl82.5/aQSy8"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestHashPasswordFunctionHashedPasswd(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: wrapInput(`
+users {
+  name          = "admin"
+  hashed_passwd = provider::cloud-config::hash_password("password", "sha512crypt", "saltsalt")
+}
+
+chpasswd {
+  users {
+    name     = "root"
+    password = provider::cloud-config::hash_password("password", "yescrypt", "saltsaltsalt")
+    type     = "hash"
+  }
+}
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						resourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedOutput(`
+chpasswd:
+    users:
+        - name: root
+          password: $y$j9T$saltsaltsalt$WJhblAc/BKcuw1LHqgcyvlsjC8J4ha9Wl82.5/aQSy8
+          type: hash
+users:
+    - name: admin
+      hashed_passwd: $6$saltsalt$qFmFH.bQmmtXzyBY0s9v7Oicd2z4XSIecDzlB5KiA2/jctKu9YterLp8wwnSq.qc.eoxqOmSuNp2xS0ktL3nh/
+`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestHashPasswordFunctionErrors(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::hash_password("password", "md5", "saltsalt")
+}
+`,
+				ExpectError: regexp.MustCompile(`unsupported\s+algorithm\s+"md5"`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::hash_password("password", "sha512crypt", "salt$")
+}
+`,
+				ExpectError: regexp.MustCompile(`salt\s+may\s+only\s+contain`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::hash_password("password", "yescrypt", "abc")
+}
+`,
+				ExpectError: regexp.MustCompile(`not\s+a\s+valid\s+encoding`),
+			},
+		},
+	})
+}
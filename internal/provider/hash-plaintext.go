@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+// hashSalt
+// Seed of salts used by `hash_plaintext`, a random one is generated unless it is already known
+func hashSalt(value types.String) (types.String, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	if !value.IsNull() && !value.IsUnknown() {
+		return value, diagnostics
+	}
+
+	salt, err := utils.RandomCryptSalt()
+	if err != nil {
+		diagnostics.AddError("Cannot generate hash_salt", err.Error())
+		return value, diagnostics
+	}
+
+	return types.StringValue(salt), diagnostics
+}
+
+// usesHashPlaintext
+// Whether any password is hashed with `hash_plaintext`, so `hash_salt` is needed to render content
+func usesHashPlaintext(model CloudConfigModel) bool {
+	if model.User != nil && model.User.HashPlaintext.ValueBool() {
+		return true
+	}
+
+	if model.Users != nil {
+		for _, user := range *model.Users {
+			if user.HashPlaintext.ValueBool() {
+				return true
+			}
+		}
+	}
+
+	return model.ChPasswd != nil && model.ChPasswd.HashPlaintext.ValueBool()
+}
+
+// hashPlaintext
+// SHA-512-crypt hash of a plaintext password. Salt is derived from `hash_salt` and `key`, which names
+// the user, so equal passwords of different users get different hashes and reordering users changes nothing
+func hashPlaintext(model CloudConfigResourceModel, key string, password string) (string, error) {
+	if model.HashSalt.IsNull() || model.HashSalt.IsUnknown() {
+		return "", errors.New("`hash_salt` must be set to hash plaintext passwords")
+	}
+
+	return utils.SHA512Crypt(password, utils.DeriveCryptSalt(model.HashSalt.ValueString(), key))
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jinjaHat
+// cloud-init only renders documents, which start with this header
+const jinjaHat = "## template: jinja"
+
+// instanceDataV1Keys
+// Documented keys of `v1` namespace, both underscore and dash variants are available
+// @see https://cloudinit.readthedocs.io/en/latest/explanation/instancedata.html#v1
+var instanceDataV1Keys = []string{
+	"_beta_keys",
+	"availability_zone",
+	"availability-zone",
+	"cloud_id",
+	"cloud_name",
+	"cloud-name",
+	"distro",
+	"distro_release",
+	"distro_version",
+	"instance_id",
+	"instance-id",
+	"kernel_release",
+	"local_hostname",
+	"local-hostname",
+	"machine",
+	"platform",
+	"public_ssh_keys",
+	"public-ssh-keys",
+	"python_version",
+	"region",
+	"subplatform",
+	"system_platform",
+	"variant",
+}
+
+// instanceDataKeys
+// Top-level keys of instance-data, besides aliases of `v1` keys
+// @see https://cloudinit.readthedocs.io/en/latest/explanation/instancedata.html
+var instanceDataKeys = []string{
+	"base64_encoded_keys",
+	"combined_cloud_config",
+	"ds",
+	"features",
+	"merged_cfg",
+	"merged_system_cfg",
+	"sensitive_keys",
+	"sys_info",
+	"system_info",
+	"v1",
+	"vendordata",
+}
+
+var (
+	jinjaBlock       = regexp.MustCompile(`(?s){{.*?}}|{%.*?%}`)
+	jinjaV1Attr      = regexp.MustCompile(`(?:^|[^\w.])v1\s*\.\s*(\w+)`)
+	jinjaV1Subscript = regexp.MustCompile(`(?:^|[^\w.])v1\s*\[\s*['"]([^'"]+)['"]\s*\]`)
+)
+
+// isJinja
+// Whether the value contains an expression, statement or comment
+func isJinja(value string) bool {
+	return strings.Contains(value, "{{") || strings.Contains(value, "{%") || strings.Contains(value, "{#")
+}
+
+// jinjaLiteralStyle
+// Writes values with jinja as literal blocks, so they are neither quoted nor escaped
+func jinjaLiteralStyle(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag != "!!binary" && isJinja(node.Value) {
+		node.Style = yaml.LiteralStyle
+		return
+	}
+
+	for _, child := range node.Content {
+		jinjaLiteralStyle(child)
+	}
+}
+
+// jinjaVariables
+// Keys of `v1` namespace found in tags by regular expressions, a fallback for templates which cannot be parsed
+func jinjaVariables(content string) []string {
+	variables := []string{}
+
+	for _, block := range jinjaBlock.FindAllString(content, -1) {
+		for _, match := range jinjaV1Attr.FindAllStringSubmatch(block, -1) {
+			variables = append(variables, match[1])
+		}
+		for _, match := range jinjaV1Subscript.FindAllStringSubmatch(block, -1) {
+			variables = append(variables, match[1])
+		}
+	}
+
+	return variables
+}
+
+// jinjaTopLevelVariables
+// Names, which templates can read without `v1.` prefix: keys of instance-data and underscore aliases of `v1` keys
+func jinjaTopLevelVariables() []string {
+	variables := slices.Clone(instanceDataKeys)
+
+	for _, key := range instanceDataV1Keys {
+		if !strings.Contains(key, "-") {
+			variables = append(variables, key)
+		}
+	}
+
+	slices.Sort(variables)
+
+	return variables
+}
+
+// checkJinjaVariables
+// Reports `v1` keys and top-level variables, which are not documented instance-data.
+// Templates, which can't be parsed, are only checked for `v1` keys inside of tags
+func checkJinjaVariables(content string) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	// NOTE: the whole document is a template, the header is only a comment for Jinja
+	variables, err := utils.JinjaVariables(content)
+	if err != nil {
+		variables = []utils.JinjaVariable{{Name: "v1", Keys: jinjaVariables(content)}}
+	}
+
+	known := jinjaTopLevelVariables()
+
+	for _, variable := range variables {
+		if err == nil && !slices.Contains(known, variable.Name) {
+			diagnostics.AddAttributeError(
+				path.Root("template"),
+				"Unknown instance-data variable",
+				fmt.Sprintf("`%s` is not a documented instance-data variable, expected one of: %s", variable.Name, strings.Join(known, ", ")),
+			)
+		}
+
+		if variable.Name != "v1" {
+			continue
+		}
+
+		for _, key := range variable.Keys {
+			if slices.Contains(instanceDataV1Keys, key) {
+				continue
+			}
+
+			diagnostics.AddAttributeError(
+				path.Root("template"),
+				"Unknown instance-data variable",
+				fmt.Sprintf("`v1.%s` is not a documented instance-data key, expected one of: %s", key, strings.Join(instanceDataV1Keys, ", ")),
+			)
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+)
+
+var _ datasource.DataSource = &MetaDataDataSource{}
+
+func NewMetaDataDataSource() datasource.DataSource {
+	return &MetaDataDataSource{}
+}
+
+// MetaDataDataSource
+// Renders meta-data the same way as `MetaDataResource`, but without keeping it in state between runs
+type MetaDataDataSource struct {
+}
+
+func (d *MetaDataDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_meta_data"
+}
+
+func (d *MetaDataDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "NoCloud meta-data rendered at read time, the stateless counterpart of the `cloud-config_meta_data` resource",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "YAML or JSON content of meta-data file",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content`, encoded with base64",
+			},
+		},
+	}
+
+	module := ccmodules.MetaData()
+	maps.Insert(schema.Attributes, maps.All(module.DataSourceAttributes()))
+
+	resp.Schema = schema
+}
+
+func (d *MetaDataDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data MetaDataResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read a data source")
+
+	resp.Diagnostics.Append(setMetaDataContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"gopkg.in/yaml.v3"
+)
+
+var _ resource.Resource = &MetaDataResource{}
+var _ resource.ResourceWithModifyPlan = &MetaDataResource{}
+
+func NewMetaDataResource() resource.Resource {
+	return &MetaDataResource{}
+}
+
+// MetaDataResource
+// `meta-data` of NoCloud datasource, which describes the instance rather than configures it
+type MetaDataResource struct {
+}
+
+type MetaDataResourceModel struct {
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+
+	ccmodules.MetaDataModel
+}
+
+func (r *MetaDataResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_meta_data"
+}
+
+func (r *MetaDataResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "`meta-data` of [NoCloud](https://cloudinit.readthedocs.io/en/latest/reference/datasources/nocloud.html) datasource, provided along with user-data, e.g. as `meta-data` file of a seed.",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "YAML or JSON content of meta-data file",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content`, encoded with base64",
+			},
+		},
+	}
+
+	module := ccmodules.MetaData()
+	maps.Insert(schema.Attributes, maps.All(module.Attributes()))
+
+	resp.Schema = schema
+}
+
+func (r *MetaDataResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data MetaDataResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(setMetaDataContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MetaDataResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data MetaDataResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MetaDataResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data MetaDataResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setMetaDataContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MetaDataResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data MetaDataResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *MetaDataResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// NOTE: same as `cloud-config`, `content` is only rendered when every input is known
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var data MetaDataResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(setMetaDataContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("local_hostname"), data.LocalHostname)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), data.Content)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_base64"), data.ContentBase64)...)
+}
+
+// setMetaDataContent
+// Derives `local_hostname` and renders `content` along with its encoded representation
+func setMetaDataContent(ctx context.Context, data *MetaDataResourceModel) diag.Diagnostics {
+	if data.LocalHostname.IsNull() || data.LocalHostname.IsUnknown() {
+		data.LocalHostname = localHostname(data.MetaDataModel)
+	}
+
+	content, diagnostics := ExportMetaData(ctx, data.MetaDataModel)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	data.Content = types.StringValue(content)
+	data.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString([]byte(content)))
+
+	return diagnostics
+}
+
+// localHostname
+// Hostname picked by `set_hostname` module: `fqdn` if preferred, otherwise `hostname` or the first label of `fqdn`
+func localHostname(model ccmodules.MetaDataModel) types.String {
+	if !model.FQDN.IsNull() && model.PreferFQDNOverHostname.ValueBool() {
+		return model.FQDN
+	}
+
+	if !model.Hostname.IsNull() {
+		return model.Hostname
+	}
+
+	if !model.FQDN.IsNull() {
+		hostname, _, _ := strings.Cut(model.FQDN.ValueString(), ".")
+		return types.StringValue(hostname)
+	}
+
+	return types.StringNull()
+}
+
+// ExportMetaData
+// Renders the model into meta-data YAML or JSON
+func ExportMetaData(ctx context.Context, model ccmodules.MetaDataModel) (string, diag.Diagnostics) {
+	output := ccmodules.MetaDataOutputModel{
+		InstanceID:    model.InstanceID.ValueString(),
+		LocalHostname: model.LocalHostname.ValueStringPointer(),
+	}
+
+	publicKeys, diagnostics := castArray[string](ctx, model.PublicKeys)
+	if diagnostics.HasError() {
+		return "", diagnostics
+	}
+	output.PublicKeys = publicKeys
+
+	// NOTE: merging works on YAML nodes, which preserve the order of keys
+	var node yaml.Node
+	if err := node.Encode(output); err != nil {
+		return "", diag.Diagnostics{
+			diag.NewErrorDiagnostic("Cannot marshal YAML", err.Error()),
+		}
+	}
+
+	if !model.ExtraYAML.IsNull() {
+		diagnostics := mergeExtraYAML(&node, model.ExtraYAMLModel)
+		if diagnostics.HasError() {
+			return "", diagnostics
+		}
+	}
+
+	if model.Format.ValueString() == ccmodules.MetaDataFormatJSON {
+		var document any
+		if err := node.Decode(&document); err != nil {
+			return "", diag.Diagnostics{
+				diag.NewErrorDiagnostic("Cannot decode YAML", err.Error()),
+			}
+		}
+
+		json, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return "", diag.Diagnostics{
+				diag.NewErrorDiagnostic("Cannot marshal JSON", err.Error()),
+			}
+		}
+
+		return string(json), nil
+	}
+
+	yaml, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", diag.Diagnostics{
+			diag.NewErrorDiagnostic("Cannot marshal YAML", err.Error()),
+		}
+	}
+
+	return strings.TrimSpace(string(yaml)), nil
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const metaDataResourceName = "cloud-config_meta_data.test"
+
+func TestAccMetaDataResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config_meta_data" "test" {
+  instance_id = "i-0001"
+  fqdn        = "node1.example.com"
+  public_keys = ["ssh-ed25519 AAAA admin@example.com"]
+
+  extra_yaml = <<-EOT
+    dsmode: local
+  EOT
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(metaDataResourceName, tfjsonpath.New("local_hostname"), knownvalue.StringExact("node1")),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						metaDataResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact("instance-id: i-0001\nlocal-hostname: node1\npublic-keys:\n    - ssh-ed25519 AAAA admin@example.com\ndsmode: local"),
+					),
+				},
+			},
+			{
+				Config: `
+resource "cloud-config_meta_data" "test" {
+  format                    = "json"
+  instance_id               = "i-0001"
+  fqdn                      = "node1.example.com"
+  prefer_fqdn_over_hostname = true
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						metaDataResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact("{\n  \"instance-id\": \"i-0001\",\n  \"local-hostname\": \"node1.example.com\"\n}"),
+					),
+					statecheck.ExpectKnownValue(
+						metaDataResourceName,
+						tfjsonpath.New("content_base64"),
+						knownvalue.StringExact("ewogICJpbnN0YW5jZS1pZCI6ICJpLTAwMDEiLAogICJsb2NhbC1ob3N0bmFtZSI6ICJub2RlMS5leGFtcGxlLmNvbSIKfQ=="),
+					),
+				},
+			},
+			{
+				Config: `
+resource "cloud-config_meta_data" "test" {
+  instance_id    = "i-0002"
+  local_hostname = "custom"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						metaDataResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact("instance-id: i-0002\nlocal-hostname: custom"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccMetaDataDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config" "test" {
+  hostname = "node2"
+  fqdn     = "node2.example.com"
+}
+
+data "cloud-config_meta_data" "test" {
+  instance_id = "i-0002"
+  hostname    = resource.cloud-config.test.hostname
+  fqdn        = resource.cloud-config.test.fqdn
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.cloud-config_meta_data.test",
+						tfjsonpath.New("content"),
+						knownvalue.StringExact("instance-id: i-0002\nlocal-hostname: node2"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccMetaDataResourceInvalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+		error  string
+	}{
+		{
+			name: "local_hostname with hostname",
+			config: `
+instance_id    = "i-0001"
+local_hostname = "custom"
+hostname       = "node1"
+`,
+			error: `Invalid Attribute Combination`,
+		},
+		{
+			name: "conflicting extra_yaml",
+			config: `
+instance_id = "i-0001"
+extra_yaml  = "instance-id: i-0002"
+`,
+			error: `Conflicting extra_yaml`,
+		},
+		{
+			name: "unknown format",
+			config: `
+instance_id = "i-0001"
+format      = "toml"
+`,
+			error: `value must be one of`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      `resource "cloud-config_meta_data" "test" {` + tc.config + `}`,
+						ExpectError: regexp.MustCompile(tc.error),
+					},
+				},
+			})
+		})
+	}
+}
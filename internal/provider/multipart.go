@@ -0,0 +1,441 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"mime/multipart"
+	"net/textproto"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+const defaultBoundary = "MIMEBOUNDARY"
+
+var _ resource.Resource = &MultipartResource{}
+var _ resource.ResourceWithModifyPlan = &MultipartResource{}
+var _ resource.ResourceWithValidateConfig = &MultipartResource{}
+
+func NewMultipartResource() resource.Resource {
+	return &MultipartResource{}
+}
+
+// MultipartResource
+// MIME multi-part user-data, same format as `cloudinit_config` data source of `hashicorp/cloudinit`
+type MultipartResource struct {
+}
+
+type MultipartPart struct {
+	ContentType types.String      `tfsdk:"content_type"`
+	Filename    types.String      `tfsdk:"filename"`
+	MergeType   types.String      `tfsdk:"merge_type"`
+	Content     types.String      `tfsdk:"content"`
+	CloudConfig *CloudConfigModel `tfsdk:"cloud_config"`
+}
+
+type MultipartResourceModel struct {
+	Content      types.String     `tfsdk:"content"`
+	Boundary     types.String     `tfsdk:"boundary"`
+	Gzip         types.Bool       `tfsdk:"gzip"`
+	Base64Encode types.Bool       `tfsdk:"base64_encode"`
+	HashSalt     types.String     `tfsdk:"hash_salt"`
+	Parts        *[]MultipartPart `tfsdk:"part"`
+}
+
+func (r *MultipartResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_multipart"
+}
+
+func (r *MultipartResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	cloudConfig := schema.SingleNestedBlock{
+		MarkdownDescription: "Cloud-config rendered from the same attributes and blocks as the `cloud-config` resource. Conflicts with **content**.",
+		Attributes:          map[string]schema.Attribute{},
+		Blocks:              map[string]schema.Block{},
+	}
+
+	for _, module := range flatModules() {
+		maps.Insert(cloudConfig.Attributes, maps.All(module.Attributes()))
+	}
+
+	for _, module := range blockModules() {
+		maps.Insert(cloudConfig.Blocks, maps.All(module.Block()))
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "MIME multi-part user-data, compatible with the `cloudinit_config` data source of `hashicorp/cloudinit`",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Rendered MIME multi-part archive",
+			},
+			"boundary": schema.StringAttribute{
+				MarkdownDescription: "Boundary which separates parts of the archive. *Default*: `" + defaultBoundary + "`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 70),
+				},
+			},
+			"hash_salt": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Seed of salts used to hash passwords with **hash_plaintext** in **cloud_config** parts. Unless set, a random one is generated on creation and kept in state, so hashes stay the same across plans. With **hash_plaintext**, `content` of a new resource is therefore only known after apply.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"gzip": schema.BoolAttribute{
+				MarkdownDescription: "Compress the archive with gzip. Requires **base64_encode**, as the compressed archive is binary. *Default*: `false`.",
+				Optional:            true,
+			},
+			"base64_encode": schema.BoolAttribute{
+				MarkdownDescription: "Encode the archive with base64. *Default*: `false`.",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"part": schema.ListNestedBlock{
+				MarkdownDescription: "Part of the archive, parts are rendered in the order they are defined",
+				Validators: []validator.List{
+					listvalidator.IsRequired(),
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"content_type": schema.StringAttribute{
+							MarkdownDescription: "MIME type of the part, e.g. `text/cloud-config`, `text/x-shellscript`, `text/cloud-boothook`, `text/jinja2`, `text/part-handler` or `text/x-include-url`. *Default*: `text/cloud-config` for **cloud_config**, `text/plain` otherwise.",
+							Optional:            true,
+						},
+						"filename": schema.StringAttribute{
+							MarkdownDescription: "Filename of the part, set as `Content-Disposition` header.",
+							Optional:            true,
+						},
+						"merge_type": schema.StringAttribute{
+							MarkdownDescription: "How cloud-init merges this part with the previous ones, set as `X-Merge-Type` header, e.g. `list(append)+dict(no_replace,recurse_list)+str()`.",
+							Optional:            true,
+						},
+						"content": schema.StringAttribute{
+							MarkdownDescription: "Raw content of the part. Conflicts with **cloud_config**.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("cloud_config")),
+							},
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"cloud_config": cloudConfig,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *MultipartResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+}
+
+func (r *MultipartResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data, config MultipartResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// NOTE: write-only attributes are null in the plan, they are only available in configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	var diagnostics diag.Diagnostics
+	data.HashSalt, diagnostics = hashSalt(data.HashSalt)
+	resp.Diagnostics.Append(diagnostics...)
+	config.HashSalt = data.HashSalt
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, err := ExportMultipart(ctx, config)
+	if err != nil {
+		resp.Diagnostics.Append(err...)
+		return
+	}
+
+	data.Content = types.StringValue(content)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MultipartResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data MultipartResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MultipartResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, config MultipartResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// NOTE: write-only attributes are null in the plan, they are only available in configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diagnostics diag.Diagnostics
+	data.HashSalt, diagnostics = hashSalt(data.HashSalt)
+	resp.Diagnostics.Append(diagnostics...)
+	config.HashSalt = data.HashSalt
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, err := ExportMultipart(ctx, config)
+	if err != nil {
+		resp.Diagnostics.Append(err...)
+		return
+	}
+
+	data.Content = types.StringValue(content)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MultipartResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data MultipartResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *MultipartResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// NOTE: same as `cloud-config`, `content` is only rendered when every input is known
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var data, config MultipartResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	// NOTE: write-only attributes are null in the plan, they are only available in configuration
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NOTE: same as `cloud-config`, `hash_salt` is generated on creation, passwords can't be hashed before that
+	if data.HashSalt.IsUnknown() && config.Parts != nil && slices.ContainsFunc(*config.Parts, func(part MultipartPart) bool {
+		return part.CloudConfig != nil && usesHashPlaintext(*part.CloudConfig)
+	}) {
+		return
+	}
+
+	config.HashSalt = data.HashSalt
+
+	content, err := ExportMultipart(ctx, config)
+	if err != nil {
+		resp.Diagnostics.Append(err...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), types.StringValue(content))...)
+}
+
+func (r *MultipartResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var gzipped, encoded types.Bool
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("gzip"), &gzipped)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("base64_encode"), &encoded)...)
+
+	if resp.Diagnostics.HasError() || gzipped.IsUnknown() || encoded.IsUnknown() {
+		return
+	}
+
+	if gzipped.ValueBool() && !encoded.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base64_encode"),
+			"Invalid Attribute Combination",
+			"`base64_encode` must be `true` when `gzip` is enabled, compressed archive can't be stored as a string otherwise.",
+		)
+	}
+}
+
+// ExportMultipart
+// Renders every part into MIME multi-part archive
+func ExportMultipart(ctx context.Context, data MultipartResourceModel) (string, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	boundary := defaultBoundary
+	if !data.Boundary.IsNull() {
+		boundary = data.Boundary.ValueString()
+	}
+
+	var buf bytes.Buffer
+
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(boundary); err != nil {
+		diagnostics.AddAttributeError(path.Root("boundary"), "Invalid boundary", err.Error())
+		return "", diagnostics
+	}
+
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", boundary)
+
+	if data.Parts != nil {
+		for i, part := range *data.Parts {
+			content := part.Content.ValueString()
+			contentType := "text/plain"
+
+			if part.CloudConfig != nil {
+				at := path.Root("part").AtListIndex(i).AtName("cloud_config")
+
+				if usesWriteOnly(*part.CloudConfig) {
+					diagnostics.AddAttributeError(
+						at,
+						"Write-only attributes are not supported in parts",
+						"`content` of the archive is stored in state, so it can't carry values of write-only attributes. Render such parts with the ephemeral `cloud-config` resource instead.",
+					)
+					return "", diagnostics
+				}
+
+				model := CloudConfigResourceModel{CloudConfigModel: *part.CloudConfig, HashSalt: data.HashSalt}
+
+				rendered, d := ExportContent(ctx, model, nil)
+				diagnostics.Append(withBasePath(at, d)...)
+				if diagnostics.HasError() {
+					return "", diagnostics
+				}
+
+				diagnostics.Append(validateSchema(ctx, rendered, at, model)...)
+				if diagnostics.HasError() {
+					return "", diagnostics
+				}
+
+				content = rendered
+				contentType = "text/cloud-config"
+
+				// NOTE: cloud-init only renders templates of parts with jinja type
+				if part.CloudConfig.Template.ValueString() == ccmodules.TemplateJinja {
+					contentType = "text/jinja2"
+				}
+			}
+
+			if !part.ContentType.IsNull() {
+				contentType = part.ContentType.ValueString()
+			}
+
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", contentType)
+			header.Set("Content-Transfer-Encoding", "7bit")
+			header.Set("Mime-Version", "1.0")
+
+			if !part.Filename.IsNull() {
+				header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", part.Filename.ValueString()))
+			}
+
+			if !part.MergeType.IsNull() {
+				header.Set("X-Merge-Type", part.MergeType.ValueString())
+			}
+
+			w, err := writer.CreatePart(header)
+			if err != nil {
+				diagnostics.AddAttributeError(path.Root("part").AtListIndex(i), "Cannot write part", err.Error())
+				return "", diagnostics
+			}
+
+			if _, err := w.Write([]byte(content)); err != nil {
+				diagnostics.AddAttributeError(path.Root("part").AtListIndex(i), "Cannot write part", err.Error())
+				return "", diagnostics
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		diagnostics.AddError("Cannot write MIME archive", err.Error())
+		return "", diagnostics
+	}
+
+	archive := buf.Bytes()
+
+	if data.Gzip.ValueBool() {
+		compressed, err := utils.Gzip(archive)
+		if err != nil {
+			diagnostics.AddError("Cannot compress MIME archive", err.Error())
+			return "", diagnostics
+		}
+
+		archive = compressed
+	}
+
+	if data.Base64Encode.ValueBool() {
+		return base64.StdEncoding.EncodeToString(archive), diagnostics
+	}
+
+	return string(archive), diagnostics
+}
+
+// withBasePath
+// Moves diagnostics of a nested cloud-config under `base`, those without a path point to `base` itself
+func withBasePath(base path.Path, diagnostics diag.Diagnostics) diag.Diagnostics {
+	result := make(diag.Diagnostics, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		at := base
+
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			for _, step := range withPath.Path().Steps() {
+				switch step := step.(type) {
+				case path.PathStepAttributeName:
+					at = at.AtName(string(step))
+				case path.PathStepElementKeyInt:
+					at = at.AtListIndex(int(step))
+				case path.PathStepElementKeyString:
+					at = at.AtMapKey(string(step))
+				case path.PathStepElementKeyValue:
+					at = at.AtSetValue(step.Value)
+				}
+			}
+		}
+
+		result = append(result, diag.WithPath(at, d))
+	}
+
+	return result
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+const multipartResourceName = "cloud-config_multipart.test"
+
+func expectedMultipart(parts ...string) string {
+	return "Content-Type: multipart/mixed; boundary=\"MIMEBOUNDARY\"\r\nMIME-Version: 1.0\r\n\r\n" +
+		"--MIMEBOUNDARY\r\n" +
+		strings.Join(parts, "\r\n--MIMEBOUNDARY\r\n") +
+		"\r\n--MIMEBOUNDARY--\r\n"
+}
+
+func TestAccMultipartResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config_multipart" "test" {
+  part {
+    filename = "cloud-config.yaml"
+
+    cloud_config {
+      hostname = "one"
+      runcmd   = ["echo '11'"]
+    }
+  }
+
+  part {
+    content_type = "text/x-shellscript"
+    merge_type   = "list(append)+dict(no_replace,recurse_list)+str()"
+    content      = "#!/bin/sh\necho hello\n"
+  }
+
+  part {
+    content_type = "text/cloud-boothook"
+    content      = "#cloud-boothook\necho boothook\n"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						multipartResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(expectedMultipart(
+							"Content-Disposition: attachment; filename=\"cloud-config.yaml\"\r\n"+
+								"Content-Transfer-Encoding: 7bit\r\n"+
+								"Content-Type: text/cloud-config\r\n"+
+								"Mime-Version: 1.0\r\n\r\n"+
+								expectedOutput("hostname: one\nruncmd:\n    - echo '11'"),
+							"Content-Transfer-Encoding: 7bit\r\n"+
+								"Content-Type: text/x-shellscript\r\n"+
+								"Mime-Version: 1.0\r\n"+
+								"X-Merge-Type: list(append)+dict(no_replace,recurse_list)+str()\r\n\r\n"+
+								"#!/bin/sh\necho hello\n",
+							"Content-Transfer-Encoding: 7bit\r\n"+
+								"Content-Type: text/cloud-boothook\r\n"+
+								"Mime-Version: 1.0\r\n\r\n"+
+								"#cloud-boothook\necho boothook\n",
+						)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccMultipartResourceGzip(t *testing.T) {
+	expected := expectedMultipart(
+		"Content-Transfer-Encoding: 7bit\r\n" +
+			"Content-Type: text/x-shellscript\r\n" +
+			"Mime-Version: 1.0\r\n\r\n" +
+			"#!/bin/sh\necho hello\n",
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config_multipart" "test" {
+  gzip          = true
+  base64_encode = true
+
+  part {
+    content_type = "text/x-shellscript"
+    content      = "#!/bin/sh\necho hello\n"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						multipartResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringFunc(func(v string) error {
+							compressed, err := base64.StdEncoding.DecodeString(v)
+							if err != nil {
+								return err
+							}
+
+							reader, err := gzip.NewReader(bytes.NewReader(compressed))
+							if err != nil {
+								return err
+							}
+
+							archive, err := io.ReadAll(reader)
+							if err != nil {
+								return err
+							}
+
+							if string(archive) != expected {
+								return fmt.Errorf("expected %q, got %q", expected, archive)
+							}
+
+							return nil
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccMultipartResourceInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config_multipart" "test" {
+  gzip = true
+
+  part {
+    content = "#!/bin/sh"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("must be `true` when `gzip` is enabled"),
+			},
+			{
+				Config: `
+resource "cloud-config_multipart" "test" {
+  part {
+    content = "#!/bin/sh"
+
+    cloud_config {
+      hostname = "one"
+    }
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			// Errors of a part point to its attributes
+			{
+				Config: `
+resource "cloud-config_multipart" "test" {
+  part {
+    cloud_config {
+      template = "jinja"
+      hostname = "{{ v1.instance_name }}"
+    }
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)in resource "cloud-config_multipart" "test":.*template = "jinja".*is not a documented instance-data key`),
+			},
+		},
+	})
+}
+
+func TestAccMultipartResourceWriteOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Write-only attributes are available since 1.11.0
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// `content` of the archive is stored in state, so it can't carry secrets
+			{
+				Config: `
+resource "cloud-config_multipart" "test" {
+  part {
+    cloud_config {
+      salt_minion {
+        private_key_wo = "private"
+      }
+    }
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("Write-only attributes are not supported in parts"),
+			},
+		},
+	})
+}
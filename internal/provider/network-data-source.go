@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+)
+
+var _ datasource.DataSource = &NetworkDataSource{}
+
+func NewNetworkDataSource() datasource.DataSource {
+	return &NetworkDataSource{}
+}
+
+// NetworkDataSource
+// Renders network-config the same way as `NetworkResource`, but without keeping it in state between runs
+type NetworkDataSource struct {
+}
+
+func (d *NetworkDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+func (d *NetworkDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "Network configuration rendered at read time, the stateless counterpart of the `cloud-config_network` resource",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "YAML content of network-config file",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content`, encoded with base64",
+			},
+		},
+		Blocks: map[string]schema.Block{},
+	}
+
+	module, blocks := ccmodules.NetworkConfig(), ccmodules.NetworkConfigBlock()
+	maps.Insert(schema.Attributes, maps.All(module.DataSourceAttributes()))
+	maps.Insert(schema.Blocks, maps.All(blocks.DataSourceBlock()))
+
+	resp.Schema = schema
+}
+
+func (d *NetworkDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read a data source")
+
+	resp.Diagnostics.Append(setNetworkContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
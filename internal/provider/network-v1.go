@@ -0,0 +1,424 @@
+package provider
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+)
+
+const networkV1Unsupported = "Unsupported in network-config version 1"
+
+// networkV1Device
+// Entry of a device, along with IDs of devices it is built on
+type networkV1Device struct {
+	id        string
+	entry     ccmodules.NetworkV1ConfigOutput
+	dependsOn []string
+}
+
+// transformNetworkV1
+// Converts blocks into entries of version 1 config. Anything version 1 can't express is reported at its attribute,
+// instead of being silently dropped
+func transformNetworkV1(ctx context.Context, model ccmodules.NetworkConfigModel) (ccmodules.NetworkV1OutputModel, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	output := ccmodules.NetworkV1OutputModel{
+		Version: 1,
+		Config:  []ccmodules.NetworkV1ConfigOutput{},
+	}
+
+	if !model.Renderer.IsNull() {
+		diagnostics.AddAttributeError(path.Root("renderer"), networkV1Unsupported, "Version 1 doesn't select a renderer, remove `renderer` or use version 2.")
+	}
+
+	ids := networkIDs{}
+	devices := []networkV1Device{}
+	extra := []ccmodules.NetworkV1ConfigOutput{}
+
+	// NOTE: ethernets may be renamed with `set_name`, other devices refer to them by ID
+	names := map[string]string{}
+	name := func(id string) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+
+		return id
+	}
+
+	if model.Ethernets != nil {
+		for _, ethernet := range *model.Ethernets {
+			if !ethernet.SetName.IsNull() {
+				names[ethernet.ID.ValueString()] = ethernet.SetName.ValueString()
+			}
+		}
+	}
+
+	if model.Ethernets != nil {
+		for i, ethernet := range *model.Ethernets {
+			at := path.Root("ethernets").AtListIndex(i)
+			if !ids.add(at, ethernet.ID.ValueString(), &diagnostics) {
+				continue
+			}
+
+			if !ethernet.MACAddress.IsNull() {
+				diagnostics.AddAttributeError(at.AtName("macaddress"), networkV1Unsupported, "Version 1 can't change MAC address of physical interfaces, it only matches them, use `match` with `set_name` or version 2.")
+			}
+
+			entry, entries, d := transformNetworkV1Device(ctx, at, ethernet.NetworkDevice)
+			diagnostics.Append(d...)
+
+			ethernetName := name(ethernet.ID.ValueString())
+
+			entry.Type = "physical"
+			entry.Name = &ethernetName
+			entry.MACAddress = nil
+			entry.WakeOnLAN = ethernet.WakeOnLAN.ValueBoolPointer()
+
+			if match := ethernet.Match; match != nil {
+				if !match.Name.IsNull() {
+					diagnostics.AddAttributeError(at.AtName("match").AtName("name"), networkV1Unsupported, "Version 1 matches physical interfaces only by MAC address, use `macaddress` or version 2.")
+				}
+
+				if !match.Driver.IsNull() {
+					diagnostics.AddAttributeError(at.AtName("match").AtName("driver"), networkV1Unsupported, "Version 1 matches physical interfaces only by MAC address, use `macaddress` or version 2.")
+				}
+
+				// NOTE: matched interface is always renamed to `name` in version 1
+				if !match.MACAddress.IsNull() && ethernet.SetName.IsNull() {
+					diagnostics.AddAttributeError(at.AtName("set_name"), networkV1Unsupported, "Version 1 renames the matched interface, `set_name` must be set along with `match`, or use version 2.")
+				}
+
+				entry.MACAddress = match.MACAddress.ValueStringPointer()
+			}
+
+			devices = append(devices, networkV1Device{id: ethernet.ID.ValueString(), entry: entry})
+			extra = append(extra, entries...)
+		}
+	}
+
+	if model.Bonds != nil {
+		for i, bond := range *model.Bonds {
+			at := path.Root("bonds").AtListIndex(i)
+			if !ids.add(at, bond.ID.ValueString(), &diagnostics) {
+				continue
+			}
+
+			entry, entries, d := transformNetworkV1Device(ctx, at, bond.NetworkDevice)
+			diagnostics.Append(d...)
+
+			interfaces, d := castArray[string](ctx, bond.Interfaces)
+			diagnostics.Append(d...)
+
+			entry.Type = "bond"
+			entry.Name = bond.ID.ValueStringPointer()
+			entry.BondInterfaces = networkV1Names(interfaces, name)
+
+			if params := bond.Parameters; params != nil {
+				out := &ccmodules.NetworkV1BondParametersOutput{
+					Mode:               params.Mode.ValueStringPointer(),
+					LACPRate:           params.LACPRate.ValueStringPointer(),
+					MIIMonitorInterval: params.MIIMonitorInterval.ValueInt64Pointer(),
+					MinLinks:           params.MinLinks.ValueInt64Pointer(),
+					TransmitHashPolicy: params.TransmitHashPolicy.ValueStringPointer(),
+					UpDelay:            params.UpDelay.ValueInt64Pointer(),
+					DownDelay:          params.DownDelay.ValueInt64Pointer(),
+				}
+
+				if !params.Primary.IsNull() {
+					primary := name(params.Primary.ValueString())
+					out.Primary = &primary
+				}
+
+				entry.Params = out
+			}
+
+			device := networkV1Device{id: bond.ID.ValueString(), entry: entry}
+			if interfaces != nil {
+				device.dependsOn = *interfaces
+			}
+
+			devices = append(devices, device)
+			extra = append(extra, entries...)
+		}
+	}
+
+	if model.Bridges != nil {
+		for i, bridge := range *model.Bridges {
+			at := path.Root("bridges").AtListIndex(i)
+			if !ids.add(at, bridge.ID.ValueString(), &diagnostics) {
+				continue
+			}
+
+			entry, entries, d := transformNetworkV1Device(ctx, at, bridge.NetworkDevice)
+			diagnostics.Append(d...)
+
+			interfaces, d := castArray[string](ctx, bridge.Interfaces)
+			diagnostics.Append(d...)
+
+			entry.Type = "bridge"
+			entry.Name = bridge.ID.ValueStringPointer()
+			entry.BridgeInterfaces = networkV1Names(interfaces, name)
+
+			if params := bridge.Parameters; params != nil {
+				entry.Params = &ccmodules.NetworkV1BridgeParametersOutput{
+					AgeingTime:   params.AgeingTime.ValueInt64Pointer(),
+					Priority:     params.Priority.ValueInt64Pointer(),
+					ForwardDelay: params.ForwardDelay.ValueInt64Pointer(),
+					HelloTime:    params.HelloTime.ValueInt64Pointer(),
+					MaxAge:       params.MaxAge.ValueInt64Pointer(),
+					STP:          params.STP.ValueBoolPointer(),
+				}
+			}
+
+			device := networkV1Device{id: bridge.ID.ValueString(), entry: entry}
+			if interfaces != nil {
+				device.dependsOn = *interfaces
+			}
+
+			devices = append(devices, device)
+			extra = append(extra, entries...)
+		}
+	}
+
+	if model.VLANs != nil {
+		for i, vlan := range *model.VLANs {
+			at := path.Root("vlans").AtListIndex(i)
+			if !ids.add(at, vlan.ID.ValueString(), &diagnostics) {
+				continue
+			}
+
+			entry, entries, d := transformNetworkV1Device(ctx, at, vlan.NetworkDevice)
+			diagnostics.Append(d...)
+
+			link := name(vlan.Link.ValueString())
+
+			entry.Type = "vlan"
+			entry.Name = vlan.ID.ValueStringPointer()
+			entry.VLANID = vlan.VLANID.ValueInt64Pointer()
+			entry.VLANLink = &link
+
+			devices = append(devices, networkV1Device{id: vlan.ID.ValueString(), entry: entry, dependsOn: []string{vlan.Link.ValueString()}})
+			extra = append(extra, entries...)
+		}
+	}
+
+	// NOTE: cloud-init expects interfaces of bonds, bridges and vlans to be defined before them
+	byID := make(map[string]networkV1Device, len(devices))
+	for _, device := range devices {
+		byID[device.id] = device
+	}
+
+	visited := map[string]bool{}
+	var visit func(device networkV1Device)
+	visit = func(device networkV1Device) {
+		if visited[device.id] {
+			return
+		}
+
+		visited[device.id] = true
+
+		for _, id := range device.dependsOn {
+			if dependency, ok := byID[id]; ok {
+				visit(dependency)
+			}
+		}
+
+		output.Config = append(output.Config, device.entry)
+	}
+
+	for _, device := range devices {
+		visit(device)
+	}
+
+	output.Config = append(output.Config, extra...)
+
+	return output, diagnostics
+}
+
+// transformNetworkV1Device
+// Properties shared by every kind of device. Routes and nameservers, which can't be attached to a subnet,
+// are returned as separate entries
+func transformNetworkV1Device(ctx context.Context, at path.Path, device ccmodules.NetworkDevice) (ccmodules.NetworkV1ConfigOutput, []ccmodules.NetworkV1ConfigOutput, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+	var entries []ccmodules.NetworkV1ConfigOutput
+
+	out := ccmodules.NetworkV1ConfigOutput{
+		MACAddress: device.MACAddress.ValueStringPointer(),
+		MTU:        device.MTU.ValueInt64Pointer(),
+		AcceptRA:   device.AcceptRA.ValueBoolPointer(),
+	}
+
+	if device.Optional.ValueBool() {
+		diagnostics.AddAttributeError(at.AtName("optional"), networkV1Unsupported, "Version 1 can't mark devices optional, remove `optional` or use version 2.")
+	}
+
+	if device.RoutingPolicy != nil && len(*device.RoutingPolicy) > 0 {
+		diagnostics.AddAttributeError(at.AtName("routing_policy"), networkV1Unsupported, "Version 1 has no policy routing, remove `routing_policy` or use version 2.")
+	}
+
+	subnets := []ccmodules.NetworkV1SubnetOutput{}
+
+	if device.DHCP4.ValueBool() {
+		subnets = append(subnets, ccmodules.NetworkV1SubnetOutput{Type: "dhcp4"})
+	}
+
+	if device.DHCP6.ValueBool() {
+		subnets = append(subnets, ccmodules.NetworkV1SubnetOutput{Type: "dhcp6"})
+	}
+
+	addresses, d := castArray[string](ctx, device.Addresses)
+	diagnostics.Append(d...)
+
+	// NOTE: gateways and nameservers belong to static subnets in version 1, the first one of a family is used
+	static4, static6 := -1, -1
+	if addresses == nil {
+		addresses = &[]string{}
+	}
+
+	for _, address := range *addresses {
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			continue
+		}
+
+		subnet := ccmodules.NetworkV1SubnetOutput{Type: "static", Address: &address}
+
+		if prefix.Addr().Is4() {
+			if static4 < 0 {
+				static4 = len(subnets)
+			}
+		} else {
+			subnet.Type = "static6"
+
+			if static6 < 0 {
+				static6 = len(subnets)
+			}
+		}
+
+		subnets = append(subnets, subnet)
+	}
+
+	if !device.Gateway4.IsNull() {
+		if static4 < 0 {
+			diagnostics.AddAttributeError(at.AtName("gateway4"), networkV1Unsupported, "Gateway belongs to a static subnet in version 1, add an IPv4 address to `addresses` or use version 2.")
+		} else {
+			subnets[static4].Gateway = device.Gateway4.ValueStringPointer()
+		}
+	}
+
+	if !device.Gateway6.IsNull() {
+		if static6 < 0 {
+			diagnostics.AddAttributeError(at.AtName("gateway6"), networkV1Unsupported, "Gateway belongs to a static subnet in version 1, add an IPv6 address to `addresses` or use version 2.")
+		} else {
+			subnets[static6].Gateway = device.Gateway6.ValueStringPointer()
+		}
+	}
+
+	if device.Nameservers != nil {
+		nameservers, d := castArray[string](ctx, device.Nameservers.Addresses)
+		diagnostics.Append(d...)
+
+		search, d := castArray[string](ctx, device.Nameservers.Search)
+		diagnostics.Append(d...)
+
+		static := static4
+		if static < 0 {
+			static = static6
+		}
+
+		if static >= 0 {
+			subnets[static].DNSNameservers = nameservers
+			subnets[static].DNSSearch = search
+		} else {
+			// NOTE: `address` is required by nameserver entries
+			if nameservers == nil {
+				nameservers = &[]string{}
+			}
+
+			entries = append(entries, ccmodules.NetworkV1ConfigOutput{
+				Type:    "nameserver",
+				Address: nameservers,
+				Search:  search,
+			})
+		}
+	}
+
+	if len(subnets) > 0 {
+		out.Subnets = &subnets
+	}
+
+	if device.Routes != nil {
+		for i, route := range *device.Routes {
+			rat := at.AtName("routes").AtListIndex(i)
+
+			if !route.Table.IsNull() {
+				diagnostics.AddAttributeError(rat.AtName("table"), networkV1Unsupported, "Version 1 routes can't select a table, remove `table` or use version 2.")
+			}
+
+			if route.OnLink.ValueBool() {
+				diagnostics.AddAttributeError(rat.AtName("on_link"), networkV1Unsupported, "Version 1 routes can't be on-link, remove `on_link` or use version 2.")
+			}
+
+			if !route.Type.IsNull() && route.Type.ValueString() != "unicast" {
+				diagnostics.AddAttributeError(rat.AtName("type"), networkV1Unsupported, "Version 1 has only unicast routes, remove `type` or use version 2.")
+			}
+
+			if route.Via.IsNull() {
+				diagnostics.AddAttributeError(rat.AtName("via"), networkV1Unsupported, "Version 1 routes need a gateway, set `via` or use version 2.")
+				continue
+			}
+
+			destination := networkV1Destination(route.To.ValueString(), route.Via.ValueString())
+
+			entries = append(entries, ccmodules.NetworkV1ConfigOutput{
+				Type:        "route",
+				Destination: &destination,
+				Gateway:     route.Via.ValueStringPointer(),
+				Metric:      route.Metric.ValueInt64Pointer(),
+			})
+		}
+	}
+
+	return out, entries, diagnostics
+}
+
+// networkV1Destination
+// Version 1 has no `default` keyword and expects a prefix length, family of the default route follows the gateway
+func networkV1Destination(to string, via string) string {
+	if to == "default" {
+		if gateway, err := netip.ParseAddr(via); err == nil && gateway.Is6() {
+			return "::/0"
+		}
+
+		return "0.0.0.0/0"
+	}
+
+	if strings.Contains(to, "/") {
+		return to
+	}
+
+	if addr, err := netip.ParseAddr(to); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()).String()
+	}
+
+	return to
+}
+
+// networkV1Names
+// Maps IDs of devices into names of interfaces
+func networkV1Names(ids *[]string, name func(id string) string) *[]string {
+	if ids == nil {
+		return nil
+	}
+
+	names := make([]string, len(*ids))
+	for i, id := range *ids {
+		names[i] = name(id)
+	}
+
+	return &names
+}
@@ -0,0 +1,408 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"maps"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"gopkg.in/yaml.v3"
+)
+
+var _ resource.Resource = &NetworkResource{}
+var _ resource.ResourceWithModifyPlan = &NetworkResource{}
+
+func NewNetworkResource() resource.Resource {
+	return &NetworkResource{}
+}
+
+// NetworkResource
+// Network configuration of cloud-init, which is provided apart from user-data, e.g. as `network-config` of NoCloud
+type NetworkResource struct {
+}
+
+type NetworkResourceModel struct {
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+
+	ccmodules.NetworkConfigModel
+}
+
+func (r *NetworkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network"
+}
+
+func (r *NetworkResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "Network configuration of cloud-init in [version 2](https://cloudinit.readthedocs.io/en/latest/reference/network-config-format-v2.html) (netplan) format, or converted into [version 1](https://cloudinit.readthedocs.io/en/latest/reference/network-config-format-v1.html) with `version = 1`. Unlike user-data, it is provided separately, e.g. as `network-config` file of a NoCloud seed.",
+
+		Attributes: map[string]schema.Attribute{
+			"content": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "YAML content of network-config file",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "`content`, encoded with base64",
+			},
+		},
+		Blocks: map[string]schema.Block{},
+	}
+
+	module, blocks := ccmodules.NetworkConfig(), ccmodules.NetworkConfigBlock()
+	maps.Insert(schema.Attributes, maps.All(module.Attributes()))
+	maps.Insert(schema.Blocks, maps.All(blocks.Block()))
+
+	resp.Schema = schema
+}
+
+func (r *NetworkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(setNetworkContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NetworkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NetworkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(setNetworkContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *NetworkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *NetworkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// NOTE: same as `cloud-config`, `content` is only rendered when every input is known
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var data NetworkResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(setNetworkContent(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), data.Content)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_base64"), data.ContentBase64)...)
+}
+
+// setNetworkContent
+// Renders `content` along with its encoded representation
+func setNetworkContent(ctx context.Context, data *NetworkResourceModel) diag.Diagnostics {
+	content, diagnostics := ExportNetwork(ctx, data.NetworkConfigModel)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	data.Content = types.StringValue(content)
+	data.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString([]byte(content)))
+
+	return diagnostics
+}
+
+// ExportNetwork
+// Renders the model into network-config YAML of version 2, or version 1 if requested
+func ExportNetwork(ctx context.Context, model ccmodules.NetworkConfigModel) (string, diag.Diagnostics) {
+	var output any
+	var diagnostics diag.Diagnostics
+
+	if model.Version.ValueInt64() == 1 {
+		output, diagnostics = transformNetworkV1(ctx, model)
+	} else {
+		output, diagnostics = transformNetwork(ctx, model)
+	}
+
+	if diagnostics.HasError() {
+		return "", diagnostics
+	}
+
+	yaml, err := yaml.Marshal(output)
+	if err != nil {
+		return "", diag.Diagnostics{
+			diag.NewErrorDiagnostic("Cannot marshal YAML", err.Error()),
+		}
+	}
+
+	return strings.TrimSpace(string(yaml)), nil
+}
+
+// networkIDs
+// Every kind of device shares the same namespace of IDs
+type networkIDs map[string]bool
+
+func (ids networkIDs) add(at path.Path, id string, diagnostics *diag.Diagnostics) bool {
+	if ids[id] {
+		diagnostics.AddAttributeError(at.AtName("id"), "Duplicate device ID", "Device `"+id+"` is already defined, IDs must be unique across ethernets, bonds, bridges and vlans.")
+		return false
+	}
+
+	ids[id] = true
+	return true
+}
+
+func transformNetwork(ctx context.Context, model ccmodules.NetworkConfigModel) (ccmodules.NetworkConfigOutputModel, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	output := ccmodules.NetworkConfigOutputModel{
+		Version:  2,
+		Renderer: model.Renderer.ValueStringPointer(),
+	}
+
+	ids := networkIDs{}
+	checkID := func(at path.Path, id string) bool {
+		return ids.add(at, id, &diagnostics)
+	}
+
+	if model.Ethernets != nil && len(*model.Ethernets) > 0 {
+		output.Ethernets = make(map[string]ccmodules.NetworkEthernetOutput, len(*model.Ethernets))
+
+		for i, ethernet := range *model.Ethernets {
+			at := path.Root("ethernets").AtListIndex(i)
+			if !checkID(at, ethernet.ID.ValueString()) {
+				continue
+			}
+
+			device, d := transformNetworkDevice(ctx, ethernet.NetworkDevice)
+			diagnostics.Append(d...)
+
+			out := ccmodules.NetworkEthernetOutput{
+				SetName:             ethernet.SetName.ValueStringPointer(),
+				WakeOnLAN:           ethernet.WakeOnLAN.ValueBoolPointer(),
+				NetworkDeviceOutput: device,
+			}
+
+			if ethernet.Match != nil {
+				out.Match = &ccmodules.NetworkMatchOutput{
+					Name:       ethernet.Match.Name.ValueStringPointer(),
+					MACAddress: ethernet.Match.MACAddress.ValueStringPointer(),
+					Driver:     ethernet.Match.Driver.ValueStringPointer(),
+				}
+			}
+
+			output.Ethernets[ethernet.ID.ValueString()] = out
+		}
+	}
+
+	if model.Bonds != nil && len(*model.Bonds) > 0 {
+		output.Bonds = make(map[string]ccmodules.NetworkBondOutput, len(*model.Bonds))
+
+		for i, bond := range *model.Bonds {
+			at := path.Root("bonds").AtListIndex(i)
+			if !checkID(at, bond.ID.ValueString()) {
+				continue
+			}
+
+			device, d := transformNetworkDevice(ctx, bond.NetworkDevice)
+			diagnostics.Append(d...)
+
+			interfaces, d := castArray[string](ctx, bond.Interfaces)
+			diagnostics.Append(d...)
+
+			out := ccmodules.NetworkBondOutput{
+				Interfaces:          interfaces,
+				NetworkDeviceOutput: device,
+			}
+
+			if params := bond.Parameters; params != nil {
+				out.Parameters = &ccmodules.NetworkBondParametersOutput{
+					Mode:               params.Mode.ValueStringPointer(),
+					LACPRate:           params.LACPRate.ValueStringPointer(),
+					MIIMonitorInterval: params.MIIMonitorInterval.ValueInt64Pointer(),
+					MinLinks:           params.MinLinks.ValueInt64Pointer(),
+					TransmitHashPolicy: params.TransmitHashPolicy.ValueStringPointer(),
+					UpDelay:            params.UpDelay.ValueInt64Pointer(),
+					DownDelay:          params.DownDelay.ValueInt64Pointer(),
+					Primary:            params.Primary.ValueStringPointer(),
+				}
+			}
+
+			output.Bonds[bond.ID.ValueString()] = out
+		}
+	}
+
+	if model.Bridges != nil && len(*model.Bridges) > 0 {
+		output.Bridges = make(map[string]ccmodules.NetworkBridgeOutput, len(*model.Bridges))
+
+		for i, bridge := range *model.Bridges {
+			at := path.Root("bridges").AtListIndex(i)
+			if !checkID(at, bridge.ID.ValueString()) {
+				continue
+			}
+
+			device, d := transformNetworkDevice(ctx, bridge.NetworkDevice)
+			diagnostics.Append(d...)
+
+			interfaces, d := castArray[string](ctx, bridge.Interfaces)
+			diagnostics.Append(d...)
+
+			out := ccmodules.NetworkBridgeOutput{
+				Interfaces:          interfaces,
+				NetworkDeviceOutput: device,
+			}
+
+			if params := bridge.Parameters; params != nil {
+				out.Parameters = &ccmodules.NetworkBridgeParametersOutput{
+					AgeingTime:   params.AgeingTime.ValueInt64Pointer(),
+					Priority:     params.Priority.ValueInt64Pointer(),
+					ForwardDelay: params.ForwardDelay.ValueInt64Pointer(),
+					HelloTime:    params.HelloTime.ValueInt64Pointer(),
+					MaxAge:       params.MaxAge.ValueInt64Pointer(),
+					STP:          params.STP.ValueBoolPointer(),
+				}
+			}
+
+			output.Bridges[bridge.ID.ValueString()] = out
+		}
+	}
+
+	if model.VLANs != nil && len(*model.VLANs) > 0 {
+		output.VLANs = make(map[string]ccmodules.NetworkVLANOutput, len(*model.VLANs))
+
+		for i, vlan := range *model.VLANs {
+			at := path.Root("vlans").AtListIndex(i)
+			if !checkID(at, vlan.ID.ValueString()) {
+				continue
+			}
+
+			device, d := transformNetworkDevice(ctx, vlan.NetworkDevice)
+			diagnostics.Append(d...)
+
+			output.VLANs[vlan.ID.ValueString()] = ccmodules.NetworkVLANOutput{
+				VLANID:              vlan.VLANID.ValueInt64Pointer(),
+				Link:                vlan.Link.ValueStringPointer(),
+				NetworkDeviceOutput: device,
+			}
+		}
+	}
+
+	return output, diagnostics
+}
+
+// transformNetworkDevice
+// Properties shared by every kind of device
+func transformNetworkDevice(ctx context.Context, device ccmodules.NetworkDevice) (ccmodules.NetworkDeviceOutput, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	out := ccmodules.NetworkDeviceOutput{
+		DHCP4:      device.DHCP4.ValueBoolPointer(),
+		DHCP6:      device.DHCP6.ValueBoolPointer(),
+		AcceptRA:   device.AcceptRA.ValueBoolPointer(),
+		Gateway4:   device.Gateway4.ValueStringPointer(),
+		Gateway6:   device.Gateway6.ValueStringPointer(),
+		MTU:        device.MTU.ValueInt64Pointer(),
+		MACAddress: device.MACAddress.ValueStringPointer(),
+		Optional:   device.Optional.ValueBoolPointer(),
+	}
+
+	addresses, d := castArray[string](ctx, device.Addresses)
+	diagnostics.Append(d...)
+	out.Addresses = addresses
+
+	if device.Nameservers != nil {
+		out.Nameservers = &ccmodules.NetworkNameserversOutput{}
+
+		out.Nameservers.Addresses, d = castArray[string](ctx, device.Nameservers.Addresses)
+		diagnostics.Append(d...)
+
+		out.Nameservers.Search, d = castArray[string](ctx, device.Nameservers.Search)
+		diagnostics.Append(d...)
+	}
+
+	// NOTE: list blocks are empty lists rather than null, when not configured
+	if device.Routes != nil && len(*device.Routes) > 0 {
+		routes := make([]ccmodules.NetworkRouteOutput, len(*device.Routes))
+
+		for i, route := range *device.Routes {
+			routes[i] = ccmodules.NetworkRouteOutput{
+				To:     route.To.ValueStringPointer(),
+				Via:    route.Via.ValueStringPointer(),
+				Metric: route.Metric.ValueInt64Pointer(),
+				Table:  route.Table.ValueInt64Pointer(),
+				OnLink: route.OnLink.ValueBoolPointer(),
+				Type:   route.Type.ValueStringPointer(),
+			}
+		}
+
+		out.Routes = &routes
+	}
+
+	if device.RoutingPolicy != nil && len(*device.RoutingPolicy) > 0 {
+		policy := make([]ccmodules.NetworkRoutingPolicyOutput, len(*device.RoutingPolicy))
+
+		for i, rule := range *device.RoutingPolicy {
+			policy[i] = ccmodules.NetworkRoutingPolicyOutput{
+				From:          rule.From.ValueStringPointer(),
+				To:            rule.To.ValueStringPointer(),
+				Table:         rule.Table.ValueInt64Pointer(),
+				Priority:      rule.Priority.ValueInt64Pointer(),
+				Mark:          rule.Mark.ValueInt64Pointer(),
+				TypeOfService: rule.TypeOfService.ValueInt64Pointer(),
+			}
+		}
+
+		out.RoutingPolicy = &policy
+	}
+
+	return out, diagnostics
+}
@@ -0,0 +1,569 @@
+package provider
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const networkResourceName = "cloud-config_network.test"
+
+const networkConfig = `
+  renderer = "networkd"
+
+  ethernets {
+    id       = "uplink0"
+    set_name = "uplink0"
+    mtu      = 9000
+
+    match {
+      macaddress = "52:54:00:12:34:56"
+    }
+  }
+
+  ethernets {
+    id    = "uplink1"
+    match {
+      name   = "enp*"
+      driver = "ixgbe"
+    }
+  }
+
+  bonds {
+    id         = "bond0"
+    interfaces = ["uplink0", "uplink1"]
+
+    parameters {
+      mode                 = "802.3ad"
+      lacp_rate            = "fast"
+      mii_monitor_interval = 100
+    }
+  }
+
+  bridges {
+    id         = "br0"
+    interfaces = ["vlan42"]
+    dhcp6      = false
+    addresses  = ["192.168.42.10/24", "2001:db8::10/64"]
+
+    parameters {
+      stp           = false
+      forward_delay = 0
+    }
+
+    nameservers {
+      addresses = ["192.168.42.1", "2001:db8::1"]
+      search    = ["lan"]
+    }
+
+    routes {
+      to  = "default"
+      via = "192.168.42.1"
+    }
+
+    routes {
+      to     = "10.0.0.0/8"
+      via    = "192.168.42.254"
+      metric = 100
+      table  = 42
+    }
+
+    routing_policy {
+      from  = "192.168.42.0/24"
+      table = 42
+    }
+  }
+
+  vlans {
+    id      = "vlan42"
+    vlan_id = 42
+    link    = "bond0"
+  }
+`
+
+func TestAccNetworkResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "cloud-config_network" "test" {` + networkConfig + `}`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(networkResourceName, tfjsonpath.New("content"), knownvalue.NotNull()),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						networkResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(strings.TrimSpace(`
+version: 2
+renderer: networkd
+ethernets:
+    uplink0:
+        match:
+            macaddress: "52:54:00:12:34:56"
+        set-name: uplink0
+        mtu: 9000
+    uplink1:
+        match:
+            name: enp*
+            driver: ixgbe
+bonds:
+    bond0:
+        interfaces:
+            - uplink0
+            - uplink1
+        parameters:
+            mode: 802.3ad
+            lacp-rate: fast
+            mii-monitor-interval: 100
+bridges:
+    br0:
+        interfaces:
+            - vlan42
+        parameters:
+            forward-delay: 0
+            stp: false
+        dhcp6: false
+        addresses:
+            - 192.168.42.10/24
+            - 2001:db8::10/64
+        nameservers:
+            addresses:
+                - 192.168.42.1
+                - 2001:db8::1
+            search:
+                - lan
+        routes:
+            - to: default
+              via: 192.168.42.1
+            - to: 10.0.0.0/8
+              via: 192.168.42.254
+              metric: 100
+              table: 42
+        routing-policy:
+            - from: 192.168.42.0/24
+              table: 42
+vlans:
+    vlan42:
+        id: 42
+        link: bond0
+`)),
+					),
+				},
+			},
+			{
+				Config: `
+resource "cloud-config_network" "test" {
+  ethernets {
+    id    = "eth0"
+    dhcp4 = true
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						networkResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact("version: 2\nethernets:\n    eth0:\n        dhcp4: true"),
+					),
+					statecheck.ExpectKnownValue(
+						networkResourceName,
+						tfjsonpath.New("content_base64"),
+						knownvalue.StringExact("dmVyc2lvbjogMgpldGhlcm5ldHM6CiAgICBldGgwOgogICAgICAgIGRoY3A0OiB0cnVl"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccNetworkResourceV1(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config_network" "test" {
+  version = 1
+
+  ethernets {
+    id       = "uplink0"
+    set_name = "lan0"
+    mtu      = 9000
+
+    match {
+      macaddress = "52:54:00:12:34:56"
+    }
+  }
+
+  ethernets {
+    id        = "eth1"
+    wakeonlan = true
+  }
+
+  bonds {
+    id         = "bond0"
+    interfaces = ["uplink0", "eth1"]
+    dhcp4      = true
+
+    parameters {
+      mode                 = "active-backup"
+      mii_monitor_interval = 100
+      primary              = "uplink0"
+    }
+
+    nameservers {
+      search = ["lan"]
+    }
+  }
+
+  bridges {
+    id         = "br0"
+    interfaces = ["vlan42"]
+    addresses  = ["192.168.42.10/24", "2001:db8::10/64"]
+    gateway4   = "192.168.42.1"
+
+    parameters {
+      stp           = false
+      forward_delay = 0
+    }
+
+    nameservers {
+      addresses = ["192.168.42.1"]
+    }
+
+    routes {
+      to     = "10.0.0.0/8"
+      via    = "192.168.42.254"
+      metric = 100
+    }
+
+    routes {
+      to  = "default"
+      via = "2001:db8::1"
+    }
+  }
+
+  vlans {
+    id      = "vlan42"
+    vlan_id = 42
+    link    = "bond0"
+  }
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						networkResourceName,
+						tfjsonpath.New("content"),
+						knownvalue.StringExact(strings.TrimSpace(`
+version: 1
+config:
+    - type: physical
+      name: lan0
+      mac_address: "52:54:00:12:34:56"
+      mtu: 9000
+    - type: physical
+      name: eth1
+      wakeonlan: true
+    - type: bond
+      name: bond0
+      bond_interfaces:
+        - lan0
+        - eth1
+      params:
+        bond-mode: active-backup
+        bond-miimon: 100
+        bond-primary: lan0
+      subnets:
+        - type: dhcp4
+    - type: vlan
+      name: vlan42
+      vlan_link: bond0
+      vlan_id: 42
+    - type: bridge
+      name: br0
+      bridge_interfaces:
+        - vlan42
+      params:
+        bridge_fd: 0
+        bridge_stp: false
+      subnets:
+        - type: static
+          address: 192.168.42.10/24
+          gateway: 192.168.42.1
+          dns_nameservers:
+            - 192.168.42.1
+        - type: static6
+          address: 2001:db8::10/64
+    - type: nameserver
+      address: []
+      search:
+        - lan
+    - type: route
+      destination: 10.0.0.0/8
+      gateway: 192.168.42.254
+      metric: 100
+    - type: route
+      destination: ::/0
+      gateway: 2001:db8::1
+`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccNetworkDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config_network" "test" {` + networkConfig + `}
+
+data "cloud-config_network" "test" {` + networkConfig + `}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.CompareValuePairs(
+						"data.cloud-config_network.test",
+						tfjsonpath.New("content"),
+						networkResourceName,
+						tfjsonpath.New("content"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccNetworkResourceInvalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+		error  string
+	}{
+		{
+			name: "address without prefix",
+			config: `
+ethernets {
+  id        = "eth0"
+  addresses = ["192.168.1.10"]
+}
+`,
+			error: `value must be an IP address with prefix\s+length`,
+		},
+		{
+			name: "invalid prefix length",
+			config: `
+ethernets {
+  id        = "eth0"
+  addresses = ["192.168.1.10/33"]
+}
+`,
+			error: `value must be an IP address with prefix\s+length`,
+		},
+		{
+			name: "IPv6 gateway4",
+			config: `
+ethernets {
+  id       = "eth0"
+  gateway4 = "2001:db8::1"
+}
+`,
+			error: `value must be an IPv4 address`,
+		},
+		{
+			name: "invalid route gateway",
+			config: `
+ethernets {
+  id = "eth0"
+
+  routes {
+    to  = "default"
+    via = "192.168.1"
+  }
+}
+`,
+			error: `value must be an IP address`,
+		},
+		{
+			name: "invalid route destination",
+			config: `
+ethernets {
+  id = "eth0"
+
+  routes {
+    to = "everywhere"
+  }
+}
+`,
+			error: `Invalid Attribute Value`,
+		},
+		{
+			name: "invalid MAC",
+			config: `
+ethernets {
+  id = "eth0"
+
+  match {
+    macaddress = "52-54-00-12-34-56"
+  }
+}
+`,
+			error: `value must be a colon-separated MAC\s+address`,
+		},
+		{
+			name: "invalid nameserver",
+			config: `
+ethernets {
+  id = "eth0"
+
+  nameservers {
+    addresses = ["dns.lan"]
+  }
+}
+`,
+			error: `value must be an IP address`,
+		},
+		{
+			name: "set_name without match",
+			config: `
+ethernets {
+  id       = "eth0"
+  set_name = "lan0"
+}
+`,
+			error: `Attribute "ethernets\[0\].match" must be specified when\s+"ethernets\[0\].set_name"\s+is specified`,
+		},
+		{
+			name: "duplicate ID",
+			config: `
+ethernets {
+  id = "eth0"
+}
+
+vlans {
+  id      = "eth0"
+  vlan_id = 1
+  link    = "eth0"
+}
+`,
+			error: `Device .eth0. is already defined`,
+		},
+		{
+			name: "version 1 renderer",
+			config: `
+version  = 1
+renderer = "networkd"
+`,
+			error: `Version 1 doesn't select a renderer`,
+		},
+		{
+			name: "version 1 match by name",
+			config: `
+version = 1
+
+ethernets {
+  id       = "eth0"
+  set_name = "lan0"
+
+  match {
+    name = "enp*"
+  }
+}
+`,
+			error: `Version 1 matches physical interfaces only by MAC address`,
+		},
+		{
+			name: "version 1 match without set_name",
+			config: `
+version = 1
+
+ethernets {
+  id = "eth0"
+
+  match {
+    macaddress = "52:54:00:12:34:56"
+  }
+}
+`,
+			error: `Version 1 renames the matched interface`,
+		},
+		{
+			name: "version 1 gateway without address",
+			config: `
+version = 1
+
+ethernets {
+  id       = "eth0"
+  dhcp4    = true
+  gateway4 = "192.168.1.1"
+}
+`,
+			error: `Gateway belongs to a static subnet in version 1`,
+		},
+		{
+			name: "version 1 route table",
+			config: `
+version = 1
+
+ethernets {
+  id        = "eth0"
+  addresses = ["192.168.1.10/24"]
+
+  routes {
+    to    = "10.0.0.0/8"
+    via   = "192.168.1.254"
+    table = 42
+  }
+}
+`,
+			error: `Version 1 routes can't select a table`,
+		},
+		{
+			name: "version 1 routing policy",
+			config: `
+version = 1
+
+ethernets {
+  id = "eth0"
+
+  routing_policy {
+    from  = "192.168.1.0/24"
+    table = 42
+  }
+}
+`,
+			error: `Version 1 has no policy routing`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config:      `resource "cloud-config_network" "test" {` + tc.config + `}`,
+						ExpectError: regexp.MustCompile(tc.error),
+					},
+				},
+			})
+		})
+	}
+}
@@ -0,0 +1,1010 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+
+	"gopkg.in/yaml.v3"
+)
+
+func stringOrNull(s *string) types.String {
+	return types.StringPointerValue(s)
+}
+
+func boolOrNull(b *bool) types.Bool {
+	return types.BoolPointerValue(b)
+}
+
+func castList(ctx context.Context, arr *[]string) (types.List, diag.Diagnostics) {
+	if arr == nil {
+		return types.ListNull(types.StringType), nil
+	}
+
+	return types.ListValueFrom(ctx, types.StringType, *arr)
+}
+
+// elementType
+// Type of elements of a (nested) list block in `cloud-config` schema
+func elementType(ctx context.Context, names ...string) attr.Type {
+	var typ attr.Type = cloudConfigSchema(ctx).Type()
+
+	for _, name := range names {
+		if list, ok := typ.(types.ListType); ok {
+			typ = list.ElemType
+		}
+		typ = typ.(types.ObjectType).AttrTypes[name]
+	}
+
+	return typ.(types.ListType).ElemType
+}
+
+func parseWriteFiles(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	writeFiles := []ccmodules.WriteFile{}
+
+	if input.WriteFiles != nil {
+		for _, v := range *input.WriteFiles {
+			item := ccmodules.WriteFile{
+				Path:        stringOrNull(v.Path),
+				Content:     stringOrNull(v.Content),
+				Owner:       stringOrNull(v.Owner),
+				Permissions: stringOrNull(v.Permissions),
+				Encoding:    stringOrNull(v.Encoding),
+				Append:      boolOrNull(v.Append),
+				Defer:       boolOrNull(v.Defer),
+			}
+
+			if v.Source != nil {
+				src := ccmodules.WriteFileSource{
+					URI:     stringOrNull(v.Source.URI),
+					Headers: types.MapNull(types.StringType),
+				}
+
+				if v.Source.Headers != nil {
+					headers, diagnostics := types.MapValueFrom(ctx, types.StringType, *v.Source.Headers)
+					if diagnostics.HasError() {
+						return diagnostics
+					}
+					src.Headers = headers
+				}
+
+				item.Source = &src
+			}
+
+			writeFiles = append(writeFiles, item)
+		}
+	}
+
+	res, diagnostics := types.ListValueFrom(ctx, elementType(ctx, "write_files"), writeFiles)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	model.WriteFiles = res
+
+	return nil
+}
+
+func parseZypper(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Zypper == nil {
+		return nil
+	}
+
+	zypper := ccmodules.Zypper{
+		Config: types.MapNull(types.StringType),
+	}
+
+	repos := []ccmodules.ZypperRepository{}
+	if input.Zypper.Repos != nil {
+		for _, v := range *input.Zypper.Repos {
+			repos = append(repos, ccmodules.ZypperRepository{
+				ID:      stringOrNull(v.ID),
+				BaseURL: stringOrNull(v.BaseURL),
+			})
+		}
+	}
+
+	res, diagnostics := types.ListValueFrom(ctx, elementType(ctx, "zypper", "repos"), repos)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+	zypper.Repos = res
+
+	if input.Zypper.Config != nil {
+		config, diagnostics := types.MapValueFrom(ctx, types.StringType, *input.Zypper.Config)
+		if diagnostics.HasError() {
+			return diagnostics
+		}
+		zypper.Config = config
+	}
+
+	model.Zypper = &zypper
+
+	return nil
+}
+
+func parseWireguard(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Wireguard == nil {
+		return nil
+	}
+
+	wireguard := ccmodules.Wireguard{}
+
+	interfaces := []ccmodules.Interface{}
+	if input.Wireguard.Interfaces != nil {
+		for _, v := range *input.Wireguard.Interfaces {
+			interfaces = append(interfaces, ccmodules.Interface{
+				Name:       stringOrNull(v.Name),
+				ConfigPath: stringOrNull(v.ConfigPath),
+				Content:    stringOrNull(v.Content),
+			})
+		}
+	}
+
+	res, diagnostics := types.ListValueFrom(ctx, elementType(ctx, "wireguard", "interfaces"), interfaces)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+	wireguard.Interfaces = res
+
+	wireguard.ReadinessProbe, diagnostics = castList(ctx, input.Wireguard.ReadinessProbe)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	model.Wireguard = &wireguard
+
+	return nil
+}
+
+func parseRPI(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.RPI == nil {
+		return nil
+	}
+
+	rpi := ccmodules.RPI{}
+
+	rpi.EnableRPIConnect = boolOrNull(input.RPI.EnableRPIConnect)
+
+	if input.RPI.Interfaces != nil {
+		interfaces := ccmodules.RPIInterface{}
+
+		interfaces.SPI = boolOrNull(input.RPI.Interfaces.SPI)
+		interfaces.I2C = boolOrNull(input.RPI.Interfaces.I2C)
+		interfaces.SSH = boolOrNull(input.RPI.Interfaces.SSH)
+		interfaces.Onewire = boolOrNull(input.RPI.Interfaces.Onewire)
+		interfaces.RemoteGPIO = boolOrNull(input.RPI.Interfaces.RemoteGPIO)
+
+		if input.RPI.Interfaces.Serial != nil {
+			interfaces.Serial = &ccmodules.RPISerial{
+				Console:  boolOrNull(input.RPI.Interfaces.Serial.Console),
+				Hardware: boolOrNull(input.RPI.Interfaces.Serial.Hardware),
+			}
+		}
+
+		rpi.Interfaces = &interfaces
+	}
+
+	model.RPI = &rpi
+
+	return nil
+}
+
+func parseSeedRandom(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.RandomSeed == nil {
+		return nil
+	}
+
+	seed := ccmodules.RandomSeed{}
+
+	var diagnostics diag.Diagnostics
+	seed.Command, diagnostics = castList(ctx, input.RandomSeed.Command)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	seed.File = stringOrNull(input.RandomSeed.File)
+	seed.Data = stringOrNull(input.RandomSeed.Data)
+	seed.Encoding = stringOrNull(input.RandomSeed.Encoding)
+
+	seed.CommandRequired = boolOrNull(input.RandomSeed.CommandRequired)
+
+	model.RandomSeed = &seed
+
+	return nil
+}
+
+func parseNTP(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.NTP == nil {
+		return nil
+	}
+
+	ntp := ccmodules.NTP{}
+
+	var diagnostics diag.Diagnostics
+	for _, item := range []struct {
+		target *types.List
+		source *[]string
+	}{
+		{&ntp.Pools, input.NTP.Pools},
+		{&ntp.Servers, input.NTP.Servers},
+		{&ntp.Peers, input.NTP.Peers},
+		{&ntp.Allow, input.NTP.Allow},
+	} {
+		*item.target, diagnostics = castList(ctx, item.source)
+		if diagnostics.HasError() {
+			return diagnostics
+		}
+	}
+
+	ntp.NTPClient = stringOrNull(input.NTP.NTPClient)
+	ntp.Enabled = types.BoolPointerValue(input.NTP.Enabled)
+
+	if input.NTP.Config != nil {
+		config := ccmodules.NTPConfig{}
+
+		config.Packages, diagnostics = castList(ctx, input.NTP.Config.Packages)
+		if diagnostics.HasError() {
+			return diagnostics
+		}
+
+		config.Confpath = stringOrNull(input.NTP.Config.Confpath)
+		config.CheckExe = stringOrNull(input.NTP.Config.CheckExe)
+		config.ServiceName = stringOrNull(input.NTP.Config.ServiceName)
+		config.Template = stringOrNull(input.NTP.Config.Template)
+
+		ntp.Config = &config
+	}
+
+	model.NTP = &ntp
+
+	return nil
+}
+
+func parseLandscape(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Landscape == nil || input.Landscape.Client == nil {
+		return nil
+	}
+
+	client := input.Landscape.Client
+
+	model.Landscape = &ccmodules.Landscape{
+		Client: &ccmodules.Client{
+			URL:             stringOrNull(client.URL),
+			PingURL:         stringOrNull(client.PingURL),
+			DataPath:        stringOrNull(client.DataPath),
+			LogLevel:        stringOrNull(client.LogLevel),
+			ComputerTitle:   stringOrNull(client.ComputerTitle),
+			AccountName:     stringOrNull(client.AccountName),
+			RegistrationKey: stringOrNull(client.RegistrationKey),
+			Tags:            stringOrNull(client.Tags),
+			HTTPProxy:       stringOrNull(client.HTTPProxy),
+			HTTPSProxy:      stringOrNull(client.HTTPSProxy),
+		},
+	}
+
+	return nil
+}
+
+func parseSetHostname(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	model.Hostname = stringOrNull(input.Hostname)
+	model.FQDN = stringOrNull(input.FQDN)
+	model.PreserveHostname = boolOrNull(input.PreserveHostname)
+	model.PreferFQDNOverHostname = boolOrNull(input.PreferFQDNOverHostname)
+	model.CreateHostnameFile = types.BoolPointerValue(input.CreateHostnameFile)
+
+	return nil
+}
+
+func parseLocale(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	model.Locale = stringOrNull(input.Locale)
+	model.LocaleConfigfile = stringOrNull(input.LocaleConfigfile)
+
+	return nil
+}
+
+func parseManageEtcHosts(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	model.ManageEtcHosts = types.BoolNull()
+	model.ManageEtcHostsLocalhost = types.BoolNull()
+
+	switch value := input.ManageEtcHosts.(type) {
+	case nil:
+	case bool:
+		model.ManageEtcHosts = types.BoolValue(value)
+	case string:
+		if value != "localhost" {
+			return unsupportedValue("manage_etc_hosts", value)
+		}
+		model.ManageEtcHostsLocalhost = types.BoolValue(true)
+	default:
+		return unsupportedValue("manage_etc_hosts", value)
+	}
+
+	return nil
+}
+
+func parseSetPasswords(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	model.SSHPwauth = types.BoolPointerValue(input.SSHPwauth)
+
+	if input.ChPasswd == nil {
+		return nil
+	}
+
+	chpasswd := ccmodules.ChangePassword{
+		Expire: types.BoolPointerValue(input.ChPasswd.Expire),
+	}
+
+	usrs := []ccmodules.ChangePasswordUser{}
+	if input.ChPasswd.Users != nil {
+		for _, usr := range *input.ChPasswd.Users {
+			usrs = append(usrs, ccmodules.ChangePasswordUser{
+				Name:     stringOrNull(usr.Name),
+				Password: stringOrNull(usr.Password),
+				Type:     stringOrNull(usr.Type),
+			})
+		}
+	}
+	chpasswd.Users = &usrs
+
+	model.ChPasswd = &chpasswd
+
+	return nil
+}
+
+func parsePkgUpdateUpgrade(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	model.PackageUpdate = boolOrNull(input.PackageUpdate)
+	model.PackageUpgrade = boolOrNull(input.PackageUpgrade)
+	model.PackageRebootIfRequired = boolOrNull(input.PackageRebootIfRequired)
+
+	var diagnostics diag.Diagnostics
+	model.Packages, diagnostics = castList(ctx, input.Packages)
+
+	return diagnostics
+}
+
+func parseUsersAndGroups(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	parseUser := func(user *ccmodules.UserOutput) (ccmodules.User, diag.Diagnostics) {
+		out := ccmodules.User{}
+
+		out.Name = stringOrNull(user.Name)
+		out.ExpireDate = stringOrNull(user.ExpireDate)
+		out.Gecos = stringOrNull(user.Gecos)
+		out.HomeDir = stringOrNull(user.HomeDir)
+		out.Inactive = stringOrNull(user.Inactive)
+		out.Passwd = stringOrNull(user.Passwd)
+		out.HashedPasswd = stringOrNull(user.HashedPasswd)
+		out.PlainTextPasswd = stringOrNull(user.PlainTextPasswd)
+		out.PrimaryGroup = stringOrNull(user.PrimaryGroup)
+		out.SELinuxUser = stringOrNull(user.SELinuxUser)
+		out.Shell = stringOrNull(user.Shell)
+		out.SnapUser = stringOrNull(user.SnapUser)
+
+		out.LockPassword = types.BoolPointerValue(user.LockPassword)
+
+		out.NoCreateHome = boolOrNull(user.NoCreateHome)
+		out.NoLogInit = boolOrNull(user.NoLogInit)
+		out.NoUserGroup = boolOrNull(user.NoUserGroup)
+		out.CreateGroups = boolOrNull(user.CreateGroups)
+		out.SSHRedirectUser = boolOrNull(user.SSHRedirectUser)
+		out.System = boolOrNull(user.System)
+
+		out.UID = types.Int32PointerValue(user.UID)
+
+		var diagnostics diag.Diagnostics
+		for _, item := range []struct {
+			target *types.List
+			source *[]string
+		}{
+			{&out.Doas, user.Doas},
+			{&out.SSHAuthorizedKeys, user.SSHAuthorizedKeys},
+			{&out.SSHImportId, user.SSHImportId},
+			{&out.Sudo, user.Sudo},
+			{&out.Groups, user.Groups},
+		} {
+			*item.target, diagnostics = castList(ctx, item.source)
+			if diagnostics.HasError() {
+				return out, diagnostics
+			}
+		}
+
+		return out, nil
+	}
+
+	var diagnostics diag.Diagnostics
+	model.Groups, diagnostics = castList(ctx, input.Groups)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	if input.User != nil {
+		user, diagnostics := parseUser(input.User)
+		if diagnostics.HasError() {
+			return diagnostics
+		}
+		model.User = &user
+	}
+
+	usrs := []ccmodules.User{}
+	if input.Users != nil {
+		for _, usr := range *input.Users {
+			user, diagnostics := parseUser(&usr)
+			if diagnostics.HasError() {
+				return diagnostics
+			}
+			usrs = append(usrs, user)
+		}
+	}
+	model.Users = &usrs
+
+	return nil
+}
+
+func parseApkConfigure(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.ApkRepos == nil {
+		return nil
+	}
+
+	repo := ccmodules.ApkRepo{
+		PreserveRepositories: boolOrNull(input.ApkRepos.PreserveRepositories),
+		LocalRepoBaseUrl:     stringOrNull(input.ApkRepos.LocalRepoBaseUrl),
+	}
+
+	if input.ApkRepos.AlpineRepo != nil {
+		repo.AlpineRepo = &ccmodules.AlpineRepo{
+			CommunityEnabled: boolOrNull(input.ApkRepos.AlpineRepo.CommunityEnabled),
+			TestingEnabled:   boolOrNull(input.ApkRepos.AlpineRepo.TestingEnabled),
+			BaseUrl:          stringOrNull(input.ApkRepos.AlpineRepo.BaseUrl),
+			Version:          stringOrNull(input.ApkRepos.AlpineRepo.Version),
+		}
+	}
+
+	model.ApkRepos = &repo
+
+	return nil
+}
+
+func parseAptPipelining(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	config := ccmodules.AptPipeliningConfig{
+		OS:      types.BoolNull(),
+		Disable: types.BoolNull(),
+		Depth:   types.Int32Null(),
+	}
+
+	switch value := input.AptPipelining.(type) {
+	case nil:
+		return nil
+	case string:
+		// NOTE: `none` and `unchanged` are aliases of `os`
+		if value != "os" && value != "none" && value != "unchanged" {
+			return unsupportedValue("apt_pipelining", value)
+		}
+		config.OS = types.BoolValue(true)
+	case bool:
+		if value {
+			return unsupportedValue("apt_pipelining", value)
+		}
+		config.Disable = types.BoolValue(true)
+	case int:
+		config.Depth = types.Int32Value(int32(value))
+	default:
+		return unsupportedValue("apt_pipelining", value)
+	}
+
+	model.AptPipelining = &config
+
+	return nil
+}
+
+func parseCACertificates(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.CACerts == nil {
+		return nil
+	}
+
+	trusted, diagnostics := castList(ctx, input.CACerts.Trusted)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	model.CACerts = &ccmodules.CACerts{
+		RemoveDefaults: boolOrNull(input.CACerts.RemoveDefaults),
+		Trusted:        trusted,
+	}
+
+	return nil
+}
+
+func parseFan(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Fan == nil {
+		return nil
+	}
+
+	model.Fan = &ccmodules.Fan{
+		Config:     stringOrNull(input.Fan.Config),
+		ConfigPath: stringOrNull(input.Fan.ConfigPath),
+	}
+
+	return nil
+}
+
+func parseGrowpart(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Growpart == nil {
+		return nil
+	}
+
+	devices, diagnostics := castList(ctx, input.Growpart.Devices)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	model.Growpart = &ccmodules.Growpart{
+		Mode:                   stringOrNull(input.Growpart.Mode),
+		Devices:                devices,
+		IgnoreGrowrootDisabled: boolOrNull(input.Growpart.IgnoreGrowrootDisabled),
+	}
+
+	return nil
+}
+
+func parseGRUBDpkg(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.GRUBDpkg == nil {
+		return nil
+	}
+
+	model.GRUBDpkg = &ccmodules.GRUBDpkg{
+		Enabled:                    boolOrNull(input.GRUBDpkg.Enabled),
+		GRUBPC_InstallDevicesEmpty: boolOrNull(input.GRUBDpkg.GRUBPC_InstallDevicesEmpty),
+		GRUBPC_InstallDevices:      stringOrNull(input.GRUBDpkg.GRUBPC_InstallDevices),
+		GRUBEFI_InstallDevices:     stringOrNull(input.GRUBDpkg.GRUBEFI_InstallDevices),
+	}
+
+	return nil
+}
+
+func parseInstallHotplug(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Updates == nil || input.Updates.Network == nil {
+		return nil
+	}
+
+	when, diagnostics := castList(ctx, input.Updates.Network.When)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	model.Updates = &ccmodules.Updates{
+		Network: &ccmodules.Network{
+			When: when,
+		},
+	}
+
+	return nil
+}
+
+func parseKeyboard(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Keyboard == nil {
+		return nil
+	}
+
+	model.Keyboard = &ccmodules.Keyboard{
+		Layout:  stringOrNull(input.Keyboard.Layout),
+		Model:   stringOrNull(input.Keyboard.Model),
+		Variant: stringOrNull(input.Keyboard.Variant),
+		Options: stringOrNull(input.Keyboard.Options),
+	}
+
+	return nil
+}
+
+func parseKeysToConsole(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.SSH != nil {
+		model.SSH = &ccmodules.SSHObj{
+			EmitKeysToConsole: types.BoolPointerValue(input.SSH.EmitKeysToConsole),
+		}
+	}
+
+	var diagnostics diag.Diagnostics
+	model.SSHKeyConsoleBlacklist, diagnostics = castList(ctx, input.SSHKeyConsoleBlacklist)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	model.SSHFPConsoleBlacklist, diagnostics = castList(ctx, input.SSHFPConsoleBlacklist)
+
+	return diagnostics
+}
+
+func parseResizefs(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	model.Resizefs = types.BoolNull()
+	model.ResizefsNoBlock = types.BoolNull()
+
+	switch value := input.Resizefs.(type) {
+	case nil:
+	case bool:
+		model.Resizefs = types.BoolValue(value)
+	case string:
+		if value != "noblock" {
+			return unsupportedValue("resize_rootfs", value)
+		}
+		model.ResizefsNoBlock = types.BoolValue(true)
+	default:
+		return unsupportedValue("resize_rootfs", value)
+	}
+
+	return nil
+}
+
+func parseSaltMinion(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.SaltMinion == nil {
+		return nil
+	}
+
+	model.SaltMinion = &ccmodules.SaltMinion{
+		PkgName:     stringOrNull(input.SaltMinion.PkgName),
+		ServiceName: stringOrNull(input.SaltMinion.ServiceName),
+		ConfigDir:   stringOrNull(input.SaltMinion.ConfigDir),
+		PublicKey:   stringOrNull(input.SaltMinion.PublicKey),
+		PrivateKey:  stringOrNull(input.SaltMinion.PrivateKey),
+		PkiDir:      stringOrNull(input.SaltMinion.PkiDir),
+	}
+
+	return nil
+}
+
+func parseUbuntuAutoinstall(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Autoinstall == nil {
+		return nil
+	}
+
+	model.Autoinstall = &ccmodules.Autoinstall{
+		Version: types.Int32PointerValue(input.Autoinstall.Version),
+	}
+
+	return nil
+}
+
+func parsePowerStateChange(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.PowerState == nil {
+		return nil
+	}
+
+	config := ccmodules.PowerState{
+		Mode:         stringOrNull(input.PowerState.Mode),
+		Message:      stringOrNull(input.PowerState.Message),
+		Timeout:      types.Int64PointerValue(input.PowerState.Timeout),
+		Delay:        types.Int64Null(),
+		NoDelay:      types.BoolNull(),
+		Condition:    types.BoolNull(),
+		ConditionCmd: types.StringNull(),
+	}
+
+	switch value := input.PowerState.Delay.(type) {
+	case nil:
+	case int:
+		config.Delay = types.Int64Value(int64(value))
+	case string:
+		if value != "now" {
+			return unsupportedValue("power_state.delay", value)
+		}
+		config.NoDelay = types.BoolValue(true)
+	default:
+		return unsupportedValue("power_state.delay", value)
+	}
+
+	switch value := input.PowerState.Condition.(type) {
+	case nil:
+	case bool:
+		config.Condition = types.BoolValue(value)
+	case string:
+		config.ConditionCmd = types.StringValue(value)
+	default:
+		return unsupportedValue("power_state.condition", value)
+	}
+
+	model.PowerState = &config
+
+	return nil
+}
+
+func parsePhoneHome(ctx context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.PhoneHome == nil {
+		return nil
+	}
+
+	post, diagnostics := castList(ctx, input.PhoneHome.Post)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	config := ccmodules.PhoneHome{
+		URL:   stringOrNull(input.PhoneHome.URL),
+		Tries: types.Int64PointerValue(input.PhoneHome.Tries),
+		Post:  post,
+	}
+
+	model.PhoneHome = &config
+
+	return nil
+}
+
+func parseSpacewalk(_ context.Context, model *CloudConfigResourceModel, input ExportModel) diag.Diagnostics {
+	if input.Spacewalk == nil {
+		return nil
+	}
+
+	model.Spacewalk = &ccmodules.Spacewalk{
+		Server:        stringOrNull(input.Spacewalk.Server),
+		Proxy:         stringOrNull(input.Spacewalk.Proxy),
+		ActivationKey: stringOrNull(input.Spacewalk.ActivationKey),
+	}
+
+	return nil
+}
+
+func parse(ctx context.Context, input ExportModel) (CloudConfigResourceModel, diag.Diagnostics) {
+	model := CloudConfigResourceModel{
+		Content: types.StringNull(),
+	}
+
+	var diagnostics diag.Diagnostics
+
+	model.Timezone = stringOrNull(input.Timezone)
+	model.DisableEC2Metadata = boolOrNull(input.DisableEC2Metadata)
+	model.ByobuByDefault = stringOrNull(input.ByobuByDefault)
+	model.FinalMessage = stringOrNull(input.FinalMessage)
+
+	model.RunCMD, diagnostics = castList(ctx, input.RunCMD)
+	if diagnostics.HasError() {
+		return model, diagnostics
+	}
+
+	model.BootCMD, diagnostics = castList(ctx, input.BootCMD)
+	if diagnostics.HasError() {
+		return model, diagnostics
+	}
+
+	model.SSHAuthorizedKeys, diagnostics = castList(ctx, input.SSHAuthorizedKeys)
+	if diagnostics.HasError() {
+		return model, diagnostics
+	}
+
+	for _, fn := range []func(context.Context, *CloudConfigResourceModel, ExportModel) diag.Diagnostics{
+		parseSetHostname,
+		parseLocale,
+		parseManageEtcHosts,
+		parseSetPasswords,
+		parsePkgUpdateUpgrade,
+		parseUsersAndGroups,
+		parseApkConfigure,
+		parseAptPipelining,
+		parseCACertificates,
+		parseFan,
+		parseGrowpart,
+		parseGRUBDpkg,
+		parseInstallHotplug,
+		parseKeyboard,
+		parseKeysToConsole,
+		parseResizefs,
+		parseSaltMinion,
+		parseUbuntuAutoinstall,
+		parsePowerStateChange,
+		parsePhoneHome,
+		parseLandscape,
+		parseNTP,
+		parseRPI,
+		parseSeedRandom,
+		parseWireguard,
+		parseZypper,
+		parseWriteFiles,
+		parseSpacewalk,
+	} {
+		diagnostics.Append(fn(ctx, &model, input)...)
+		if diagnostics.HasError() {
+			return model, diagnostics
+		}
+	}
+
+	return model, diagnostics
+}
+
+// ParseContent
+// The opposite of `ExportContent`: reads a cloud-config document back into the resource model.
+// Keys which are not modelled by the provider are reported as warnings and moved to `extra_yaml`.
+func ParseContent(ctx context.Context, content string) (CloudConfigResourceModel, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	// NOTE: header is a YAML comment anyway, so it's optional here
+	body := strings.TrimPrefix(strings.TrimSpace(content), hat)
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &node); err != nil {
+		diagnostics.AddError("Cannot unmarshal YAML", err.Error())
+		return CloudConfigResourceModel{}, diagnostics
+	}
+
+	extra, extraDiagnostics := extractUnsupported(&node)
+	diagnostics.Append(extraDiagnostics...)
+
+	if diagnostics.HasError() {
+		return CloudConfigResourceModel{}, diagnostics
+	}
+
+	input := ExportModel{}
+	if err := node.Decode(&input); err != nil {
+		diagnostics.AddError("Cannot unmarshal YAML", err.Error())
+		return CloudConfigResourceModel{}, diagnostics
+	}
+
+	model, parseDiagnostics := parse(ctx, input)
+	diagnostics.Append(parseDiagnostics...)
+
+	model.ExtraYAML = extra
+
+	// NOTE: jinja header goes before the `#cloud-config` one
+	if strings.HasPrefix(body, jinjaHat) {
+		model.Template = types.StringValue(ccmodules.TemplateJinja)
+	}
+
+	return model, diagnostics
+}
+
+func unsupportedValue(key string, value any) diag.Diagnostics {
+	return diag.Diagnostics{
+		diag.NewErrorDiagnostic(
+			"Unsupported cloud-config value",
+			fmt.Sprintf("Value `%v` of key `%s` cannot be represented by the provider.", value, key),
+		),
+	}
+}
+
+// extractUnsupported
+// Moves top-level keys, which can't be represented by the model, out of the document into `extra_yaml`.
+// A key is moved as a whole, when it is unknown, has unknown nested keys or a value of different shape
+func extractUnsupported(document *yaml.Node) (types.String, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return types.StringNull(), nil
+	}
+
+	root := document.Content[0]
+	fields := yamlFields(reflect.TypeOf(ExportModel{}))
+
+	kept := []*yaml.Node{}
+	extra := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		field, ok := fields[key.Value]
+		if !ok {
+			diagnostics.AddWarning(
+				"Unsupported cloud-config key",
+				fmt.Sprintf("Key `%s` (line %d) is not supported by the provider and was moved to `extra_yaml`.", key.Value, key.Line),
+			)
+			extra.Content = append(extra.Content, key, value)
+			continue
+		}
+
+		if nested := unsupportedKeys(value, field, key.Value); len(nested) > 0 {
+			diagnostics.AddWarning(
+				"Unsupported cloud-config key",
+				fmt.Sprintf("Key `%s` (line %d) is not supported by the provider, so `%s` was moved to `extra_yaml`.", nested[0].path, nested[0].line, key.Value),
+			)
+			extra.Content = append(extra.Content, key, value)
+			continue
+		}
+
+		// NOTE: legacy shapes, e.g. `default` user, argv commands or `[name, version]` packages
+		single := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{key, value}}
+		if err := single.Decode(&ExportModel{}); err != nil {
+			diagnostics.AddWarning(
+				"Unsupported cloud-config value",
+				fmt.Sprintf("Value of `%s` (line %d) cannot be represented by the provider and was moved to `extra_yaml`.", key.Value, key.Line),
+			)
+			extra.Content = append(extra.Content, key, value)
+			continue
+		}
+
+		kept = append(kept, key, value)
+	}
+
+	if len(extra.Content) == 0 {
+		return types.StringNull(), nil
+	}
+
+	root.Content = kept
+
+	out, err := yaml.Marshal(extra)
+	if err != nil {
+		diagnostics.AddError("Cannot marshal YAML", err.Error())
+		return types.StringNull(), diagnostics
+	}
+
+	return types.StringValue(strings.TrimSpace(string(out))), diagnostics
+}
+
+type unsupportedKey struct {
+	path string
+	line int
+}
+
+// unsupportedKeys
+// Walks YAML document alongside the output model and collects keys the model does not have
+func unsupportedKeys(node *yaml.Node, typ reflect.Type, path string) []unsupportedKey {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		var result []unsupportedKey
+		for _, child := range node.Content {
+			result = append(result, unsupportedKeys(child, typ, path)...)
+		}
+		return result
+	case yaml.SequenceNode:
+		if typ.Kind() != reflect.Slice {
+			return nil
+		}
+		var result []unsupportedKey
+		for i, child := range node.Content {
+			result = append(result, unsupportedKeys(child, typ.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return result
+	case yaml.MappingNode:
+		if typ.Kind() != reflect.Struct {
+			return nil
+		}
+		fields := yamlFields(typ)
+		var result []unsupportedKey
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+
+			field, ok := fields[key.Value]
+			if !ok {
+				result = append(result, unsupportedKey{path: childPath, line: key.Line})
+				continue
+			}
+			result = append(result, unsupportedKeys(value, field, childPath)...)
+		}
+		return result
+	}
+
+	return nil
+}
+
+// yamlFields
+// Maps YAML keys of a struct (including inlined structs) to their types
+func yamlFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		tag := field.Tag.Get("yaml")
+		name, options, _ := strings.Cut(tag, ",")
+
+		if strings.Contains(options, "inline") {
+			for k, v := range yamlFields(field.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+
+	return fields
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+var _ function.Function = &PreviewFunction{}
+
+func NewPreviewFunction() function.Function {
+	return &PreviewFunction{}
+}
+
+// PreviewFunction
+// Renders jinja templated content the same way cloud-init does on the instance
+type PreviewFunction struct {
+}
+
+var (
+	// jinjaHeader
+	// Same as cloud-init, the header is matched case-insensitively and spaces are optional
+	jinjaHeader = regexp.MustCompile(`(?i)^##\s*template:\s*jinja\s*$`)
+
+	// instanceDataVersion
+	// Keys of versioned namespaces, their keys are also available at the top level
+	instanceDataVersion = regexp.MustCompile(`^v\d+$`)
+)
+
+func (f *PreviewFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "preview"
+}
+
+func (f *PreviewFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Preview cloud-config rendered on an instance",
+		MarkdownDescription: "Renders content with `## template: jinja` header against an instance-data document, the same way cloud-init does on the instance, so the per-instance output can be asserted by `terraform test` without booting a VM. Supports the subset of Jinja used by cloud-init templates: expressions, `if`, `for`, `set` and `do` statements, common filters and tests. Integers are limited to 64 bits, larger results are reported as errors. Undefined variables are rendered as `CI_MISSING_JINJA_VAR/<name>`, same as in cloud-init. Content without the header is returned as it is. Provider-defined functions require Terraform 1.8 or later.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "content",
+				MarkdownDescription: "Content of user-data, e.g. `content` of `cloud-config` with `template = \"jinja\"`",
+			},
+			function.StringParameter{
+				Name:                "instance_data_json",
+				MarkdownDescription: "Instance-data JSON document, e.g. `/run/cloud-init/instance-data.json` of a booted instance, or `jsonencode({ v1 = { local_hostname = \"node1\" } })`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PreviewFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content, instanceData string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content, &instanceData))
+
+	if resp.Error != nil {
+		return
+	}
+
+	header, body, _ := strings.Cut(content, "\n")
+	if !jinjaHeader.MatchString(header) {
+		resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, content))
+		return
+	}
+
+	variables, err := instanceDataVariables(instanceData)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Invalid instance-data: %s", err))
+		return
+	}
+
+	rendered, err := utils.Jinja(body, variables)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Cannot render template: %s", err))
+		return
+	}
+
+	// NOTE: other kinds of user-data, e.g. shell scripts, can be templated as well
+	if strings.HasPrefix(rendered, hat) {
+		var document any
+		if err := yaml.Unmarshal([]byte(rendered), &document); err != nil {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Rendered content is not valid YAML: %s", err))
+			return
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, rendered))
+}
+
+// instanceDataVariables
+// Variables of templates, which cloud-init makes out of instance-data
+func instanceDataVariables(instanceData string) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(instanceData)))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	data, ok := jsonValue(document).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	decodePaths := []string{}
+
+	if keys, ok := data["base64_encoded_keys"].([]any); ok {
+		for _, key := range keys {
+			if key, ok := key.(string); ok {
+				decodePaths = append(decodePaths, strings.ReplaceAll(key, "-", "_"))
+			}
+		}
+	}
+
+	delete(data, "base64_encoded_keys")
+
+	return convertInstanceData(data, "", decodePaths)
+}
+
+// convertInstanceData
+// Decodes base64 encoded values, adds underscore aliases of keys with dashes and dots,
+// and copies keys of versioned namespaces to the top level, e.g. `v1.region` to `region`
+func convertInstanceData(data map[string]any, prefix string, decodePaths []string) (map[string]any, error) {
+	result := map[string]any{}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := data[key]
+
+		keyPath := key
+		if prefix != "" {
+			keyPath = prefix + "/" + key
+		}
+
+		if encoded, ok := value.(string); ok && slices.Contains(decodePaths, keyPath) {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", keyPath, err)
+			}
+			value = string(decoded)
+		}
+
+		if nested, ok := value.(map[string]any); ok {
+			converted, err := convertInstanceData(nested, keyPath, decodePaths)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = converted
+
+			if instanceDataVersion.MatchString(key) {
+				for subkey, subvalue := range converted {
+					result[subkey] = subvalue
+				}
+			}
+		} else {
+			result[key] = value
+		}
+
+		if alias := strings.NewReplacer("-", "_", ".", "_").Replace(key); alias != key {
+			result[alias] = result[key]
+		}
+	}
+
+	return result, nil
+}
+
+// jsonValue
+// Converts decoded JSON numbers into ints or floats, the same as Python does
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			return integer
+		}
+		float, _ := value.Float64()
+		return float
+	case []any:
+		for i, item := range value {
+			value[i] = jsonValue(item)
+		}
+	case map[string]any:
+		for key, item := range value {
+			value[key] = jsonValue(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestPreviewFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config" "test" {
+  template = "jinja"
+  hostname = "{{ v1.local_hostname }}"
+  runcmd = [
+    "echo '{{ v1['instance-id'] }}: {{ region | upper }}'",
+    "echo '{{ v1.distro }}'",
+  ]
+}
+
+output "content" {
+  value = provider::cloud-config::preview(resource.cloud-config.test.content, jsonencode({
+    v1 = {
+      "instance-id"  = "i-0001"
+      local_hostname = "node1"
+      region         = "eu-west-1"
+    }
+  }))
+  sensitive = true
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("content", knownvalue.StringExact(`#cloud-config
+hostname: |-
+    node1
+runcmd:
+    - |-
+      echo 'i-0001: EU-WEST-1'
+    - |-
+      echo 'CI_MISSING_JINJA_VAR/distro'`)),
+				},
+			},
+			// Statements, filters and base64 encoded keys
+			{
+				Config: `
+output "content" {
+  value = provider::cloud-config::preview(<<-EOT
+    ## template: jinja
+    #cloud-config
+    {% set keys = ds.meta_data.public_keys | sort %}
+    ssh_authorized_keys:
+    {% for key in keys if key.startswith('ssh-') %}
+      - {{ key }}
+    {% endfor %}
+    {%- if v1.cloud_name == 'aws' and ds.user_data is defined %}
+    final_message: {{ ds.user_data | trim | tojson }}
+    {% else %}
+    final_message: other
+    {% endif %}
+    EOT
+    , jsonencode({
+      base64_encoded_keys = ["ds/user_data"]
+      ds = {
+        meta_data = { public_keys = ["ssh-rsa B", "ecdsa C", "ssh-ed25519 A"] }
+        user_data = base64encode("hello\n")
+      }
+      v1 = { cloud_name = "aws" }
+    })
+  )
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("content", knownvalue.StringExact(`#cloud-config
+ssh_authorized_keys:
+  - ssh-ed25519 A
+  - ssh-rsa B
+final_message: "hello"
+`)),
+				},
+			},
+			// Content without the header is not rendered
+			{
+				Config: `
+output "content" {
+  value = provider::cloud-config::preview("#cloud-config\nhostname: '{{ v1.local_hostname }}'", "{}")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("content", knownvalue.StringExact("#cloud-config\nhostname: '{{ v1.local_hostname }}'")),
+				},
+			},
+		},
+	})
+}
+
+func TestPreviewFunctionInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "content" {
+  value = provider::cloud-config::preview("## template: jinja\n#cloud-config\n{% if v1.region %}", "{}")
+}
+`,
+				ExpectError: regexp.MustCompile(`Cannot render template: line 2:\s+unexpected end of template,\s+expected 'elif' or 'else' or 'endif'`),
+			},
+			{
+				Config: `
+output "content" {
+  value = provider::cloud-config::preview("## template: jinja\n#cloud-config\nhostname: {{ v1.local_hostname }}", "[]")
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid instance-data:\s+expected a JSON object`),
+			},
+			{
+				Config: `
+output "content" {
+  value = provider::cloud-config::preview("## template: jinja\n#cloud-config\nhostname: {{ v1.local_hostname }}", jsonencode({
+    v1 = { local_hostname = "node1: primary" }
+  }))
+}
+`,
+				ExpectError: regexp.MustCompile(`Rendered content is not valid YAML`),
+			},
+		},
+	})
+}
@@ -20,6 +20,31 @@ type CloudConfigProvider struct {
 }
 
 type CloudConfigProviderModel struct {
+	Defaults *ProviderDefaultsModel `tfsdk:"defaults"`
+}
+
+// ProviderData
+// Provider configuration, shared with resources and data sources
+type ProviderData struct {
+	Defaults *ProviderDefaultsModel
+	// NOTE: provider configuration may depend on values which are unknown during plan
+	Known bool
+}
+
+// defaults
+// Provider defaults, nil-safe for resources which weren't configured
+func (p *ProviderData) defaults() *ProviderDefaultsModel {
+	if p == nil {
+		return nil
+	}
+
+	return p.Defaults
+}
+
+// known
+// Whether provider configuration is known, nil-safe for resources which weren't configured
+func (p *ProviderData) known() bool {
+	return p == nil || p.Known
 }
 
 func (p *CloudConfigProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -30,6 +55,9 @@ func (p *CloudConfigProvider) Metadata(ctx context.Context, req provider.Metadat
 func (p *CloudConfigProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{},
+		Blocks: map[string]schema.Block{
+			"defaults": defaultsBlock(),
+		},
 	}
 }
 
@@ -41,24 +69,50 @@ func (p *CloudConfigProvider) Configure(ctx context.Context, req provider.Config
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	providerData := &ProviderData{
+		Defaults: data.Defaults,
+		Known:    req.Config.Raw.IsFullyKnown(),
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
 }
 
 func (p *CloudConfigProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		NewCloudConfigEphemeralResource,
+	}
 }
 
 func (p *CloudConfigProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCloudConfigResource,
+		NewMultipartResource,
+		NewNetworkResource,
+		NewMetaDataResource,
+		NewSeedISOResource,
+		NewSeedDirectoryResource,
 	}
 }
 
 func (p *CloudConfigProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewCloudConfigDataSource,
+		NewNetworkDataSource,
+		NewMetaDataDataSource,
+		NewVMwareGuestinfoDataSource,
+	}
 }
 
 func (p *CloudConfigProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewRenderFunction,
+		NewDecodeFunction,
+		NewHashPasswordFunction,
+		NewPreviewFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+var _ function.Function = &RenderFunction{}
+
+func NewRenderFunction() function.Function {
+	return &RenderFunction{}
+}
+
+// RenderFunction
+// Renders the same YAML as `cloud-config` resource, but inline
+type RenderFunction struct {
+}
+
+func (f *RenderFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "render"
+}
+
+func (f *RenderFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Render cloud-config YAML",
+		MarkdownDescription: "Renders an object shaped like the `cloud-config` resource into the same YAML as its `content` attribute. Blocks are passed as objects (single blocks) or lists of objects (repeated blocks), omitted attributes are treated as `null`. Functions have no access to the provider configuration, so `defaults` of the provider are not applied. Provider-defined functions require Terraform 1.8 or later.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:                "config",
+				MarkdownDescription: "Object with the same attributes and blocks as the `cloud-config` resource",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RenderFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var config types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &config))
+
+	if resp.Error != nil {
+		return
+	}
+
+	data, err := modelFromDynamic(ctx, config)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	content, diagnostics := ExportContent(ctx, data, nil)
+	if diagnostics.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diagnostics)
+		return
+	}
+
+	// NOTE: functions can't emit warnings, so only errors are reported
+	if diagnostics := checkContent(ctx, data, content); diagnostics.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diagnostics)
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, content))
+}
+
+// cloudConfigSchema
+// Schema of `cloud-config` resource, the single source of truth for every other representation
+func cloudConfigSchema(ctx context.Context) schema.Schema {
+	resp := resource.SchemaResponse{}
+	(&CloudConfigResource{}).Schema(ctx, resource.SchemaRequest{}, &resp)
+
+	return resp.Schema
+}
+
+// modelFromDynamic
+// Converts an arbitrary object into `CloudConfigResourceModel`
+func modelFromDynamic(ctx context.Context, value types.Dynamic) (CloudConfigResourceModel, error) {
+	var data CloudConfigResourceModel
+
+	if value.IsNull() || value.IsUnderlyingValueNull() {
+		return data, errors.New("configuration object must not be null")
+	}
+
+	raw, err := value.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return data, err
+	}
+
+	objectType := cloudConfigSchema(ctx).Type()
+
+	coerced, err := utils.Coerce(raw, objectType.TerraformType(ctx))
+	if err != nil {
+		return data, err
+	}
+
+	for _, name := range computedAttributes {
+		value, _, err := tftypes.WalkAttributePath(coerced, tftypes.NewAttributePath().WithAttributeName(name))
+		if err == nil && !value.(tftypes.Value).IsNull() {
+			return data, fmt.Errorf("%s: attribute is computed and cannot be set", name)
+		}
+	}
+
+	object, err := objectType.ValueFromTerraform(ctx, coerced)
+	if err != nil {
+		return data, err
+	}
+
+	diagnostics := object.(types.Object).As(ctx, &data, basetypes.ObjectAsOptions{})
+	if diagnostics.HasError() {
+		return data, errors.New(diagnostics.Errors()[0].Detail())
+	}
+
+	return data, nil
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestRenderFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::render({
+    hostname = "one"
+    runcmd   = ["echo '11'"]
+
+    chpasswd = {
+      expire = false
+    }
+
+    users = [
+      { name = "admin", uid = 1001 },
+    ]
+  })
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue(
+						"test",
+						knownvalue.StringExact(expectedOutput(`
+hostname: one
+runcmd:
+    - echo '11'
+chpasswd:
+    expire: false
+users:
+    - name: admin
+      uid: 1001
+`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestRenderFunctionMatchesResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExampleResourceConfig("one") + `
+output "test" {
+  value = provider::cloud-config::render({
+    hostname                  = "one"
+    fqdn                      = "one.lan"
+    prefer_fqdn_over_hostname = true
+    preserve_hostname         = true
+    create_hostname_file      = false
+
+    locale            = "en_one"
+    locale_configfile = "/etc/locale"
+
+    timezone = "Asia/one"
+
+    runcmd = ["echo '11'", "cat one"]
+
+    manage_etc_hosts_localhost = true
+
+    ssh_authorized_keys = ["one"]
+  }) == cloud-config.test.content
+
+  sensitive = true
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("test", knownvalue.Bool(true)),
+				},
+			},
+		},
+	})
+}
+
+func TestRenderFunctionUnsupportedAttribute(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::render({
+    hostname = "one"
+    unknown  = true
+  })
+}
+`,
+				ExpectError: regexp.MustCompile(`unknown: unsupported attribute`),
+			},
+		},
+	})
+}
+
+func TestRenderFunctionEmptyBlocks(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::render({
+    zypper = {}
+  })
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue(
+						"test",
+						knownvalue.StringExact(expectedOutput(`
+zypper:
+    repos: []
+    config: {}
+`)),
+					),
+				},
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::cloud-config::render({
+    wireguard = {}
+  })
+}
+`,
+				// NOTE: rendered, but rejected by the schema instead of crashing the provider
+				ExpectError: regexp.MustCompile("`wireguard.interfaces`\\s+of\\s+rendered\\s+cloud-config:\\s+minItems"),
+			},
+		},
+	})
+}
@@ -0,0 +1,283 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"gopkg.in/yaml.v3"
+)
+
+const cloudConfigSchemaURL = "schema-cloud-config-v1.json"
+
+// NOTE: update with `mise run schema`
+//
+//go:embed schemas/schema-cloud-config-v1.json
+var cloudConfigSchemaJSON []byte
+
+var compiledCloudConfigSchema = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(cloudConfigSchemaJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(cloudConfigSchemaURL, doc); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(cloudConfigSchemaURL)
+})
+
+// validateSchema
+// Validates rendered content against cloud-init JSON schema.
+// Errors point to the attribute of `model` which produced the offending key, relative to `base`.
+// Keys merged from provider defaults or `extra_yaml` are reported without an attribute
+func validateSchema(ctx context.Context, content string, base path.Path, model CloudConfigResourceModel) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	sch, err := compiledCloudConfigSchema()
+	if err != nil {
+		diagnostics.AddError("Cannot compile cloud-init JSON schema", err.Error())
+		return diagnostics
+	}
+
+	var document any
+	if err := yaml.Unmarshal([]byte(strings.TrimPrefix(content, hat)), &document); err != nil {
+		diagnostics.AddError("Cannot unmarshal YAML", err.Error())
+		return diagnostics
+	}
+
+	// NOTE: JSON round-trip, validator only accepts JSON types
+	raw, err := json.Marshal(document)
+	if err != nil {
+		diagnostics.AddError("Cannot convert YAML to JSON", err.Error())
+		return diagnostics
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		diagnostics.AddError("Cannot convert YAML to JSON", err.Error())
+		return diagnostics
+	}
+
+	err = sch.Validate(instance)
+	if err == nil {
+		return diagnostics
+	}
+
+	var validationError *jsonschema.ValidationError
+	if !errors.As(err, &validationError) {
+		diagnostics.AddError("Cannot validate cloud-config", err.Error())
+		return diagnostics
+	}
+
+	resourceSchema := cloudConfigSchema(ctx)
+
+	origin, d := attributesDocument(ctx, model)
+	if d.HasError() {
+		diagnostics.Append(d...)
+		return diagnostics
+	}
+
+	for _, e := range schemaErrors(validationError) {
+		detail := fmt.Sprintf("Key `%s` of rendered cloud-config: %s", keyPath(e.location), e.message)
+
+		attribute := base
+		relative := path.Empty()
+
+		// NOTE: both documents are followed, so indices of the merged document are mapped back to attributes
+		merged, own := document, origin
+
+		for _, step := range e.location {
+			var next, nextAttribute path.Path
+
+			if index, err := strconv.Atoi(step); err == nil {
+				ownIndex, ok := originIndex(merged, own, index)
+				if !ok {
+					break
+				}
+
+				next, nextAttribute = relative.AtListIndex(ownIndex), attribute.AtListIndex(ownIndex)
+				merged, own = merged.([]any)[index], own.([]any)[ownIndex]
+			} else {
+				mergedMap, _ := merged.(map[string]any)
+				ownMap, _ := own.(map[string]any)
+
+				value, ok := ownMap[step]
+				if !ok {
+					break
+				}
+
+				next, nextAttribute = relative.AtName(step), attribute.AtName(step)
+				merged, own = mergedMap[step], value
+			}
+
+			if _, d := resourceSchema.TypeAtPath(ctx, next); d.HasError() {
+				break
+			}
+
+			relative, attribute = next, nextAttribute
+		}
+
+		if len(attribute.Steps()) == 0 {
+			diagnostics.AddError("Invalid cloud-config", detail)
+			continue
+		}
+
+		diagnostics.AddAttributeError(attribute, "Invalid cloud-config", detail)
+	}
+
+	return diagnostics
+}
+
+// attributesDocument
+// Document rendered from attributes of the model alone, without provider defaults and `extra_yaml`
+func attributesDocument(ctx context.Context, model CloudConfigResourceModel) (any, diag.Diagnostics) {
+	output, diagnostics := transform(ctx, model)
+	if diagnostics.HasError() {
+		return nil, diagnostics
+	}
+
+	raw, err := yaml.Marshal(output)
+	if err != nil {
+		diagnostics.AddError("Cannot marshal YAML", err.Error())
+		return nil, diagnostics
+	}
+
+	var document any
+	if err := yaml.Unmarshal(raw, &document); err != nil {
+		diagnostics.AddError("Cannot unmarshal YAML", err.Error())
+		return nil, diagnostics
+	}
+
+	return document, diagnostics
+}
+
+// originIndex
+// Index of `index` item of the merged list in the list rendered from attributes. Merging only adds items
+// around the ones of attributes and keeps their order, so they are matched in order, e.g. after prepended defaults
+func originIndex(merged, own any, index int) (int, bool) {
+	mergedList, _ := merged.([]any)
+	ownList, _ := own.([]any)
+
+	if index >= len(mergedList) {
+		return 0, false
+	}
+
+	next := 0
+	for i := 0; i <= index; i++ {
+		if next < len(ownList) && reflect.DeepEqual(mergedList[i], ownList[next]) {
+			if i == index {
+				return next, true
+			}
+			next++
+		}
+	}
+
+	return 0, false
+}
+
+type schemaError struct {
+	location []string
+	message  string
+}
+
+// schemaErrors
+// Collects the most specific errors. Branches of `anyOf`/`oneOf` which don't even match
+// the type are skipped, unless none of them does
+func schemaErrors(err *jsonschema.ValidationError) []schemaError {
+	if len(err.Causes) == 0 {
+		return []schemaError{{location: err.InstanceLocation, message: err.BasicOutput().Error.String()}}
+	}
+
+	causes := err.Causes
+
+	switch err.ErrorKind.(type) {
+	case *kind.AnyOf, *kind.OneOf:
+		var matching []*jsonschema.ValidationError
+		var got string
+		var want []string
+
+		for _, cause := range causes {
+			mismatch := typeMismatch(cause)
+			if mismatch == nil {
+				matching = append(matching, cause)
+				continue
+			}
+
+			got = mismatch.Got
+			want = append(want, mismatch.Want...)
+		}
+
+		if len(matching) == 0 {
+			return []schemaError{{
+				location: err.InstanceLocation,
+				message:  fmt.Sprintf("got %s, want %s", got, strings.Join(want, " or ")),
+			}}
+		}
+
+		causes = matching
+	}
+
+	var result []schemaError
+	for _, cause := range causes {
+		result = append(result, schemaErrors(cause)...)
+	}
+
+	return result
+}
+
+// typeMismatch
+// Type error of a branch, if that's the only reason it failed
+func typeMismatch(err *jsonschema.ValidationError) *kind.Type {
+	for len(err.Causes) == 1 {
+		if _, ok := err.ErrorKind.(*kind.Reference); !ok {
+			break
+		}
+		err = err.Causes[0]
+	}
+
+	if len(err.Causes) == 0 {
+		if mismatch, ok := err.ErrorKind.(*kind.Type); ok {
+			return mismatch
+		}
+	}
+
+	return nil
+}
+
+// keyPath
+// Formats JSON pointer tokens the same way as other diagnostics, e.g. `users[0].name`
+func keyPath(location []string) string {
+	var sb strings.Builder
+
+	for _, step := range location {
+		if _, err := strconv.Atoi(step); err == nil {
+			fmt.Fprintf(&sb, "[%s]", step)
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(step)
+	}
+
+	if sb.Len() == 0 {
+		return "."
+	}
+
+	return sb.String()
+}
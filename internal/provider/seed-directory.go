@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+)
+
+var _ resource.Resource = &SeedDirectoryResource{}
+var _ resource.ResourceWithModifyPlan = &SeedDirectoryResource{}
+
+func NewSeedDirectoryResource() resource.Resource {
+	return &SeedDirectoryResource{}
+}
+
+// SeedDirectoryResource
+// NoCloud seed as plain files, e.g. to be served over HTTP or copied into an image
+type SeedDirectoryResource struct {
+}
+
+type SeedDirectoryResourceModel struct {
+	Path          types.String `tfsdk:"path"`
+	SeedURL       types.String `tfsdk:"seed_url"`
+	KernelCmdline types.String `tfsdk:"kernel_cmdline"`
+	SMBIOSSerial  types.String `tfsdk:"smbios_serial"`
+
+	ccmodules.SeedModel
+}
+
+func (r *SeedDirectoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_seed_directory"
+}
+
+func (r *SeedDirectoryResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "Directory with files of [NoCloud](https://cloudinit.readthedocs.io/en/latest/reference/datasources/nocloud.html) seed. Files are written again when any input changes, or when they are modified outside of Terraform.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the directory on local disk, parent directories are created. A directory created by the provider is accessible to those who can read the files, see **file_permission**, permissions of an existing one are not changed.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"seed_url": schema.StringAttribute{
+				MarkdownDescription: "URL, which the instance reaches the directory at, e.g. `http://10.0.2.2:8000/` when it is served by `python3 -m http.server`. *Default*: `file://` URL of **path**.",
+				Optional:            true,
+			},
+			"kernel_cmdline": schema.StringAttribute{
+				MarkdownDescription: "Kernel command-line argument pointing NoCloud to **seed_url**, e.g. `ds=nocloud;s=http://10.0.2.2:8000/`. Escape `;` as `\\;` in GRUB config",
+				Computed:            true,
+			},
+			"smbios_serial": schema.StringAttribute{
+				MarkdownDescription: "SMBIOS system serial number pointing NoCloud to **seed_url**, e.g. for `-smbios type=1,serial=...` of QEMU or `sysinfo` of libvirt",
+				Computed:            true,
+			},
+		},
+	}
+
+	module := ccmodules.Seed()
+	maps.Insert(schema.Attributes, maps.All(module.Attributes()))
+
+	resp.Schema = schema
+}
+
+func (r *SeedDirectoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SeedDirectoryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(writeSeedDirectory(&data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SeedDirectoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SeedDirectoryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NOTE: same as `local_file`, removed or modified files are written again
+	for _, file := range seedFiles(data.SeedModel) {
+		content, err := os.ReadFile(filepath.Join(data.Path.ValueString(), file.Name))
+		if errors.Is(err, os.ErrNotExist) || (err == nil && !bytes.Equal(content, file.Data)) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("path"), "Cannot read seed", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SeedDirectoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SeedDirectoryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(writeSeedDirectory(&data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SeedDirectoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SeedDirectoryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dir := data.Path.ValueString()
+
+	for _, name := range seedFileNames {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			resp.Diagnostics.AddAttributeError(path.Root("path"), "Cannot remove seed", err.Error())
+			return
+		}
+	}
+
+	// NOTE: directory is kept, if something else is stored there
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 0 {
+		if err := os.Remove(dir); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("path"), "Cannot remove directory", err.Error())
+		}
+	}
+}
+
+func (r *SeedDirectoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data SeedDirectoryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NOTE: unlike content, arguments only depend on where the seed is
+	if data.Path.IsUnknown() || data.SeedURL.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(setSeedArguments(&data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("kernel_cmdline"), data.KernelCmdline)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("smbios_serial"), data.SMBIOSSerial)...)
+}
+
+// setSeedArguments
+// Renders `kernel_cmdline` and `smbios_serial`, NoCloud expects the URL to end with a slash
+func setSeedArguments(data *SeedDirectoryResourceModel) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	seed := data.SeedURL.ValueString()
+
+	if data.SeedURL.IsNull() {
+		dir, err := filepath.Abs(data.Path.ValueString())
+		if err != nil {
+			diagnostics.AddAttributeError(path.Root("path"), "Cannot resolve path", err.Error())
+			return diagnostics
+		}
+
+		seed = (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()
+	}
+
+	if !strings.HasSuffix(seed, "/") {
+		seed += "/"
+	}
+
+	data.KernelCmdline = types.StringValue("ds=nocloud;s=" + seed)
+	data.SMBIOSSerial = types.StringValue("ds=nocloud;s=" + seed)
+
+	return diagnostics
+}
+
+// writeSeedDirectory
+// Writes files of the seed, removing the ones which are no longer configured
+func writeSeedDirectory(data *SeedDirectoryResourceModel) diag.Diagnostics {
+	diagnostics := setSeedArguments(data)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	dir := data.Path.ValueString()
+	mode := seedFileMode(data.SeedModel)
+
+	// NOTE: permissions of an existing directory are left as they are
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		// NOTE: directory can be listed by those who can read the files
+		dirMode := mode | (mode&0444)>>2
+
+		if err := os.MkdirAll(dir, dirMode); err != nil {
+			diagnostics.AddAttributeError(path.Root("path"), "Cannot create directory", err.Error())
+			return diagnostics
+		}
+
+		// NOTE: `MkdirAll` is subject to umask
+		if err := os.Chmod(dir, dirMode); err != nil {
+			diagnostics.AddAttributeError(path.Root("path"), "Cannot change permissions", err.Error())
+			return diagnostics
+		}
+	}
+
+	files := seedFiles(data.SeedModel)
+	written := map[string]bool{}
+
+	for _, file := range files {
+		name := filepath.Join(dir, file.Name)
+
+		if err := os.WriteFile(name, file.Data, mode); err != nil {
+			diagnostics.AddAttributeError(path.Root("path"), "Cannot write seed", err.Error())
+			return diagnostics
+		}
+
+		// NOTE: permissions of an existing file are not changed by `WriteFile`
+		if err := os.Chmod(name, mode); err != nil {
+			diagnostics.AddAttributeError(path.Root("path"), "Cannot change permissions", err.Error())
+			return diagnostics
+		}
+
+		written[file.Name] = true
+	}
+
+	for _, name := range seedFileNames {
+		if written[name] {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			diagnostics.AddAttributeError(path.Root("path"), "Cannot remove seed", err.Error())
+			return diagnostics
+		}
+	}
+
+	return diagnostics
+}
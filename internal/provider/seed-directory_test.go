@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const seedDirectoryResourceName = "cloud-config_seed_directory.test"
+
+// testCheckSeedDirectory
+// Compares files of the directory with `files`, missing ones are expected to be absent
+func testCheckSeedDirectory(dir string, dirMode os.FileMode, mode os.FileMode, files map[string]string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().Perm() != dirMode {
+			return fmt.Errorf("unexpected permissions of directory: %o", info.Mode().Perm())
+		}
+
+		for _, name := range seedFileNames {
+			content, err := os.ReadFile(filepath.Join(dir, name))
+
+			expected, ok := files[name]
+			if !ok {
+				if !os.IsNotExist(err) {
+					return fmt.Errorf("expected %s to be absent", name)
+				}
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if string(content) != expected {
+				return fmt.Errorf("expected %s to be %q, got: %q", name, expected, content)
+			}
+
+			info, err := os.Stat(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+
+			if info.Mode().Perm() != mode {
+				return fmt.Errorf("expected permissions of %s to be %o, got: %o", name, mode, info.Mode().Perm())
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestAccSeedDirectoryResource(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "seed")
+
+	config := fmt.Sprintf(`
+resource "cloud-config_seed_directory" "test" {
+  path           = %q
+  user_data      = "#cloud-config\nhostname: node1"
+  network_config = "version: 2"
+}
+`, dir)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(seedDirectoryResourceName, tfjsonpath.New("kernel_cmdline"), knownvalue.StringExact("ds=nocloud;s=file://"+dir+"/")),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(seedDirectoryResourceName, tfjsonpath.New("smbios_serial"), knownvalue.StringExact("ds=nocloud;s=file://"+dir+"/")),
+				},
+				Check: testCheckSeedDirectory(dir, 0700, 0600, map[string]string{
+					"user-data":      "#cloud-config\nhostname: node1",
+					"meta-data":      "",
+					"network-config": "version: 2",
+				}),
+			},
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filepath.Join(dir, "user-data"), []byte("modified"), 0600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(seedDirectoryResourceName, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testCheckSeedDirectory(dir, 0700, 0600, map[string]string{
+					"user-data":      "#cloud-config\nhostname: node1",
+					"meta-data":      "",
+					"network-config": "version: 2",
+				}),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "cloud-config_seed_directory" "test" {
+  path            = %q
+  seed_url        = "http://10.0.2.2:8000"
+  user_data       = "#cloud-config\nhostname: node1"
+  meta_data       = "instance-id: i-0001"
+  vendor_data     = "#cloud-config\nruncmd: []"
+  file_permission = "0644"
+}
+`, dir),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(seedDirectoryResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(seedDirectoryResourceName, tfjsonpath.New("kernel_cmdline"), knownvalue.StringExact("ds=nocloud;s=http://10.0.2.2:8000/")),
+					statecheck.ExpectKnownValue(seedDirectoryResourceName, tfjsonpath.New("smbios_serial"), knownvalue.StringExact("ds=nocloud;s=http://10.0.2.2:8000/")),
+				},
+				Check: testCheckSeedDirectory(dir, 0700, 0644, map[string]string{
+					"user-data":   "#cloud-config\nhostname: node1",
+					"meta-data":   "instance-id: i-0001",
+					"vendor-data": "#cloud-config\nruncmd: []",
+				}),
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				return fmt.Errorf("expected %s to be removed", dir)
+			}
+
+			return nil
+		},
+	})
+}
+
+func TestAccSeedDirectoryResourceExistingDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Permissions of a directory, which wasn't created by the provider, are kept
+			{
+				Config: fmt.Sprintf(`
+resource "cloud-config_seed_directory" "test" {
+  path      = %q
+  user_data = "#cloud-config\nhostname: node1"
+}
+`, dir),
+				Check: testCheckSeedDirectory(dir, 0755, 0600, map[string]string{
+					"user-data": "#cloud-config\nhostname: node1",
+					"meta-data": "",
+				}),
+			},
+		},
+	})
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+var _ resource.Resource = &SeedISOResource{}
+var _ resource.ResourceWithModifyPlan = &SeedISOResource{}
+
+func NewSeedISOResource() resource.Resource {
+	return &SeedISOResource{}
+}
+
+// SeedISOResource
+// NoCloud seed image, the same as `genisoimage -volid cidata -joliet` would build
+type SeedISOResource struct {
+}
+
+type SeedISOResourceModel struct {
+	Path   types.String `tfsdk:"path"`
+	SHA256 types.String `tfsdk:"sha256"`
+
+	ccmodules.SeedModel
+}
+
+func (r *SeedISOResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_seed_iso"
+}
+
+func (r *SeedISOResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	schema := schema.Schema{
+		MarkdownDescription: "ISO 9660 image labelled `cidata`, which [NoCloud](https://cloudinit.readthedocs.io/en/latest/reference/datasources/nocloud.html) datasource reads seed from. Attach it as a CD-ROM to a VM. The image is rebuilt when any input changes, or when the file is modified outside of Terraform.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the image on local disk, parent directories are created.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum of the image, hex encoded. Same files always produce the same image",
+				Computed:            true,
+			},
+		},
+	}
+
+	module := ccmodules.Seed()
+	maps.Insert(schema.Attributes, maps.All(module.Attributes()))
+
+	resp.Schema = schema
+}
+
+func (r *SeedISOResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SeedISOResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+
+	resp.Diagnostics.Append(writeSeedISO(&data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SeedISOResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SeedISOResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NOTE: removed or modified image is built again
+	image, err := os.ReadFile(data.Path.ValueString())
+	if errors.Is(err, os.ErrNotExist) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Cannot read image", err.Error())
+		return
+	}
+
+	data.SHA256 = types.StringValue(seedChecksum(image))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SeedISOResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SeedISOResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(writeSeedISO(&data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SeedISOResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SeedISOResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := os.Remove(data.Path.ValueString()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Cannot remove image", err.Error())
+	}
+}
+
+func (r *SeedISOResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Resource is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// NOTE: same as `cloud-config`, `sha256` is only known when every input is known
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	var data SeedISOResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	image, diagnostics := buildSeedISO(data.SeedModel)
+	resp.Diagnostics.Append(diagnostics...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), seedChecksum(image))...)
+}
+
+// buildSeedISO
+// Image with every file of the seed in its root directory
+func buildSeedISO(model ccmodules.SeedModel) ([]byte, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	image, err := utils.ISO9660(seedLabel, seedFiles(model))
+	if err != nil {
+		diagnostics.AddError("Cannot build image", err.Error())
+	}
+
+	return image, diagnostics
+}
+
+// writeSeedISO
+// Builds the image and writes it to `path`, along with its checksum
+func writeSeedISO(data *SeedISOResourceModel) diag.Diagnostics {
+	image, diagnostics := buildSeedISO(data.SeedModel)
+	if diagnostics.HasError() {
+		return diagnostics
+	}
+
+	name := data.Path.ValueString()
+	mode := seedFileMode(data.SeedModel)
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		diagnostics.AddAttributeError(path.Root("path"), "Cannot create directory", err.Error())
+		return diagnostics
+	}
+
+	if err := os.WriteFile(name, image, mode); err != nil {
+		diagnostics.AddAttributeError(path.Root("path"), "Cannot write image", err.Error())
+		return diagnostics
+	}
+
+	// NOTE: permissions of an existing file are not changed by `WriteFile`
+	if err := os.Chmod(name, mode); err != nil {
+		diagnostics.AddAttributeError(path.Root("path"), "Cannot change permissions", err.Error())
+		return diagnostics
+	}
+
+	data.SHA256 = types.StringValue(seedChecksum(image))
+
+	return diagnostics
+}
+
+func seedChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+const seedISOResourceName = "cloud-config_seed_iso.test"
+
+// testCheckSeedISO
+// Compares the image on disk with the one built from `files` directly
+func testCheckSeedISO(name string, mode os.FileMode, files []utils.ISO9660File) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		expected, err := utils.ISO9660(seedLabel, files)
+		if err != nil {
+			return err
+		}
+
+		image, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(image, expected) {
+			return fmt.Errorf("image %s differs from the expected one", name)
+		}
+
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().Perm() != mode {
+			return fmt.Errorf("expected permissions %o, got: %o", mode, info.Mode().Perm())
+		}
+
+		return nil
+	}
+}
+
+func TestAccSeedISOResource(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "seeds", "seed.iso")
+
+	files := []utils.ISO9660File{
+		{Name: "user-data", Data: []byte("#cloud-config\nhostname: node1")},
+		{Name: "meta-data", Data: []byte("instance-id: i-0001")},
+	}
+	image, err := utils.ISO9660(seedLabel, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := append(files, utils.ISO9660File{Name: "network-config", Data: []byte("version: 2")})
+
+	config := fmt.Sprintf(`
+resource "cloud-config_seed_iso" "test" {
+  path      = %q
+  user_data = "#cloud-config\nhostname: node1"
+  meta_data = "instance-id: i-0001"
+}
+`, name)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(seedISOResourceName, tfjsonpath.New("sha256"), knownvalue.StringExact(seedChecksum(image))),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(seedISOResourceName, tfjsonpath.New("sha256"), knownvalue.StringExact(seedChecksum(image))),
+				},
+				Check: testCheckSeedISO(name, 0600, files),
+			},
+			{
+				PreConfig: func() {
+					if err := os.Remove(name); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(seedISOResourceName, plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testCheckSeedISO(name, 0600, files),
+			},
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(name, []byte("modified"), 0600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(seedISOResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testCheckSeedISO(name, 0600, files),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "cloud-config_seed_iso" "test" {
+  path            = %q
+  user_data       = "#cloud-config\nhostname: node1"
+  meta_data       = "instance-id: i-0001"
+  network_config  = "version: 2"
+  file_permission = "0644"
+}
+`, name),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(seedISOResourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testCheckSeedISO(name, 0644, updated),
+			},
+		},
+	})
+}
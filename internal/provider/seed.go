@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"os"
+	"strconv"
+
+	ccmodules "github.com/opa-oz/terraform-provider-cloud-config/internal/cc-modules"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+// seedLabel
+// Volume label, which NoCloud looks for
+const seedLabel = "cidata"
+
+// seedFileNames
+// Every file a seed may consist of
+var seedFileNames = []string{"user-data", "meta-data", "network-config", "vendor-data"}
+
+// seedFiles
+// Files of NoCloud seed, `meta-data` is required by cloud-init, so it is written even when empty
+func seedFiles(model ccmodules.SeedModel) []utils.ISO9660File {
+	files := []utils.ISO9660File{
+		{Name: "user-data", Data: []byte(model.UserData.ValueString())},
+		{Name: "meta-data", Data: []byte(model.MetaData.ValueString())},
+	}
+
+	if !model.NetworkConfig.IsNull() {
+		files = append(files, utils.ISO9660File{Name: "network-config", Data: []byte(model.NetworkConfig.ValueString())})
+	}
+
+	if !model.VendorData.IsNull() {
+		files = append(files, utils.ISO9660File{Name: "vendor-data", Data: []byte(model.VendorData.ValueString())})
+	}
+
+	return files
+}
+
+// seedFileMode
+// Permissions of written files, validated by the schema
+func seedFileMode(model ccmodules.SeedModel) os.FileMode {
+	if model.FilePermission.IsNull() {
+		return 0600
+	}
+
+	mode, err := strconv.ParseUint(model.FilePermission.ValueString(), 8, 32)
+	if err != nil {
+		return 0600
+	}
+
+	return os.FileMode(mode)
+}
@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// platformLimit
+// User-data size limit of a platform, `size` of 0 means there is no known limit
+type platformLimit struct {
+	size   int
+	base64 bool // limit applies to base64 encoded user-data
+	gzip   bool // platform accepts gzip compressed user-data
+}
+
+var platformLimits = map[string]platformLimit{
+	"aws":       {size: 16 * 1024, base64: false, gzip: true},
+	"azure":     {size: 64 * 1024, base64: true, gzip: true},
+	"gce":       {size: 256 * 1024, base64: false, gzip: false},
+	"openstack": {size: 65535, base64: true, gzip: true},
+	"nocloud":   {},
+	"vmware":    {},
+}
+
+// targetPlatforms
+// Sorted names of supported platforms
+func targetPlatforms() []string {
+	platforms := make([]string, 0, len(platformLimits))
+	for platform := range platformLimits {
+		platforms = append(platforms, platform)
+	}
+
+	slices.Sort(platforms)
+
+	return platforms
+}
+
+// checkPlatformLimits
+// Reports an error if content doesn't fit into user-data of the target platform,
+// or a warning if only compressed content does
+func checkPlatformLimits(targetPlatform types.String, content string) diag.Diagnostics {
+	var diagnostics diag.Diagnostics
+
+	if targetPlatform.IsNull() || targetPlatform.IsUnknown() {
+		return diagnostics
+	}
+
+	platform := targetPlatform.ValueString()
+
+	limit, ok := platformLimits[platform]
+	if !ok || limit.size == 0 {
+		return diagnostics
+	}
+
+	size := limit.measure([]byte(content))
+	if size <= limit.size {
+		return diagnostics
+	}
+
+	summary := fmt.Sprintf("Content exceeds %s user-data limit", platform)
+	detail := fmt.Sprintf("%s is %d bytes, limit of %s is %d bytes.", limit.describe(), size, platform, limit.size)
+
+	if contributors := largestContributors(content); contributors != "" {
+		detail += "\n\n" + contributors
+	}
+
+	if limit.gzip {
+		compressed, err := utils.Gzip([]byte(content))
+		if err == nil && limit.measure(compressed) <= limit.size {
+			diagnostics.AddAttributeWarning(
+				path.Root("target_platform"),
+				summary,
+				detail+"\n\nCompressed content fits, use `content_gzip_base64` instead of `content`.",
+			)
+			return diagnostics
+		}
+	}
+
+	diagnostics.AddAttributeError(path.Root("target_platform"), summary, detail)
+
+	return diagnostics
+}
+
+func (l platformLimit) measure(content []byte) int {
+	if l.base64 {
+		return base64.StdEncoding.EncodedLen(len(content))
+	}
+
+	return len(content)
+}
+
+func (l platformLimit) describe() string {
+	if l.base64 {
+		return "Base64 encoded content"
+	}
+
+	return "Content"
+}
+
+type contributor struct {
+	key  string
+	size int
+}
+
+// largestContributors
+// Lists top-level keys and list entries (e.g. `write_files` entries) taking the most space
+func largestContributors(content string) string {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.TrimPrefix(content, hat)), &node); err != nil || len(node.Content) == 0 {
+		return ""
+	}
+
+	root := node.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return ""
+	}
+
+	var modules, entries []contributor
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+
+		modules = append(modules, contributor{key: key.Value, size: nodeSize(key, value)})
+
+		if value.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for j, item := range value.Content {
+			entries = append(entries, contributor{
+				key:  fmt.Sprintf("%s[%d]%s", key.Value, j, entryName(item)),
+				size: nodeSize(nil, item),
+			})
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Largest modules: ")
+	sb.WriteString(formatContributors(modules))
+
+	if len(entries) > 0 {
+		sb.WriteString("\nLargest entries: ")
+		sb.WriteString(formatContributors(entries))
+	}
+
+	return sb.String()
+}
+
+func nodeSize(key *yaml.Node, value *yaml.Node) int {
+	node := value
+	if key != nil {
+		node = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, value}}
+	}
+
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return 0
+	}
+
+	return len(out)
+}
+
+// entryName
+// Human-readable name of a list entry, e.g. `path` of `write_files` or `name` of `users`
+func entryName(item *yaml.Node) string {
+	if item.Kind != yaml.MappingNode {
+		return ""
+	}
+
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		if k := item.Content[i].Value; k == "path" || k == "name" {
+			return fmt.Sprintf(" (%s)", item.Content[i+1].Value)
+		}
+	}
+
+	return ""
+}
+
+func formatContributors(contributors []contributor) string {
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].size > contributors[j].size
+	})
+
+	top := contributors[:min(3, len(contributors))]
+
+	parts := make([]string, len(top))
+	for i, c := range top {
+		parts[i] = fmt.Sprintf("`%s` (%d bytes)", c.key, c.size)
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/opa-oz/terraform-provider-cloud-config/internal/utils"
+)
+
+var _ datasource.DataSource = &VMwareGuestinfoDataSource{}
+
+func NewVMwareGuestinfoDataSource() datasource.DataSource {
+	return &VMwareGuestinfoDataSource{}
+}
+
+// VMwareGuestinfoDataSource
+// Encodes user-data and meta-data into `guestinfo` keys, which VMware datasource reads
+type VMwareGuestinfoDataSource struct {
+}
+
+type VMwareGuestinfoDataSourceModel struct {
+	UserData    types.String `tfsdk:"user_data"`
+	MetaData    types.String `tfsdk:"meta_data"`
+	VendorData  types.String `tfsdk:"vendor_data"`
+	ExtraConfig types.Map    `tfsdk:"extra_config"`
+}
+
+// vmwareGuestinfoEncoding
+// Encoding of every key, same as `content_gzip_base64`
+const vmwareGuestinfoEncoding = "gzip+base64"
+
+func (d *VMwareGuestinfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vmware_guestinfo"
+}
+
+func (d *VMwareGuestinfoDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "`guestinfo` keys of [VMware](https://cloudinit.readthedocs.io/en/latest/reference/datasources/vmware.html) datasource, ready to be passed to `extra_config` of `vsphere_virtual_machine`.",
+
+		Attributes: map[string]schema.Attribute{
+			"user_data": schema.StringAttribute{
+				MarkdownDescription: "Content of user-data, e.g. `content` of `cloud-config` or `cloud-config_multipart`.",
+				Required:            true,
+			},
+			"meta_data": schema.StringAttribute{
+				MarkdownDescription: "Content of meta-data, e.g. `content` of `cloud-config_meta_data`. Network configuration goes to its `network` key.",
+				Optional:            true,
+			},
+			"vendor_data": schema.StringAttribute{
+				MarkdownDescription: "Content of vendor-data.",
+				Optional:            true,
+			},
+			"extra_config": schema.MapAttribute{
+				MarkdownDescription: "`guestinfo.userdata`, `guestinfo.metadata`, `guestinfo.vendordata` and their `.encoding` keys. Values are gzip compressed and base64 encoded, keys of unset inputs are omitted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *VMwareGuestinfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VMwareGuestinfoDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read a data source")
+
+	extraConfig, diagnostics := vmwareGuestinfo(map[string]types.String{
+		"userdata":   data.UserData,
+		"metadata":   data.MetaData,
+		"vendordata": data.VendorData,
+	})
+	resp.Diagnostics.Append(diagnostics...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ExtraConfig = extraConfig
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// vmwareGuestinfo
+// `guestinfo.<key>` along with `guestinfo.<key>.encoding` for every set value
+func vmwareGuestinfo(values map[string]types.String) (types.Map, diag.Diagnostics) {
+	var diagnostics diag.Diagnostics
+
+	elements := map[string]attr.Value{}
+
+	for key, value := range values {
+		if value.IsNull() {
+			continue
+		}
+
+		compressed, err := utils.Gzip([]byte(value.ValueString()))
+		if err != nil {
+			diagnostics.AddError("Cannot compress "+key, err.Error())
+			continue
+		}
+
+		elements["guestinfo."+key] = types.StringValue(base64.StdEncoding.EncodeToString(compressed))
+		elements["guestinfo."+key+".encoding"] = types.StringValue(vmwareGuestinfoEncoding)
+	}
+
+	extraConfig, d := types.MapValue(types.StringType, elements)
+	diagnostics.Append(d...)
+
+	return extraConfig, diagnostics
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const vmwareGuestinfoDataSourceName = "data.cloud-config_vmware_guestinfo.test"
+
+// testCheckGuestinfo
+// Decodes `guestinfo.<key>` and compares it with `expected`
+func testCheckGuestinfo(key string, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[vmwareGuestinfoDataSourceName]
+		if !ok {
+			return fmt.Errorf("%s not found", vmwareGuestinfoDataSourceName)
+		}
+
+		compressed, err := base64.StdEncoding.DecodeString(rs.Primary.Attributes["extra_config.guestinfo."+key])
+		if err != nil {
+			return err
+		}
+
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return err
+		}
+
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		if string(content) != expected {
+			return fmt.Errorf("expected guestinfo.%s to be %q, got: %q", key, expected, content)
+		}
+
+		return nil
+	}
+}
+
+func TestAccVMwareGuestinfoDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "cloud-config" "test" {
+  hostname = "node1"
+}
+
+data "cloud-config_vmware_guestinfo" "test" {
+  user_data = resource.cloud-config.test.content
+  meta_data = "instance-id: i-0001"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						vmwareGuestinfoDataSourceName,
+						tfjsonpath.New("extra_config"),
+						knownvalue.MapPartial(map[string]knownvalue.Check{
+							"guestinfo.userdata.encoding": knownvalue.StringExact("gzip+base64"),
+							"guestinfo.metadata.encoding": knownvalue.StringExact("gzip+base64"),
+						}),
+					),
+					statecheck.ExpectKnownValue(vmwareGuestinfoDataSourceName, tfjsonpath.New("extra_config"), knownvalue.MapSizeExact(4)),
+					statecheck.CompareValuePairs(
+						vmwareGuestinfoDataSourceName,
+						tfjsonpath.New("extra_config").AtMapKey("guestinfo.userdata"),
+						"cloud-config.test",
+						tfjsonpath.New("content_gzip_base64"),
+						compare.ValuesSame(),
+					),
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testCheckGuestinfo("userdata", "#cloud-config\nhostname: node1"),
+					testCheckGuestinfo("metadata", "instance-id: i-0001"),
+				),
+			},
+		},
+	})
+}
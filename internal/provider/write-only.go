@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// writeOnlyValues
+// `name=value` of every write-only attribute which is set
+func writeOnlyValues(model CloudConfigModel) []string {
+	var values []string
+
+	add := func(name string, value types.String) {
+		if !value.IsNull() {
+			values = append(values, fmt.Sprintf("%s=%s", name, value.ValueString()))
+		}
+	}
+
+	if model.User != nil {
+		add("user.hashed_passwd_wo", model.User.HashedPasswdWO)
+		add("user.plain_text_passwd_wo", model.User.PlainTextPasswdWO)
+	}
+
+	if model.Users != nil {
+		for i, user := range *model.Users {
+			add(fmt.Sprintf("users.%d.hashed_passwd_wo", i), user.HashedPasswdWO)
+			add(fmt.Sprintf("users.%d.plain_text_passwd_wo", i), user.PlainTextPasswdWO)
+		}
+	}
+
+	if model.ChPasswd != nil && model.ChPasswd.Users != nil {
+		for i, user := range *model.ChPasswd.Users {
+			add(fmt.Sprintf("chpasswd.users.%d.password_wo", i), user.PasswordWO)
+		}
+	}
+
+	if model.SaltMinion != nil {
+		add("salt_minion.private_key_wo", model.SaltMinion.PrivateKeyWO)
+	}
+
+	if model.Landscape != nil && model.Landscape.Client != nil {
+		add("landscape.client.registration_key_wo", model.Landscape.Client.RegistrationKeyWO)
+	}
+
+	return values
+}
+
+// usesWriteOnly
+// Whether any write-only attribute is set, so rendered content carries secrets which must not be stored
+func usesWriteOnly(model CloudConfigModel) bool {
+	return len(writeOnlyValues(model)) > 0
+}
+
+// writeOnlyHash
+// HMAC-SHA256 of every write-only attribute which is set, keyed with `hash_salt`, so changes of secrets
+// are visible in the plan. `hash_salt` is stored in state too, so only strong secrets are hidden by it,
+// weak ones can be brute-forced by anyone with access to state. Null when no write-only attribute is set
+func writeOnlyHash(model CloudConfigModel, key types.String) types.String {
+	values := writeOnlyValues(model)
+
+	if len(values) == 0 {
+		return types.StringNull()
+	}
+
+	if key.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	mac := hmac.New(sha256.New, []byte(key.ValueString()))
+	mac.Write([]byte(strings.Join(values, "\n")))
+
+	return types.StringValue(hex.EncodeToString(mac.Sum(nil)))
+}
+
+// withoutWriteOnly
+// Drops rendered content, when it carries values of write-only attributes.
+// Only `content_redacted` is kept, secrets are replaced there anyway
+func withoutWriteOnly(data *CloudConfigResourceModel, config CloudConfigModel) {
+	if !usesWriteOnly(config) {
+		return
+	}
+
+	data.Content = types.StringNull()
+	data.ContentBase64 = types.StringNull()
+	data.ContentGzipBase64 = types.StringNull()
+}
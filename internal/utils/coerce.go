@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Coerce
+// Converts an arbitrary (usually dynamic) value into the given type, following
+// the same rules Terraform uses for type conversion:
+//   - missing object attributes become null
+//   - tuples become lists, objects become maps
+//   - primitives are converted between string, number and bool when possible
+//
+// Attributes which are not a part of the target object are reported as errors.
+func Coerce(val tftypes.Value, typ tftypes.Type) (tftypes.Value, error) {
+	return coerce("", val, typ)
+}
+
+func coerce(path string, val tftypes.Value, typ tftypes.Type) (tftypes.Value, error) {
+	if !val.IsKnown() {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+
+	if val.IsNull() {
+		return tftypes.NewValue(typ, nil), nil
+	}
+
+	switch {
+	case typ.Is(tftypes.DynamicPseudoType):
+		return val, nil
+	case typ.Is(tftypes.String):
+		return coerceString(path, val)
+	case typ.Is(tftypes.Number):
+		return coerceNumber(path, val)
+	case typ.Is(tftypes.Bool):
+		return coerceBool(path, val)
+	case typ.Is(tftypes.List{}):
+		return coerceSequence(path, val, typ, typ.(tftypes.List).ElementType)
+	case typ.Is(tftypes.Set{}):
+		return coerceSequence(path, val, typ, typ.(tftypes.Set).ElementType)
+	case typ.Is(tftypes.Map{}):
+		return coerceMap(path, val, typ.(tftypes.Map))
+	case typ.Is(tftypes.Object{}):
+		return coerceObject(path, val, typ.(tftypes.Object))
+	}
+
+	return val, pathError(path, "unsupported type %s", typ)
+}
+
+func coerceString(path string, val tftypes.Value) (tftypes.Value, error) {
+	switch {
+	case val.Type().Is(tftypes.String):
+		return val, nil
+	case val.Type().Is(tftypes.Number):
+		var n big.Float
+		if err := val.As(&n); err != nil {
+			return val, pathError(path, "%s", err)
+		}
+		return tftypes.NewValue(tftypes.String, n.Text('f', -1)), nil
+	case val.Type().Is(tftypes.Bool):
+		var b bool
+		if err := val.As(&b); err != nil {
+			return val, pathError(path, "%s", err)
+		}
+		return tftypes.NewValue(tftypes.String, strconv.FormatBool(b)), nil
+	}
+
+	return val, pathError(path, "string required, got %s", val.Type())
+}
+
+func coerceNumber(path string, val tftypes.Value) (tftypes.Value, error) {
+	switch {
+	case val.Type().Is(tftypes.Number):
+		return val, nil
+	case val.Type().Is(tftypes.String):
+		var s string
+		if err := val.As(&s); err != nil {
+			return val, pathError(path, "%s", err)
+		}
+		n, _, err := big.ParseFloat(s, 10, 512, big.ToNearestEven)
+		if err != nil {
+			return val, pathError(path, "a number is required, got %q", s)
+		}
+		return tftypes.NewValue(tftypes.Number, n), nil
+	}
+
+	return val, pathError(path, "number required, got %s", val.Type())
+}
+
+func coerceBool(path string, val tftypes.Value) (tftypes.Value, error) {
+	switch {
+	case val.Type().Is(tftypes.Bool):
+		return val, nil
+	case val.Type().Is(tftypes.String):
+		var s string
+		if err := val.As(&s); err != nil {
+			return val, pathError(path, "%s", err)
+		}
+		if s != "true" && s != "false" {
+			return val, pathError(path, "a bool is required, got %q", s)
+		}
+		return tftypes.NewValue(tftypes.Bool, s == "true"), nil
+	}
+
+	return val, pathError(path, "bool required, got %s", val.Type())
+}
+
+func coerceSequence(path string, val tftypes.Value, typ tftypes.Type, elemType tftypes.Type) (tftypes.Value, error) {
+	if !val.Type().Is(tftypes.List{}) && !val.Type().Is(tftypes.Set{}) && !val.Type().Is(tftypes.Tuple{}) {
+		return val, pathError(path, "list required, got %s", val.Type())
+	}
+
+	var elems []tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return val, pathError(path, "%s", err)
+	}
+
+	result := make([]tftypes.Value, len(elems))
+	for i, elem := range elems {
+		v, err := coerce(fmt.Sprintf("%s[%d]", path, i), elem, elemType)
+		if err != nil {
+			return val, err
+		}
+		result[i] = v
+	}
+
+	return tftypes.NewValue(typ, result), nil
+}
+
+func coerceMap(path string, val tftypes.Value, typ tftypes.Map) (tftypes.Value, error) {
+	if !val.Type().Is(tftypes.Map{}) && !val.Type().Is(tftypes.Object{}) {
+		return val, pathError(path, "map required, got %s", val.Type())
+	}
+
+	var elems map[string]tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return val, pathError(path, "%s", err)
+	}
+
+	result := make(map[string]tftypes.Value, len(elems))
+	for k, elem := range elems {
+		v, err := coerce(fmt.Sprintf("%s[%q]", path, k), elem, typ.ElementType)
+		if err != nil {
+			return val, err
+		}
+		result[k] = v
+	}
+
+	return tftypes.NewValue(typ, result), nil
+}
+
+func coerceObject(path string, val tftypes.Value, typ tftypes.Object) (tftypes.Value, error) {
+	if !val.Type().Is(tftypes.Map{}) && !val.Type().Is(tftypes.Object{}) {
+		return val, pathError(path, "object required, got %s", val.Type())
+	}
+
+	var elems map[string]tftypes.Value
+	if err := val.As(&elems); err != nil {
+		return val, pathError(path, "%s", err)
+	}
+
+	// NOTE: sorted, so the reported error is stable
+	keys := make([]string, 0, len(elems))
+	for k := range elems {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, ok := typ.AttributeTypes[k]; !ok {
+			return val, pathError(attributePath(path, k), "unsupported attribute")
+		}
+	}
+
+	result := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for k, attrType := range typ.AttributeTypes {
+		elem, ok := elems[k]
+		if !ok {
+			result[k] = tftypes.NewValue(attrType, nil)
+			continue
+		}
+
+		v, err := coerce(attributePath(path, k), elem, attrType)
+		if err != nil {
+			return val, err
+		}
+		result[k] = v
+	}
+
+	return tftypes.NewValue(typ, result), nil
+}
+
+func attributePath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func pathError(path string, format string, a ...any) error {
+	if path == "" {
+		return fmt.Errorf(format, a...)
+	}
+
+	return fmt.Errorf("%s: %s", path, fmt.Sprintf(format, a...))
+}
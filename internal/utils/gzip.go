@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// Gzip
+// Compresses data with an empty header (no name, zero modification time),
+// so the same input always produces the same output
+func Gzip(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
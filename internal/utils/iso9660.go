@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+const isoSectorSize = 2048
+
+// ISO9660File
+// File in the root directory of an image
+type ISO9660File struct {
+	Name string
+	Data []byte
+}
+
+// ISO9660
+// Writes an ISO 9660 image with Joliet extension, which keeps names such as `user-data` readable.
+// Only a root directory is supported, timestamps are fixed, so the same files always produce the same image
+func ISO9660(label string, files []ISO9660File) ([]byte, error) {
+	if len(label) > 32 {
+		return nil, errors.New("volume label must be at most 32 characters long")
+	}
+
+	for _, file := range files {
+		if file.Name == "" || len(file.Name) > 64 || strings.ContainsAny(file.Name, "/\\;*:?\"") {
+			return nil, errors.New("invalid file name `" + file.Name + "`")
+		}
+	}
+
+	// NOTE: layout is system area, primary and Joliet descriptors, terminator,
+	// path tables of both, root directories of both, and data of files
+	const (
+		primaryDescriptor = 16
+		jolietDescriptor  = 17
+		terminator        = 18
+		primaryLPath      = 19
+		primaryMPath      = 20
+		jolietLPath       = 21
+		jolietMPath       = 22
+		primaryRoot       = 23
+	)
+
+	primaryNames := make([][]byte, len(files))
+	jolietNames := make([][]byte, len(files))
+	for i, file := range files {
+		primaryNames[i] = []byte(isoPrimaryName(file.Name))
+		jolietNames[i] = isoJolietName(file.Name)
+	}
+
+	primaryRootSize := isoDirectorySize(primaryNames)
+	jolietRoot := primaryRoot + primaryRootSize/isoSectorSize
+	jolietRootSize := isoDirectorySize(jolietNames)
+
+	extents := make([]uint32, len(files))
+	next := uint32(jolietRoot + jolietRootSize/isoSectorSize)
+	for i, file := range files {
+		extents[i] = next
+		next += uint32((len(file.Data) + isoSectorSize - 1) / isoSectorSize)
+	}
+
+	image := make([]byte, int(next)*isoSectorSize)
+	sector := func(n int) []byte {
+		return image[n*isoSectorSize : (n+1)*isoSectorSize]
+	}
+
+	primaryRecords := make([]isoRecord, len(files))
+	jolietRecords := make([]isoRecord, len(files))
+	for i, file := range files {
+		primaryRecords[i] = isoRecord{name: primaryNames[i], extent: extents[i], size: uint32(len(file.Data))}
+		jolietRecords[i] = isoRecord{name: jolietNames[i], extent: extents[i], size: uint32(len(file.Data))}
+		copy(image[int(extents[i])*isoSectorSize:], file.Data)
+	}
+
+	isoWriteDirectory(image[primaryRoot*isoSectorSize:], primaryRoot, primaryRootSize, primaryRecords)
+	isoWriteDirectory(image[jolietRoot*isoSectorSize:], jolietRoot, jolietRootSize, jolietRecords)
+
+	isoWritePathTables(sector(primaryLPath), sector(primaryMPath), primaryRoot)
+	isoWritePathTables(sector(jolietLPath), sector(jolietMPath), jolietRoot)
+
+	isoWriteDescriptor(sector(primaryDescriptor), isoDescriptor{
+		kind:     1,
+		label:    []byte(label),
+		space:    next,
+		lPath:    primaryLPath,
+		mPath:    primaryMPath,
+		root:     primaryRoot,
+		rootSize: primaryRootSize,
+		padding:  []byte{' '},
+	})
+	isoWriteDescriptor(sector(jolietDescriptor), isoDescriptor{
+		kind:       2,
+		label:      isoJolietName(label),
+		space:      next,
+		lPath:      jolietLPath,
+		mPath:      jolietMPath,
+		root:       uint32(jolietRoot),
+		rootSize:   jolietRootSize,
+		padding:    []byte{0, ' '},
+		escapeCode: []byte("%/E"),
+	})
+
+	end := sector(terminator)
+	end[0] = 255
+	copy(end[1:], "CD001")
+	end[6] = 1
+
+	return image, nil
+}
+
+type isoRecord struct {
+	name   []byte
+	extent uint32
+	size   uint32
+	dir    bool
+}
+
+type isoDescriptor struct {
+	kind       byte
+	label      []byte
+	space      uint32
+	lPath      uint32
+	mPath      uint32
+	root       uint32
+	rootSize   int
+	padding    []byte
+	escapeCode []byte
+}
+
+// isoPrimaryName
+// Name in the primary directory, limited to upper-case letters, digits and underscores
+func isoPrimaryName(name string) string {
+	base, ext, _ := strings.Cut(strings.ToUpper(name), ".")
+
+	clean := func(s string, max int) string {
+		s = strings.Map(func(r rune) rune {
+			if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, s)
+
+		return s[:min(len(s), max)]
+	}
+
+	return clean(base, 30-min(len(ext), 3)) + "." + clean(ext, 3) + ";1"
+}
+
+// isoJolietName
+// UCS-2 big-endian representation of a name
+func isoJolietName(name string) []byte {
+	units := utf16.Encode([]rune(name))
+
+	out := make([]byte, 2*len(units))
+	for i, unit := range units {
+		binary.BigEndian.PutUint16(out[2*i:], unit)
+	}
+
+	return out
+}
+
+func isoRecordLength(name []byte) int {
+	length := 33 + len(name)
+	if length%2 == 1 {
+		length++
+	}
+
+	return length
+}
+
+// isoDirectorySize
+// Size of a directory with `.` and `..` entries, rounded up to sectors. Records never cross sectors
+func isoDirectorySize(names [][]byte) int {
+	size, used := isoSectorSize, 2*isoRecordLength([]byte{0})
+
+	for _, name := range names {
+		length := isoRecordLength(name)
+		if used+length > isoSectorSize {
+			size += isoSectorSize
+			used = 0
+		}
+
+		used += length
+	}
+
+	return size
+}
+
+func isoWriteDirectory(buf []byte, extent int, size int, records []isoRecord) {
+	// NOTE: records must be sorted by name
+	slices.SortFunc(records, func(a, b isoRecord) int {
+		return bytes.Compare(a.name, b.name)
+	})
+
+	self := isoRecord{name: []byte{0}, extent: uint32(extent), size: uint32(size), dir: true}
+	parent := isoRecord{name: []byte{1}, extent: uint32(extent), size: uint32(size), dir: true}
+
+	offset := 0
+	for _, record := range append([]isoRecord{self, parent}, records...) {
+		length := isoRecordLength(record.name)
+		if offset%isoSectorSize+length > isoSectorSize {
+			offset += isoSectorSize - offset%isoSectorSize
+		}
+
+		isoWriteRecord(buf[offset:offset+length], record)
+		offset += length
+	}
+}
+
+func isoWriteRecord(buf []byte, record isoRecord) {
+	buf[0] = byte(len(buf))
+	isoBothUint32(buf[2:], record.extent)
+	isoBothUint32(buf[10:], record.size)
+	isoWriteRecordDate(buf[18:])
+
+	if record.dir {
+		buf[25] = 2
+	}
+
+	isoBothUint16(buf[28:], 1)
+	buf[32] = byte(len(record.name))
+	copy(buf[33:], record.name)
+}
+
+func isoWritePathTables(l []byte, m []byte, root int) {
+	// NOTE: the only entry is root, which is its own parent
+	l[0], m[0] = 1, 1
+
+	binary.LittleEndian.PutUint32(l[2:], uint32(root))
+	binary.LittleEndian.PutUint16(l[6:], 1)
+	binary.BigEndian.PutUint32(m[2:], uint32(root))
+	binary.BigEndian.PutUint16(m[6:], 1)
+}
+
+func isoWriteDescriptor(buf []byte, descriptor isoDescriptor) {
+	buf[0] = descriptor.kind
+	copy(buf[1:], "CD001")
+	buf[6] = 1
+
+	isoFill(buf[8:40], nil, descriptor.padding)
+	isoFill(buf[40:72], descriptor.label, descriptor.padding)
+	isoBothUint32(buf[80:], descriptor.space)
+	copy(buf[88:], descriptor.escapeCode)
+	isoBothUint16(buf[120:], 1)
+	isoBothUint16(buf[124:], 1)
+	isoBothUint16(buf[128:], isoSectorSize)
+	isoBothUint32(buf[132:], 10)
+	binary.LittleEndian.PutUint32(buf[140:], descriptor.lPath)
+	binary.BigEndian.PutUint32(buf[148:], descriptor.mPath)
+
+	isoWriteRecord(buf[156:190], isoRecord{name: []byte{0}, extent: descriptor.root, size: uint32(descriptor.rootSize), dir: true})
+
+	// NOTE: volume set, publisher, preparer, application, copyright, abstract and bibliographic IDs
+	for _, field := range [][2]int{{190, 318}, {318, 446}, {446, 574}, {574, 702}, {702, 739}, {739, 776}, {776, 813}} {
+		isoFill(buf[field[0]:field[1]], nil, descriptor.padding)
+	}
+
+	// NOTE: creation, modification, expiration and effective dates are "not specified"
+	for offset := 813; offset < 881; offset += 17 {
+		copy(buf[offset:], "0000000000000000")
+	}
+
+	buf[881] = 1
+}
+
+// isoWriteRecordDate
+// Fixed recording date, 1970-01-01 00:00:00 UTC
+func isoWriteRecordDate(buf []byte) {
+	buf[0] = 70
+	buf[1] = 1
+	buf[2] = 1
+}
+
+func isoFill(buf []byte, value []byte, padding []byte) {
+	n := copy(buf, value)
+	for i := n; i < len(buf); i++ {
+		buf[i] = padding[(i-n)%len(padding)]
+	}
+}
+
+func isoBothUint16(buf []byte, value uint16) {
+	binary.LittleEndian.PutUint16(buf[0:], value)
+	binary.BigEndian.PutUint16(buf[2:], value)
+}
+
+func isoBothUint32(buf []byte, value uint32) {
+	binary.LittleEndian.PutUint32(buf[0:], value)
+	binary.BigEndian.PutUint32(buf[4:], value)
+}
@@ -0,0 +1,769 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+type jinjaFilterFunc func(value any, positional []any, keywords map[string]any) (any, error)
+
+type jinjaTestFunc func(value any, positional []any) (bool, error)
+
+// jinjaFilters
+// Built-in filters of Jinja, populated in `init` as some of them apply other filters
+var jinjaFilters map[string]jinjaFilterFunc
+
+func init() {
+	jinjaFilters = map[string]jinjaFilterFunc{
+		"default":    jinjaFilterDefault,
+		"d":          jinjaFilterDefault,
+		"lower":      jinjaStringFilter(strings.ToLower),
+		"upper":      jinjaStringFilter(strings.ToUpper),
+		"title":      jinjaStringFilter(jinjaTitle),
+		"capitalize": jinjaStringFilter(jinjaCapitalize),
+		"trim":       jinjaFilterTrim,
+		"replace":    jinjaFilterReplace,
+		"format":     jinjaFilterFormat,
+		"join":       jinjaFilterJoin,
+		"length":     jinjaFilterLength,
+		"count":      jinjaFilterLength,
+		"first":      jinjaFilterFirst,
+		"last":       jinjaFilterLast,
+		"int":        jinjaFilterInt,
+		"float":      jinjaFilterFloat,
+		"string":     func(value any, _ []any, _ map[string]any) (any, error) { return jinjaString(value), nil },
+		"list":       func(value any, _ []any, _ map[string]any) (any, error) { return jinjaIterate(value) },
+		"sort":       jinjaFilterSort,
+		"unique":     jinjaFilterUnique,
+		"reverse":    jinjaFilterReverse,
+		"abs":        jinjaFilterAbs,
+		"round":      jinjaFilterRound,
+		"min":        jinjaExtremeFilter(-1),
+		"max":        jinjaExtremeFilter(1),
+		"sum":        jinjaFilterSum,
+		"items":      jinjaFilterItems,
+		"tojson":     jinjaFilterToJSON,
+		"indent":     jinjaFilterIndent,
+		"map":        jinjaFilterMap,
+		"select":     jinjaSelectFilter(false, false),
+		"reject":     jinjaSelectFilter(true, false),
+		"selectattr": jinjaSelectFilter(false, true),
+		"rejectattr": jinjaSelectFilter(true, true),
+	}
+}
+
+// jinjaArgument
+// Argument of a filter passed by position or by name
+func jinjaArgument(positional []any, keywords map[string]any, i int, name string, fallback any) any {
+	if i < len(positional) {
+		return positional[i]
+	}
+
+	if value, ok := keywords[name]; ok {
+		return value
+	}
+
+	return fallback
+}
+
+func jinjaStringFilter(transform func(string) string) jinjaFilterFunc {
+	return func(value any, _ []any, _ map[string]any) (any, error) {
+		return transform(jinjaString(value)), nil
+	}
+}
+
+// jinjaTitle
+// Same as `title()` in Python, words start after any non-letter
+func jinjaTitle(value string) string {
+	var out strings.Builder
+
+	previous := false
+	for _, r := range value {
+		if previous {
+			out.WriteRune(unicode.ToLower(r))
+		} else {
+			out.WriteRune(unicode.ToUpper(r))
+		}
+		previous = unicode.IsLetter(r)
+	}
+
+	return out.String()
+}
+
+func jinjaCapitalize(value string) string {
+	for i, r := range value {
+		return string(unicode.ToUpper(r)) + strings.ToLower(value[i+len(string(r)):])
+	}
+
+	return value
+}
+
+func jinjaFilterDefault(value any, positional []any, keywords map[string]any) (any, error) {
+	fallback := jinjaArgument(positional, keywords, 0, "default_value", "")
+	boolean := jinjaTruthy(jinjaArgument(positional, keywords, 1, "boolean", false))
+
+	if _, ok := value.(jinjaUndefined); ok || (boolean && !jinjaTruthy(value)) {
+		return fallback, nil
+	}
+
+	return value, nil
+}
+
+func jinjaFilterTrim(value any, positional []any, keywords map[string]any) (any, error) {
+	chars := jinjaArgument(positional, keywords, 0, "chars", nil)
+	if chars == nil {
+		return strings.TrimSpace(jinjaString(value)), nil
+	}
+
+	return strings.Trim(jinjaString(value), jinjaString(chars)), nil
+}
+
+// jinjaFilterFormat
+// Applies `%` formatting, keyword arguments are passed as a mapping, e.g. `'%(a)s' | format(a=1)`
+func jinjaFilterFormat(value any, positional []any, keywords map[string]any) (any, error) {
+	if len(positional) > 0 && len(keywords) > 0 {
+		return nil, fmt.Errorf("can't handle positional and keyword arguments at the same time")
+	}
+
+	if len(keywords) > 0 {
+		positional = []any{keywords}
+	}
+
+	return jinjaFormat(jinjaString(value), positional)
+}
+
+func jinjaFilterReplace(value any, positional []any, keywords map[string]any) (any, error) {
+	if len(positional) < 2 {
+		return nil, fmt.Errorf("expects old and new strings")
+	}
+
+	count, err := jinjaToInt(jinjaArgument(positional, keywords, 2, "count", int64(-1)))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Replace(jinjaString(value), jinjaString(positional[0]), jinjaString(positional[1]), int(count)), nil
+}
+
+func jinjaFilterJoin(value any, positional []any, keywords map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute := jinjaArgument(positional, keywords, 1, "attribute", nil)
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if attribute != nil {
+			if item, err = jinjaGetItemValue(item, attribute); err != nil {
+				return nil, err
+			}
+		}
+		parts = append(parts, jinjaString(item))
+	}
+
+	return strings.Join(parts, jinjaString(jinjaArgument(positional, keywords, 0, "d", ""))), nil
+}
+
+func jinjaFilterLength(value any, _ []any, _ map[string]any) (any, error) {
+	length, err := jinjaLength(value)
+	return int64(length), err
+}
+
+func jinjaFilterFirst(value any, _ []any, _ map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil || len(items) == 0 {
+		return jinjaUndefined{name: "first"}, err
+	}
+
+	return items[0], nil
+}
+
+func jinjaFilterLast(value any, _ []any, _ map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil || len(items) == 0 {
+		return jinjaUndefined{name: "last"}, err
+	}
+
+	return items[len(items)-1], nil
+}
+
+func jinjaFilterInt(value any, positional []any, keywords map[string]any) (any, error) {
+	fallback := jinjaArgument(positional, keywords, 0, "default", int64(0))
+
+	base, err := jinjaToInt(jinjaArgument(positional, keywords, 1, "base", int64(10)))
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := value.(type) {
+	case int64:
+		return value, nil
+	case bool:
+		return jinjaToInt(value)
+	case float64:
+		return int64(value), nil
+	case string:
+		text := strings.ReplaceAll(strings.TrimSpace(value), "_", "")
+		if number, err := strconv.ParseInt(text, int(base), 64); err == nil {
+			return number, nil
+		}
+		// NOTE: Jinja falls back to parsing floats, e.g. "4.2" is 4
+		if number, err := strconv.ParseFloat(text, 64); err == nil && base == 10 {
+			return int64(number), nil
+		}
+	}
+
+	return fallback, nil
+}
+
+func jinjaFilterFloat(value any, positional []any, keywords map[string]any) (any, error) {
+	fallback := jinjaArgument(positional, keywords, 0, "default", 0.0)
+
+	switch value := value.(type) {
+	case int64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case bool:
+		number, _, _ := jinjaNumber(value)
+		return number, nil
+	case string:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return number, nil
+		}
+	}
+
+	return fallback, nil
+}
+
+// jinjaSortKey
+// Strings are compared case-insensitively by default, same as in Jinja
+func jinjaSortKey(value any, caseSensitive bool) any {
+	if text, ok := value.(string); ok && !caseSensitive {
+		return strings.ToLower(text)
+	}
+
+	return value
+}
+
+func jinjaFilterSort(value any, positional []any, keywords map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	reverse := jinjaTruthy(jinjaArgument(positional, keywords, 0, "reverse", false))
+	caseSensitive := jinjaTruthy(jinjaArgument(positional, keywords, 1, "case_sensitive", false))
+	attribute := jinjaArgument(positional, keywords, 2, "attribute", nil)
+
+	keys := make([]any, len(items))
+	for i, item := range items {
+		key := item
+		if attribute != nil {
+			if key, err = jinjaGetItemValue(item, attribute); err != nil {
+				return nil, err
+			}
+		}
+		keys[i] = jinjaSortKey(key, caseSensitive)
+	}
+
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	var sortErr error
+	sort.SliceStable(indexes, func(a, b int) bool {
+		order, err := jinjaCompareOrder(keys[indexes[a]], keys[indexes[b]])
+		if err != nil {
+			sortErr = err
+		}
+		if reverse {
+			return order > 0
+		}
+		return order < 0
+	})
+
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	sorted := make([]any, len(items))
+	for i, index := range indexes {
+		sorted[i] = items[index]
+	}
+
+	return sorted, nil
+}
+
+func jinjaFilterUnique(value any, positional []any, keywords map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	caseSensitive := jinjaTruthy(jinjaArgument(positional, keywords, 0, "case_sensitive", false))
+
+	unique := []any{}
+	seen := []any{}
+
+	for _, item := range items {
+		key := jinjaSortKey(item, caseSensitive)
+
+		duplicate := false
+		for _, other := range seen {
+			if jinjaEqual(key, other) {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			seen = append(seen, key)
+			unique = append(unique, item)
+		}
+	}
+
+	return unique, nil
+}
+
+func jinjaFilterReverse(value any, _ []any, _ map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	reversed := make([]any, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+
+	if _, ok := value.(string); ok {
+		return jinjaFilterJoin(reversed, nil, nil)
+	}
+
+	return reversed, nil
+}
+
+func jinjaFilterAbs(value any, _ []any, _ map[string]any) (any, error) {
+	switch value := value.(type) {
+	case int64:
+		if value < 0 {
+			return -value, nil
+		}
+		return value, nil
+	case float64:
+		return math.Abs(value), nil
+	}
+
+	return nil, fmt.Errorf("bad operand type for abs(): '%s'", jinjaTypeName(value))
+}
+
+func jinjaFilterRound(value any, positional []any, keywords map[string]any) (any, error) {
+	number, _, ok := jinjaNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("expects a number, got '%s'", jinjaTypeName(value))
+	}
+
+	precision, err := jinjaToInt(jinjaArgument(positional, keywords, 0, "precision", int64(0)))
+	if err != nil {
+		return nil, err
+	}
+
+	scale := math.Pow(10, float64(precision))
+
+	switch method := jinjaString(jinjaArgument(positional, keywords, 1, "method", "common")); method {
+	case "common":
+		return math.RoundToEven(number*scale) / scale, nil
+	case "ceil":
+		return math.Ceil(number*scale) / scale, nil
+	case "floor":
+		return math.Floor(number*scale) / scale, nil
+	default:
+		return nil, fmt.Errorf("method must be 'common', 'ceil' or 'floor', got '%s'", method)
+	}
+}
+
+func jinjaExtremeFilter(sign int) jinjaFilterFunc {
+	return func(value any, positional []any, keywords map[string]any) (any, error) {
+		items, err := jinjaIterate(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) == 0 {
+			return jinjaUndefined{name: "no items"}, nil
+		}
+
+		caseSensitive := jinjaTruthy(jinjaArgument(positional, keywords, 0, "case_sensitive", false))
+
+		extreme := items[0]
+		for _, item := range items[1:] {
+			order, err := jinjaCompareOrder(jinjaSortKey(item, caseSensitive), jinjaSortKey(extreme, caseSensitive))
+			if err != nil {
+				return nil, err
+			}
+			if order*sign > 0 {
+				extreme = item
+			}
+		}
+
+		return extreme, nil
+	}
+}
+
+func jinjaFilterSum(value any, positional []any, keywords map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute := jinjaArgument(positional, keywords, 0, "attribute", nil)
+	total := jinjaArgument(positional, keywords, 1, "start", int64(0))
+
+	for _, item := range items {
+		if attribute != nil {
+			if item, err = jinjaGetItemValue(item, attribute); err != nil {
+				return nil, err
+			}
+		}
+		if total, err = jinjaArithmetic("+", total, item); err != nil {
+			return nil, err
+		}
+	}
+
+	return total, nil
+}
+
+func jinjaFilterItems(value any, _ []any, _ map[string]any) (any, error) {
+	switch value := value.(type) {
+	case jinjaUndefined:
+		return []any{}, nil
+	case map[string]any:
+		return jinjaDictMethod(value, "items")(nil, nil)
+	}
+
+	return nil, fmt.Errorf("can only get item pairs from a mapping, got '%s'", jinjaTypeName(value))
+}
+
+// jinjaFilterToJSON
+// Same as `json.dumps` in Python with sorted keys, HTML characters are escaped by Jinja
+func jinjaFilterToJSON(value any, _ []any, _ map[string]any) (any, error) {
+	var out strings.Builder
+
+	if err := jinjaWriteJSON(&out, value); err != nil {
+		return nil, err
+	}
+
+	return out.String(), nil
+}
+
+func jinjaWriteJSON(out *strings.Builder, value any) error {
+	switch value := value.(type) {
+	case nil:
+		out.WriteString("null")
+	case bool:
+		out.WriteString(strconv.FormatBool(value))
+	case int64:
+		out.WriteString(strconv.FormatInt(value, 10))
+	case float64:
+		switch {
+		case math.IsNaN(value):
+			out.WriteString("NaN")
+		case math.IsInf(value, 1):
+			out.WriteString("Infinity")
+		case math.IsInf(value, -1):
+			out.WriteString("-Infinity")
+		default:
+			out.WriteString(jinjaFloatString(value))
+		}
+	case string:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		// NOTE: non-ASCII characters are escaped, same as `ensure_ascii` of Python
+		for _, r := range string(encoded) {
+			switch {
+			case r == '\'':
+				out.WriteString(`\u0027`)
+			case r > 0xffff:
+				high, low := utf16.EncodeRune(r)
+				fmt.Fprintf(out, `\u%04x\u%04x`, high, low)
+			case r > 0x7f:
+				fmt.Fprintf(out, `\u%04x`, r)
+			default:
+				out.WriteRune(r)
+			}
+		}
+	case []any:
+		out.WriteString("[")
+		for i, item := range value {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			if err := jinjaWriteJSON(out, item); err != nil {
+				return err
+			}
+		}
+		out.WriteString("]")
+	case map[string]any:
+		out.WriteString("{")
+		for i, key := range jinjaKeys(value) {
+			if i > 0 {
+				out.WriteString(", ")
+			}
+			if err := jinjaWriteJSON(out, key); err != nil {
+				return err
+			}
+			out.WriteString(": ")
+			if err := jinjaWriteJSON(out, value[key]); err != nil {
+				return err
+			}
+		}
+		out.WriteString("}")
+	case jinjaUndefined:
+		return fmt.Errorf("'%s' is undefined", value.name)
+	default:
+		return fmt.Errorf("object of type '%s' is not JSON serializable", jinjaTypeName(value))
+	}
+
+	return nil
+}
+
+// jinjaFilterIndent
+// Indents every line but the first one, blank lines are kept as they are
+func jinjaFilterIndent(value any, positional []any, keywords map[string]any) (any, error) {
+	width := jinjaArgument(positional, keywords, 0, "width", int64(4))
+	first := jinjaTruthy(jinjaArgument(positional, keywords, 1, "first", false))
+	blank := jinjaTruthy(jinjaArgument(positional, keywords, 2, "blank", false))
+
+	indent := jinjaString(width)
+	if count, err := jinjaToInt(width); err == nil {
+		indent = strings.Repeat(" ", int(max(count, 0)))
+	}
+
+	lines := strings.Split(jinjaString(value), "\n")
+	for i, line := range lines {
+		if (i == 0 && !first) || (line == "" && !blank) {
+			continue
+		}
+		lines[i] = indent + line
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// jinjaFilterMap
+// Either `map(attribute='name')` or `map('filter', arguments...)`
+func jinjaFilterMap(value any, positional []any, keywords map[string]any) (any, error) {
+	items, err := jinjaIterate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make([]any, 0, len(items))
+
+	if attribute, ok := keywords["attribute"]; ok {
+		for _, item := range items {
+			result, err := jinjaGetItemValue(item, attribute)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := result.(jinjaUndefined); ok {
+				if fallback, ok := keywords["default"]; ok {
+					result = fallback
+				}
+			}
+
+			mapped = append(mapped, result)
+		}
+
+		return mapped, nil
+	}
+
+	if len(positional) == 0 {
+		return nil, fmt.Errorf("expects a filter name or an attribute")
+	}
+
+	name := jinjaString(positional[0])
+
+	filter, ok := jinjaFilters[name]
+	if !ok {
+		return nil, fmt.Errorf("no filter named '%s'", name)
+	}
+
+	for _, item := range items {
+		result, err := filter(item, positional[1:], keywords)
+		if err != nil {
+			return nil, err
+		}
+		mapped = append(mapped, result)
+	}
+
+	return mapped, nil
+}
+
+// jinjaSelectFilter
+// `select`, `reject` and their `attr` variants, without a test items are checked for truthiness
+func jinjaSelectFilter(reject bool, byAttribute bool) jinjaFilterFunc {
+	return func(value any, positional []any, _ map[string]any) (any, error) {
+		items, err := jinjaIterate(value)
+		if err != nil {
+			return nil, err
+		}
+
+		var attribute any
+		if byAttribute {
+			if len(positional) == 0 {
+				return nil, fmt.Errorf("expects an attribute")
+			}
+			attribute, positional = positional[0], positional[1:]
+		}
+
+		test := func(value any, _ []any) (bool, error) { return jinjaTruthy(value), nil }
+
+		if len(positional) > 0 {
+			name := jinjaString(positional[0])
+
+			var ok bool
+			if test, ok = jinjaTests[name]; !ok {
+				return nil, fmt.Errorf("no test named '%s'", name)
+			}
+			positional = positional[1:]
+		}
+
+		selected := []any{}
+
+		for _, item := range items {
+			subject := item
+			if attribute != nil {
+				if subject, err = jinjaGetItemValue(item, attribute); err != nil {
+					return nil, err
+				}
+			}
+
+			result, err := test(subject, positional)
+			if err != nil {
+				return nil, err
+			}
+
+			if result != reject {
+				selected = append(selected, item)
+			}
+		}
+
+		return selected, nil
+	}
+}
+
+func jinjaTypeTest(check func(value any) bool) jinjaTestFunc {
+	return func(value any, _ []any) (bool, error) {
+		return check(value), nil
+	}
+}
+
+func jinjaComparisonTest(operator string) jinjaTestFunc {
+	return func(value any, positional []any) (bool, error) {
+		if len(positional) != 1 {
+			return false, fmt.Errorf("expects a single argument")
+		}
+
+		return jinjaComparison(operator, value, positional[0])
+	}
+}
+
+// jinjaTests
+// Built-in tests of Jinja, used by `is` and by `select` filters
+var jinjaTests = map[string]jinjaTestFunc{
+	"defined": jinjaTypeTest(func(value any) bool {
+		_, ok := value.(jinjaUndefined)
+		return !ok
+	}),
+	"undefined": jinjaTypeTest(func(value any) bool {
+		_, ok := value.(jinjaUndefined)
+		return ok
+	}),
+	"none":    jinjaTypeTest(func(value any) bool { return value == nil }),
+	"boolean": jinjaTypeTest(func(value any) bool { _, ok := value.(bool); return ok }),
+	"true":    jinjaTypeTest(func(value any) bool { return value == true }),
+	"false":   jinjaTypeTest(func(value any) bool { return value == false }),
+	"string":  jinjaTypeTest(func(value any) bool { _, ok := value.(string); return ok }),
+	"number": jinjaTypeTest(func(value any) bool {
+		_, _, ok := jinjaNumber(value)
+		return ok
+	}),
+	"integer": jinjaTypeTest(func(value any) bool { _, ok := value.(int64); return ok }),
+	"float":   jinjaTypeTest(func(value any) bool { _, ok := value.(float64); return ok }),
+	"mapping": jinjaTypeTest(func(value any) bool { _, ok := value.(map[string]any); return ok }),
+	"sequence": jinjaTypeTest(func(value any) bool {
+		switch value.(type) {
+		case string, []any, map[string]any:
+			return true
+		}
+		return false
+	}),
+	"iterable": jinjaTypeTest(func(value any) bool {
+		_, err := jinjaIterate(value)
+		_, undefined := value.(jinjaUndefined)
+		return err == nil && !undefined
+	}),
+	"lower": jinjaTypeTest(func(value any) bool {
+		text, ok := value.(string)
+		return ok && text == strings.ToLower(text)
+	}),
+	"upper": jinjaTypeTest(func(value any) bool {
+		text, ok := value.(string)
+		return ok && text == strings.ToUpper(text)
+	}),
+	"even": func(value any, _ []any) (bool, error) {
+		number, err := jinjaToInt(value)
+		return err == nil && number%2 == 0, err
+	},
+	"odd": func(value any, _ []any) (bool, error) {
+		number, err := jinjaToInt(value)
+		return err == nil && number%2 != 0, err
+	},
+	"divisibleby": func(value any, positional []any) (bool, error) {
+		if len(positional) != 1 {
+			return false, fmt.Errorf("expects a single argument")
+		}
+
+		remainder, err := jinjaArithmetic("%", value, positional[0])
+		if err != nil {
+			return false, err
+		}
+
+		return jinjaEqual(remainder, int64(0)), nil
+	},
+	"in": func(value any, positional []any) (bool, error) {
+		if len(positional) != 1 {
+			return false, fmt.Errorf("expects a single argument")
+		}
+
+		return jinjaContains(positional[0], value)
+	},
+	"eq":      jinjaComparisonTest("=="),
+	"equalto": jinjaComparisonTest("=="),
+	"==":      jinjaComparisonTest("=="),
+	"ne":      jinjaComparisonTest("!="),
+	"!=":      jinjaComparisonTest("!="),
+	"lt":      jinjaComparisonTest("<"),
+	"<":       jinjaComparisonTest("<"),
+	"le":      jinjaComparisonTest("<="),
+	"<=":      jinjaComparisonTest("<="),
+	"gt":      jinjaComparisonTest(">"),
+	">":       jinjaComparisonTest(">"),
+	"ge":      jinjaComparisonTest(">="),
+	">=":      jinjaComparisonTest(">="),
+}
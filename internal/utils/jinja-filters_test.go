@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestJinjaFilters(t *testing.T) {
+	variables := map[string]any{
+		"users": []any{
+			map[string]any{"name": "alice", "admin": true},
+			map[string]any{"name": "bob", "admin": false},
+		},
+		"tags": map[string]any{"b": int64(2), "a": int64(1)},
+	}
+
+	testJinja(t, []jinjaTestCase{
+		{name: "default", template: "{{ missing | default('x') }} {{ '' | d('y', true) }} {{ 0 | default(1) }}", expected: "x y 0"},
+		{name: "case", template: "{{ 'aB' | lower }} {{ 'aB' | upper }} {{ 'hello world' | title }} {{ 'hELLO' | capitalize }}", expected: "ab AB Hello World Hello"},
+		{name: "trim", template: "[{{ '  a  ' | trim }}] [{{ '--a--' | trim('-') }}]", expected: "[a] [a]"},
+		{name: "replace", template: "{{ 'a.b.c' | replace('.', '-') }} {{ 'a.b.c' | replace('.', '-', 1) }}", expected: "a-b-c a-b.c"},
+		{name: "format", template: "{{ '%s-%03d' | format('web', 7) }}", expected: "web-007"},
+		{name: "format keywords", template: "{{ '%(name)s!' | format(name='web') }}", expected: "web!"},
+		{name: "join", template: "{{ [1, 2, 3] | join(',') }} {{ users | join('/', attribute='name') }}", variables: variables, expected: "1,2,3 alice/bob"},
+		{name: "length", template: "{{ [1, 2] | length }} {{ 'abc' | count }} {{ tags | length }}", variables: variables, expected: "2 3 2"},
+		{name: "first and last", template: "{{ [1, 2, 3] | first }} {{ 'abc' | last }}", expected: "1 c"},
+		{name: "int", template: "{{ '42' | int }} {{ '4.2' | int }} {{ 'x' | int(7) }} {{ 'ff' | int(base=16) }}", expected: "42 4 7 255"},
+		{name: "float", template: "{{ '1.5' | float }} {{ 2 | float }}", expected: "1.5 2.0"},
+		{name: "string and list", template: "{{ 1 | string ~ 2 }} {{ 'ab' | list }}", expected: "12 ['a', 'b']"},
+		{name: "sort", template: "{{ [3, 1, 2] | sort }} {{ [3, 1, 2] | sort(reverse=true) }}", expected: "[1, 2, 3] [3, 2, 1]"},
+		{name: "unique and reverse", template: "{{ [1, 2, 1] | unique }} {{ [1, 2, 3] | reverse }}", expected: "[1, 2] [3, 2, 1]"},
+		{name: "numbers", template: "{{ -3 | abs }} {{ 2.567 | round(1) }} {{ [3, 1, 2] | min }} {{ [3, 1, 2] | max }} {{ [1, 2, 3] | sum }}", expected: "3 2.6 1 3 6"},
+		{name: "items", template: "{% for key, value in tags | items %}{{ key }}={{ value }};{% endfor %}", variables: variables, expected: "a=1;b=2;"},
+		{name: "tojson", template: "{{ {'a': [1, 'x']} | tojson }}", expected: `{"a": [1, "x"]}`},
+		{name: "indent", template: "{{ 'a\nb\n\nc' | indent(2) }}", expected: "a\n  b\n\n  c"},
+		{name: "map", template: "{{ users | map(attribute='name') | join(',') }} {{ ['a', 'b'] | map('upper') | list }}", variables: variables, expected: "alice,bob ['A', 'B']"},
+		{name: "select and reject", template: "{{ [1, 2, 3, 4] | select('even') | list }} {{ [1, 2, 3, 4] | reject('even') | list }}", expected: "[2, 4] [1, 3]"},
+		{name: "selectattr", template: "{{ users | selectattr('admin') | map(attribute='name') | first }} {{ users | rejectattr('admin') | map(attribute='name') | first }}", variables: variables, expected: "alice bob"},
+	})
+}
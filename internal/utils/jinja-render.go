@@ -0,0 +1,1579 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JinjaUndefinedPrefix
+// cloud-init renders undefined variables as this prefix followed by their name
+const JinjaUndefinedPrefix = "CI_MISSING_JINJA_VAR/"
+
+// jinjaUndefined
+// Missing variable, attribute or item, it renders as a placeholder and is falsy
+type jinjaUndefined struct {
+	name string
+}
+
+// jinjaMethod
+// Bound method of a value, e.g. `items` of a dict
+type jinjaMethod func(positional []any, keywords map[string]any) (any, error)
+
+type jinjaRenderer struct {
+	scopes []map[string]any
+}
+
+var jinjaGlobals = map[string]jinjaMethod{
+	"range": func(positional []any, _ map[string]any) (any, error) {
+		numbers := []int64{}
+		for _, argument := range positional {
+			number, ok := argument.(int64)
+			if !ok {
+				return nil, fmt.Errorf("range() expects integers, got '%s'", jinjaTypeName(argument))
+			}
+			numbers = append(numbers, number)
+		}
+
+		start, stop, step := int64(0), int64(0), int64(1)
+		switch len(numbers) {
+		case 1:
+			stop = numbers[0]
+		case 2:
+			start, stop = numbers[0], numbers[1]
+		case 3:
+			start, stop, step = numbers[0], numbers[1], numbers[2]
+		default:
+			return nil, fmt.Errorf("range() expects 1 to 3 arguments, got %d", len(numbers))
+		}
+
+		if step == 0 {
+			return nil, fmt.Errorf("range() step must not be zero")
+		}
+
+		items := []any{}
+		for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+			items = append(items, i)
+		}
+
+		return items, nil
+	},
+	"namespace": func(_ []any, keywords map[string]any) (any, error) {
+		namespace := map[string]any{}
+		for key, value := range keywords {
+			namespace[key] = value
+		}
+
+		return namespace, nil
+	},
+	"dict": func(_ []any, keywords map[string]any) (any, error) {
+		dict := map[string]any{}
+		for key, value := range keywords {
+			dict[key] = value
+		}
+
+		return dict, nil
+	},
+}
+
+func (r *jinjaRenderer) lookup(name string) any {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if value, ok := r.scopes[i][name]; ok {
+			return value
+		}
+	}
+
+	if global, ok := jinjaGlobals[name]; ok {
+		return global
+	}
+
+	return jinjaUndefined{name: name}
+}
+
+func (r *jinjaRenderer) render(out *strings.Builder, nodes []jinjaNode) error {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *jinjaTextNode:
+			out.WriteString(node.text)
+		case *jinjaOutputNode:
+			value, err := r.evaluate(node.expression)
+			if err != nil {
+				return err
+			}
+			out.WriteString(jinjaString(value))
+		case *jinjaIfNode:
+			matched := false
+			for _, branch := range node.branches {
+				condition, err := r.evaluate(branch.condition)
+				if err != nil {
+					return err
+				}
+
+				if jinjaTruthy(condition) {
+					matched = true
+					if err := r.render(out, branch.body); err != nil {
+						return err
+					}
+					break
+				}
+			}
+
+			if !matched {
+				if err := r.render(out, node.orElse); err != nil {
+					return err
+				}
+			}
+		case *jinjaForNode:
+			if err := r.renderFor(out, node); err != nil {
+				return err
+			}
+		case *jinjaSetNode:
+			var value any
+
+			if node.value != nil {
+				var err error
+				if value, err = r.evaluate(node.value); err != nil {
+					return err
+				}
+			} else {
+				var body strings.Builder
+				if err := r.render(&body, node.body); err != nil {
+					return err
+				}
+				value = body.String()
+			}
+
+			if err := r.assign(node.targets, value); err != nil {
+				return err
+			}
+		case *jinjaDoNode:
+			if _, err := r.evaluate(node.expression); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *jinjaRenderer) renderFor(out *strings.Builder, node *jinjaForNode) error {
+	iterable, err := r.evaluate(node.iterable)
+	if err != nil {
+		return err
+	}
+
+	items, err := jinjaIterate(iterable)
+	if err != nil {
+		return err
+	}
+
+	bind := func(scope map[string]any, item any) error {
+		if len(node.targets) == 1 {
+			scope[node.targets[0]] = item
+			return nil
+		}
+
+		values, ok := item.([]any)
+		if !ok || len(values) != len(node.targets) {
+			return fmt.Errorf("cannot unpack '%s' into %d loop variables", jinjaTypeName(item), len(node.targets))
+		}
+
+		for i, target := range node.targets {
+			scope[target] = values[i]
+		}
+
+		return nil
+	}
+
+	// NOTE: the filter is applied before `loop` is known, same as in Jinja
+	if node.condition != nil {
+		filtered := []any{}
+
+		for _, item := range items {
+			scope := map[string]any{}
+			if err := bind(scope, item); err != nil {
+				return err
+			}
+
+			r.scopes = append(r.scopes, scope)
+			condition, err := r.evaluate(node.condition)
+			r.scopes = r.scopes[:len(r.scopes)-1]
+
+			if err != nil {
+				return err
+			}
+
+			if jinjaTruthy(condition) {
+				filtered = append(filtered, item)
+			}
+		}
+
+		items = filtered
+	}
+
+	if len(items) == 0 {
+		return r.render(out, node.orElse)
+	}
+
+	for i, item := range items {
+		scope := map[string]any{
+			"loop": map[string]any{
+				"index":     int64(i + 1),
+				"index0":    int64(i),
+				"revindex":  int64(len(items) - i),
+				"revindex0": int64(len(items) - i - 1),
+				"first":     i == 0,
+				"last":      i == len(items)-1,
+				"length":    int64(len(items)),
+			},
+		}
+
+		if err := bind(scope, item); err != nil {
+			return err
+		}
+
+		r.scopes = append(r.scopes, scope)
+		err := r.render(out, node.body)
+		r.scopes = r.scopes[:len(r.scopes)-1]
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assign
+// Sets variables in the innermost scope, so assignments in loops don't leak, unless they go to `namespace()`
+func (r *jinjaRenderer) assign(targets []jinjaExpression, value any) error {
+	values := []any{value}
+
+	if len(targets) > 1 {
+		items, ok := value.([]any)
+		if !ok || len(items) != len(targets) {
+			return fmt.Errorf("cannot unpack '%s' into %d variables", jinjaTypeName(value), len(targets))
+		}
+		values = items
+	}
+
+	for i, target := range targets {
+		switch target := target.(type) {
+		case *jinjaVariable:
+			r.scopes[len(r.scopes)-1][target.name] = values[i]
+		case *jinjaGetAttr:
+			object, err := r.evaluate(target.object)
+			if err != nil {
+				return err
+			}
+
+			namespace, ok := object.(map[string]any)
+			if !ok {
+				return fmt.Errorf("cannot assign attribute on '%s'", jinjaTypeName(object))
+			}
+
+			namespace[target.name] = values[i]
+		}
+	}
+
+	return nil
+}
+
+// mutateList
+// `append()` and `extend()` of lists. Lists are values in Go, so the result is stored back
+// where the list came from, e.g. a variable or a key of a dict
+func (r *jinjaRenderer) mutateList(attribute *jinjaGetAttr, arguments jinjaArguments) (bool, error) {
+	object, err := r.evaluate(attribute.object)
+	if err != nil {
+		return false, err
+	}
+
+	list, ok := object.([]any)
+	if !ok {
+		return false, nil
+	}
+
+	positional, _, err := r.evaluateArguments(arguments)
+	if err != nil {
+		return true, err
+	}
+
+	if len(positional) != 1 {
+		return true, fmt.Errorf("%s() takes exactly one argument (%d given)", attribute.name, len(positional))
+	}
+
+	list = slices.Clone(list)
+
+	if attribute.name == "append" {
+		list = append(list, positional[0])
+	} else {
+		items, err := jinjaIterate(positional[0])
+		if err != nil {
+			return true, err
+		}
+		list = append(list, items...)
+	}
+
+	return true, r.store(attribute.object, list)
+}
+
+// store
+// Replaces the value of a variable, an attribute or an item. Variables of the caller are shadowed, not modified
+func (r *jinjaRenderer) store(target jinjaExpression, value any) error {
+	switch target := target.(type) {
+	case *jinjaVariable:
+		for i := len(r.scopes) - 1; i > 0; i-- {
+			if _, ok := r.scopes[i][target.name]; ok {
+				r.scopes[i][target.name] = value
+				return nil
+			}
+		}
+		r.scopes[1][target.name] = value
+	case *jinjaGetAttr:
+		object, err := r.evaluate(target.object)
+		if err != nil {
+			return err
+		}
+		if dict, ok := object.(map[string]any); ok {
+			dict[target.name] = value
+		}
+	case *jinjaGetItem:
+		object, err := r.evaluate(target.object)
+		if err != nil {
+			return err
+		}
+		key, err := r.evaluate(target.key)
+		if err != nil {
+			return err
+		}
+		switch object := object.(type) {
+		case map[string]any:
+			object[jinjaString(key)] = value
+		case []any:
+			if index, ok := key.(int64); ok {
+				if index < 0 {
+					index += int64(len(object))
+				}
+				if index >= 0 && index < int64(len(object)) {
+					object[index] = value
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *jinjaRenderer) evaluateAll(expressions []jinjaExpression) ([]any, error) {
+	values := make([]any, 0, len(expressions))
+
+	for _, expression := range expressions {
+		value, err := r.evaluate(expression)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func (r *jinjaRenderer) evaluateArguments(arguments jinjaArguments) ([]any, map[string]any, error) {
+	positional, err := r.evaluateAll(arguments.positional)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keywords := map[string]any{}
+	for name, expression := range arguments.keywords {
+		if keywords[name], err = r.evaluate(expression); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return positional, keywords, nil
+}
+
+func (r *jinjaRenderer) evaluate(expression jinjaExpression) (any, error) {
+	switch node := expression.(type) {
+	case *jinjaLiteral:
+		return node.value, nil
+	case *jinjaVariable:
+		return r.lookup(node.name), nil
+	case *jinjaList:
+		return r.evaluateAll(node.items)
+	case *jinjaTuple:
+		// NOTE: tuples are lists once evaluated, only a literal tuple is spread by `%` formatting
+		return r.evaluateAll(node.items)
+	case *jinjaDict:
+		dict := map[string]any{}
+		for i := range node.keys {
+			key, err := r.evaluate(node.keys[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := r.evaluate(node.values[i])
+			if err != nil {
+				return nil, err
+			}
+			dict[jinjaString(key)] = value
+		}
+		return dict, nil
+	case *jinjaGetAttr:
+		object, err := r.evaluate(node.object)
+		if err != nil {
+			return nil, err
+		}
+		return jinjaGetAttribute(object, node.name)
+	case *jinjaGetItem:
+		object, err := r.evaluate(node.object)
+		if err != nil {
+			return nil, err
+		}
+		key, err := r.evaluate(node.key)
+		if err != nil {
+			return nil, err
+		}
+		return jinjaGetItemValue(object, key)
+	case *jinjaSlice:
+		return r.evaluateSlice(node)
+	case *jinjaCall:
+		if attribute, ok := node.function.(*jinjaGetAttr); ok && (attribute.name == "append" || attribute.name == "extend") {
+			if handled, err := r.mutateList(attribute, node.jinjaArguments); handled || err != nil {
+				return nil, err
+			}
+		}
+
+		function, err := r.evaluate(node.function)
+		if err != nil {
+			return nil, err
+		}
+
+		positional, keywords, err := r.evaluateArguments(node.jinjaArguments)
+		if err != nil {
+			return nil, err
+		}
+
+		switch function := function.(type) {
+		case jinjaMethod:
+			return function(positional, keywords)
+		case jinjaUndefined:
+			return nil, fmt.Errorf("'%s' is undefined", function.name)
+		}
+
+		return nil, fmt.Errorf("'%s' object is not callable", jinjaTypeName(function))
+	case *jinjaFilter:
+		value, err := r.evaluate(node.value)
+		if err != nil {
+			return nil, err
+		}
+
+		positional, keywords, err := r.evaluateArguments(node.jinjaArguments)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := jinjaFilters[node.name](value, positional, keywords)
+		if err != nil {
+			return nil, fmt.Errorf("filter '%s': %w", node.name, err)
+		}
+
+		return result, nil
+	case *jinjaTest:
+		value, err := r.evaluate(node.value)
+		if err != nil {
+			return nil, err
+		}
+
+		positional, _, err := r.evaluateArguments(node.jinjaArguments)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := jinjaTests[node.name](value, positional)
+		if err != nil {
+			return nil, fmt.Errorf("test '%s': %w", node.name, err)
+		}
+
+		return result != node.negated, nil
+	case *jinjaUnary:
+		operand, err := r.evaluate(node.operand)
+		if err != nil {
+			return nil, err
+		}
+
+		switch node.operator {
+		case "not":
+			return !jinjaTruthy(operand), nil
+		case "-":
+			return jinjaArithmetic("*", int64(-1), operand)
+		}
+
+		return jinjaArithmetic("+", int64(0), operand)
+	case *jinjaBinary:
+		left, err := r.evaluate(node.left)
+		if err != nil {
+			return nil, err
+		}
+
+		// NOTE: same as in Python, `and` and `or` return one of operands
+		switch node.operator {
+		case "and":
+			if !jinjaTruthy(left) {
+				return left, nil
+			}
+			return r.evaluate(node.right)
+		case "or":
+			if jinjaTruthy(left) {
+				return left, nil
+			}
+			return r.evaluate(node.right)
+		}
+
+		right, err := r.evaluate(node.right)
+		if err != nil {
+			return nil, err
+		}
+
+		if node.operator == "~" {
+			return jinjaString(left) + jinjaString(right), nil
+		}
+
+		if format, ok := left.(string); ok && node.operator == "%" {
+			if _, ok := node.right.(*jinjaTuple); ok {
+				return jinjaFormat(format, right.([]any))
+			}
+			return jinjaFormat(format, []any{right})
+		}
+
+		return jinjaArithmetic(node.operator, left, right)
+	case *jinjaCompare:
+		left, err := r.evaluate(node.left)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, operator := range node.operators {
+			right, err := r.evaluate(node.rights[i])
+			if err != nil {
+				return nil, err
+			}
+
+			result, err := jinjaComparison(operator, left, right)
+			if err != nil {
+				return nil, err
+			}
+
+			if !result {
+				return false, nil
+			}
+
+			left = right
+		}
+
+		return true, nil
+	case *jinjaConditional:
+		condition, err := r.evaluate(node.condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if jinjaTruthy(condition) {
+			return r.evaluate(node.then)
+		}
+
+		// NOTE: Jinja returns undefined without a name, which cloud-init renders as `None`
+		if node.orElse == nil {
+			return jinjaUndefined{name: "None"}, nil
+		}
+
+		return r.evaluate(node.orElse)
+	}
+
+	return nil, fmt.Errorf("unsupported expression %T", expression)
+}
+
+func (r *jinjaRenderer) evaluateSlice(node *jinjaSlice) (any, error) {
+	object, err := r.evaluate(node.object)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := [2]*int64{}
+
+	for i, expression := range []jinjaExpression{node.start, node.stop} {
+		if expression == nil {
+			continue
+		}
+
+		value, err := r.evaluate(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			continue
+		}
+
+		index, ok := value.(int64)
+		if !ok {
+			return nil, fmt.Errorf("slice indices must be integers, got '%s'", jinjaTypeName(value))
+		}
+		bounds[i] = &index
+	}
+
+	clamp := func(bound *int64, length int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+
+		index := int(*bound)
+		if index < 0 {
+			index += length
+		}
+
+		return max(0, min(index, length))
+	}
+
+	switch object := object.(type) {
+	case string:
+		runes := []rune(object)
+		start, stop := clamp(bounds[0], len(runes), 0), clamp(bounds[1], len(runes), len(runes))
+		if start >= stop {
+			return "", nil
+		}
+		return string(runes[start:stop]), nil
+	case []any:
+		start, stop := clamp(bounds[0], len(object), 0), clamp(bounds[1], len(object), len(object))
+		if start >= stop {
+			return []any{}, nil
+		}
+		return append([]any{}, object[start:stop]...), nil
+	case jinjaUndefined:
+		return nil, fmt.Errorf("'%s' is undefined", object.name)
+	}
+
+	return nil, fmt.Errorf("'%s' object is not subscriptable", jinjaTypeName(object))
+}
+
+// jinjaGetAttribute
+// `object.name`, methods go first and keys of dicts second, same as in Jinja
+func jinjaGetAttribute(object any, name string) (any, error) {
+	switch object := object.(type) {
+	case jinjaUndefined:
+		return nil, fmt.Errorf("'%s' is undefined", object.name)
+	case map[string]any:
+		if method := jinjaDictMethod(object, name); method != nil {
+			return method, nil
+		}
+		if value, ok := object[name]; ok {
+			return value, nil
+		}
+	case string:
+		if method := jinjaStringMethod(object, name); method != nil {
+			return method, nil
+		}
+	}
+
+	return jinjaUndefined{name: name}, nil
+}
+
+// jinjaGetItemValue
+// `object[key]`, keys of dicts go first and attributes second, same as in Jinja
+func jinjaGetItemValue(object any, key any) (any, error) {
+	switch object := object.(type) {
+	case jinjaUndefined:
+		return nil, fmt.Errorf("'%s' is undefined", object.name)
+	case map[string]any:
+		if name, ok := key.(string); ok {
+			if value, ok := object[name]; ok {
+				return value, nil
+			}
+			return jinjaGetAttribute(object, name)
+		}
+	case []any:
+		if index, ok := key.(int64); ok {
+			if index < 0 {
+				index += int64(len(object))
+			}
+			if index >= 0 && index < int64(len(object)) {
+				return object[index], nil
+			}
+		}
+	case string:
+		if index, ok := key.(int64); ok {
+			runes := []rune(object)
+			if index < 0 {
+				index += int64(len(runes))
+			}
+			if index >= 0 && index < int64(len(runes)) {
+				return string(runes[index]), nil
+			}
+		}
+		if name, ok := key.(string); ok {
+			return jinjaGetAttribute(object, name)
+		}
+	}
+
+	return jinjaUndefined{name: jinjaString(key)}, nil
+}
+
+func jinjaDictMethod(dict map[string]any, name string) jinjaMethod {
+	switch name {
+	case "items":
+		return func(_ []any, _ map[string]any) (any, error) {
+			items := []any{}
+			for _, key := range jinjaKeys(dict) {
+				items = append(items, []any{key, dict[key]})
+			}
+			return items, nil
+		}
+	case "keys":
+		return func(_ []any, _ map[string]any) (any, error) {
+			keys := []any{}
+			for _, key := range jinjaKeys(dict) {
+				keys = append(keys, key)
+			}
+			return keys, nil
+		}
+	case "values":
+		return func(_ []any, _ map[string]any) (any, error) {
+			values := []any{}
+			for _, key := range jinjaKeys(dict) {
+				values = append(values, dict[key])
+			}
+			return values, nil
+		}
+	case "update":
+		return func(positional []any, keywords map[string]any) (any, error) {
+			if len(positional) > 1 {
+				return nil, fmt.Errorf("update expected at most 1 argument, got %d", len(positional))
+			}
+			if len(positional) == 1 {
+				other, ok := positional[0].(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("update() expects a dict, got '%s'", jinjaTypeName(positional[0]))
+				}
+				maps.Copy(dict, other)
+			}
+			maps.Copy(dict, keywords)
+			return nil, nil
+		}
+	case "get":
+		return func(positional []any, _ map[string]any) (any, error) {
+			if len(positional) == 0 {
+				return nil, fmt.Errorf("get() expects a key")
+			}
+			if value, ok := dict[jinjaString(positional[0])]; ok {
+				return value, nil
+			}
+			if len(positional) > 1 {
+				return positional[1], nil
+			}
+			return nil, nil
+		}
+	}
+
+	return nil
+}
+
+func jinjaStringMethod(value string, name string) jinjaMethod {
+	stringArgument := func(positional []any, i int, fallback string) (string, error) {
+		if i >= len(positional) || positional[i] == nil {
+			return fallback, nil
+		}
+
+		argument, ok := positional[i].(string)
+		if !ok {
+			return "", fmt.Errorf("%s() expects a string, got '%s'", name, jinjaTypeName(positional[i]))
+		}
+
+		return argument, nil
+	}
+
+	switch name {
+	case "lower", "upper", "title", "capitalize":
+		return func(_ []any, _ map[string]any) (any, error) {
+			return jinjaFilters[name](value, nil, nil)
+		}
+	case "strip", "lstrip", "rstrip":
+		return func(positional []any, _ map[string]any) (any, error) {
+			chars, err := stringArgument(positional, 0, jinjaWhitespace+"\v\f")
+			if err != nil {
+				return nil, err
+			}
+			switch name {
+			case "lstrip":
+				return strings.TrimLeft(value, chars), nil
+			case "rstrip":
+				return strings.TrimRight(value, chars), nil
+			}
+			return strings.Trim(value, chars), nil
+		}
+	case "startswith", "endswith":
+		return func(positional []any, _ map[string]any) (any, error) {
+			affix, err := stringArgument(positional, 0, "")
+			if err != nil {
+				return nil, err
+			}
+			if name == "startswith" {
+				return strings.HasPrefix(value, affix), nil
+			}
+			return strings.HasSuffix(value, affix), nil
+		}
+	case "replace":
+		return func(positional []any, _ map[string]any) (any, error) {
+			return jinjaFilters["replace"](value, positional, nil)
+		}
+	case "split":
+		return func(positional []any, keywords map[string]any) (any, error) {
+			separator, err := stringArgument(positional, 0, "")
+			if err != nil {
+				return nil, err
+			}
+
+			limit := int64(-1)
+			if len(positional) > 1 {
+				if limit, err = jinjaToInt(positional[1]); err != nil {
+					return nil, err
+				}
+			}
+
+			var parts []string
+			switch {
+			case separator == "":
+				parts = jinjaSplitFields(value, limit)
+			case limit < 0:
+				parts = strings.Split(value, separator)
+			default:
+				parts = strings.SplitN(value, separator, int(limit)+1)
+			}
+
+			items := []any{}
+			for _, part := range parts {
+				items = append(items, part)
+			}
+
+			return items, nil
+		}
+	case "join":
+		return func(positional []any, _ map[string]any) (any, error) {
+			if len(positional) == 0 {
+				return nil, fmt.Errorf("join() expects an iterable")
+			}
+			return jinjaFilters["join"](positional[0], []any{value}, nil)
+		}
+	}
+
+	return nil
+}
+
+// jinjaSplitFields
+// Same as `split()` in Python without a separator, the remainder keeps its whitespace
+func jinjaSplitFields(value string, limit int64) []string {
+	whitespace := jinjaWhitespace + "\v\f"
+
+	parts := []string{}
+	rest := strings.TrimLeft(value, whitespace)
+
+	for rest != "" {
+		if limit >= 0 && int64(len(parts)) == limit {
+			return append(parts, rest)
+		}
+
+		end := strings.IndexAny(rest, whitespace)
+		if end < 0 {
+			end = len(rest)
+		}
+
+		parts = append(parts, rest[:end])
+		rest = strings.TrimLeft(rest[end:], whitespace)
+	}
+
+	return parts
+}
+
+// jinjaKeys
+// Keys of the dict, sorted, same as instance-data converted by cloud-init
+func jinjaKeys(dict map[string]any) []string {
+	keys := make([]string, 0, len(dict))
+	for key := range dict {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// jinjaIterate
+// Items of lists, keys of dicts and characters of strings
+func jinjaIterate(value any) ([]any, error) {
+	switch value := value.(type) {
+	case jinjaUndefined:
+		return []any{}, nil
+	case []any:
+		return value, nil
+	case map[string]any:
+		items := []any{}
+		for _, key := range jinjaKeys(value) {
+			items = append(items, key)
+		}
+		return items, nil
+	case string:
+		items := []any{}
+		for _, r := range value {
+			items = append(items, string(r))
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("'%s' object is not iterable", jinjaTypeName(value))
+}
+
+// jinjaTypeName
+// Name of Python type of the value, used in errors
+func jinjaTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "NoneType"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "str"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "dict"
+	case jinjaMethod:
+		return "method"
+	case jinjaUndefined:
+		return "Undefined"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func jinjaTruthy(value any) bool {
+	switch value := value.(type) {
+	case nil, jinjaUndefined:
+		return false
+	case bool:
+		return value
+	case int64:
+		return value != 0
+	case float64:
+		return value != 0
+	case string:
+		return value != ""
+	case []any:
+		return len(value) > 0
+	case map[string]any:
+		return len(value) > 0
+	}
+
+	return true
+}
+
+// jinjaString
+// Same as `str()` in Python
+func jinjaString(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case jinjaUndefined:
+		return JinjaUndefinedPrefix + value.name
+	}
+
+	return jinjaRepr(value)
+}
+
+// jinjaRepr
+// Same as `repr()` in Python, used for items of lists and dicts
+func jinjaRepr(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if value {
+			return "True"
+		}
+		return "False"
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return jinjaFloatString(value)
+	case string:
+		quote := "'"
+		if strings.Contains(value, "'") && !strings.Contains(value, `"`) {
+			quote = `"`
+		}
+
+		var out strings.Builder
+		out.WriteString(quote)
+		for _, r := range value {
+			switch {
+			case r == '\\':
+				out.WriteString(`\\`)
+			case string(r) == quote:
+				out.WriteString(`\` + quote)
+			case r == '\n':
+				out.WriteString(`\n`)
+			case r == '\t':
+				out.WriteString(`\t`)
+			case r == '\r':
+				out.WriteString(`\r`)
+			case r < 0x20 || r == 0x7f:
+				fmt.Fprintf(&out, `\x%02x`, r)
+			default:
+				out.WriteRune(r)
+			}
+		}
+		out.WriteString(quote)
+
+		return out.String()
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, jinjaRepr(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		items := make([]string, 0, len(value))
+		for _, key := range jinjaKeys(value) {
+			items = append(items, jinjaRepr(key)+": "+jinjaRepr(value[key]))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case jinjaUndefined:
+		return JinjaUndefinedPrefix + value.name
+	}
+
+	return fmt.Sprintf("<%s>", jinjaTypeName(value))
+}
+
+// jinjaFloatString
+// Python keeps the fraction of whole floats and switches to exponent at 1e16
+func jinjaFloatString(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	case math.IsNaN(value):
+		return "nan"
+	}
+
+	exponent := 0
+	if value != 0 {
+		exponent = int(math.Floor(math.Log10(math.Abs(value))))
+	}
+
+	if exponent < -4 || exponent >= 16 {
+		return strconv.FormatFloat(value, 'e', -1, 64)
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if !strings.Contains(formatted, ".") {
+		formatted += ".0"
+	}
+
+	return formatted
+}
+
+// jinjaNumber
+// Numeric value of ints, floats and booleans, booleans are ints in Python
+func jinjaNumber(value any) (float64, bool, bool) {
+	switch value := value.(type) {
+	case bool:
+		if value {
+			return 1, true, true
+		}
+		return 0, true, true
+	case int64:
+		return float64(value), true, true
+	case float64:
+		return value, false, true
+	}
+
+	return 0, false, false
+}
+
+func jinjaToInt(value any) (int64, error) {
+	switch value := value.(type) {
+	case int64:
+		return value, nil
+	case bool:
+		if value {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("expected an integer, got '%s'", jinjaTypeName(value))
+}
+
+func jinjaArithmetic(operator string, left, right any) (any, error) {
+	for _, operand := range []any{left, right} {
+		if undefined, ok := operand.(jinjaUndefined); ok {
+			return nil, fmt.Errorf("'%s' is undefined", undefined.name)
+		}
+	}
+
+	switch operator {
+	case "+":
+		switch left := left.(type) {
+		case string:
+			if right, ok := right.(string); ok {
+				return left + right, nil
+			}
+		case []any:
+			if right, ok := right.([]any); ok {
+				return append(append([]any{}, left...), right...), nil
+			}
+		}
+	case "*":
+		// NOTE: sequences can be repeated, e.g. `'-' * 8`
+		if count, err := jinjaToInt(right); err == nil {
+			switch left := left.(type) {
+			case string:
+				return strings.Repeat(left, int(max(count, 0))), nil
+			case []any:
+				items := []any{}
+				for range max(count, 0) {
+					items = append(items, left...)
+				}
+				return items, nil
+			}
+		}
+	}
+
+	a, aInteger, aOK := jinjaNumber(left)
+	b, bInteger, bOK := jinjaNumber(right)
+
+	if !aOK || !bOK {
+		return nil, fmt.Errorf("unsupported operand type(s) for %s: '%s' and '%s'", operator, jinjaTypeName(left), jinjaTypeName(right))
+	}
+
+	integers := aInteger && bInteger
+
+	if integers && operator != "/" {
+		x, _ := jinjaToInt(left)
+		y, _ := jinjaToInt(right)
+
+		switch operator {
+		case "+":
+			result := x + y
+			return jinjaCheckOverflow(result, (x > 0 && y > 0 && result < 0) || (x < 0 && y < 0 && result >= 0))
+		case "-":
+			result := x - y
+			return jinjaCheckOverflow(result, (x >= 0 && y < 0 && result < 0) || (x < 0 && y > 0 && result >= 0))
+		case "*":
+			return jinjaCheckOverflow(jinjaMultiply(x, y))
+		case "//", "%":
+			if y == 0 {
+				return nil, fmt.Errorf("integer division or modulo by zero")
+			}
+			if x == math.MinInt64 && y == -1 {
+				if operator == "%" {
+					return int64(0), nil
+				}
+				return jinjaCheckOverflow(0, true)
+			}
+
+			quotient, remainder := x/y, x%y
+			if remainder != 0 && (remainder < 0) != (y < 0) {
+				quotient--
+				remainder += y
+			}
+
+			if operator == "//" {
+				return quotient, nil
+			}
+			return remainder, nil
+		case "**":
+			if y >= 0 {
+				return jinjaCheckOverflow(jinjaPower(x, y))
+			}
+		}
+	}
+
+	switch operator {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	case "//":
+		if b == 0 {
+			return nil, fmt.Errorf("float floor division by zero")
+		}
+		return math.Floor(a / b), nil
+	case "%":
+		if b == 0 {
+			return nil, fmt.Errorf("float modulo")
+		}
+		remainder := math.Mod(a, b)
+		if remainder != 0 && (remainder < 0) != (b < 0) {
+			remainder += b
+		}
+		return remainder, nil
+	case "**":
+		return math.Pow(a, b), nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator '%s'", operator)
+}
+
+// jinjaFormat
+// Same as `%` formatting of strings in Python, arguments are items of a tuple or a single mapping
+func jinjaFormat(format string, arguments []any) (string, error) {
+	var out strings.Builder
+
+	var mapping map[string]any
+	if len(arguments) == 1 {
+		mapping, _ = arguments[0].(map[string]any)
+	}
+
+	next := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+
+		j := i + 1
+
+		var argument any
+		hasArgument := false
+
+		// NOTE: `%(name)s` takes the argument from a mapping
+		if j < len(format) && format[j] == '(' {
+			end := strings.IndexByte(format[j:], ')')
+			if end < 0 {
+				return "", fmt.Errorf("incomplete format key")
+			}
+
+			if mapping == nil {
+				return "", fmt.Errorf("format requires a mapping")
+			}
+
+			key := format[j+1 : j+end]
+			value, ok := mapping[key]
+			if !ok {
+				return "", fmt.Errorf("key %s is missing", jinjaRepr(key))
+			}
+
+			argument, hasArgument = value, true
+			next = len(arguments)
+			j += end + 1
+		}
+
+		flags := j
+		for j < len(format) && strings.IndexByte("-+ 0#", format[j]) >= 0 {
+			j++
+		}
+		for j < len(format) && (format[j] >= '0' && format[j] <= '9' || format[j] == '.') {
+			j++
+		}
+
+		if j >= len(format) {
+			return "", fmt.Errorf("incomplete format")
+		}
+
+		spec, verb := format[flags:j], format[j]
+		i = j
+
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+
+		if !hasArgument {
+			if next >= len(arguments) {
+				return "", fmt.Errorf("not enough arguments for format string")
+			}
+			argument = arguments[next]
+			next++
+		}
+
+		switch verb {
+		case 's':
+			fmt.Fprintf(&out, "%"+spec+"s", jinjaString(argument))
+		case 'r', 'a':
+			fmt.Fprintf(&out, "%"+spec+"s", jinjaRepr(argument))
+		case 'd', 'i', 'u', 'x', 'X', 'o':
+			number, integer, ok := jinjaNumber(argument)
+			if !ok {
+				return "", fmt.Errorf("%%%c format: a real number is required, not %s", verb, jinjaTypeName(argument))
+			}
+
+			value := int64(number)
+			if integer {
+				value, _ = jinjaToInt(argument)
+			}
+
+			switch verb {
+			case 'd', 'i', 'u':
+				verb = 'd'
+			case 'o':
+				// NOTE: Python prefixes octal with `0o`, same as `%O` in Go
+				if strings.Contains(spec, "#") {
+					spec, verb = strings.ReplaceAll(spec, "#", ""), 'O'
+				}
+			}
+
+			fmt.Fprintf(&out, "%"+spec+string(verb), value)
+		case 'f', 'F', 'e', 'E', 'g', 'G':
+			number, _, ok := jinjaNumber(argument)
+			if !ok {
+				return "", fmt.Errorf("must be real number, not %s", jinjaTypeName(argument))
+			}
+
+			// NOTE: Python defaults to 6 digits of precision, Go uses the shortest representation for `%g`
+			if !strings.Contains(spec, ".") {
+				spec += ".6"
+			}
+
+			fmt.Fprintf(&out, "%"+spec+string(verb), number)
+		case 'c':
+			switch value := argument.(type) {
+			case int64:
+				fmt.Fprintf(&out, "%"+spec+"c", rune(value))
+			case string:
+				if utf8.RuneCountInString(value) != 1 {
+					return "", fmt.Errorf("%%c requires int or char")
+				}
+				fmt.Fprintf(&out, "%"+spec+"s", value)
+			default:
+				return "", fmt.Errorf("%%c requires int or char")
+			}
+		default:
+			return "", fmt.Errorf("unsupported format character '%c' (0x%x)", verb, verb)
+		}
+	}
+
+	// NOTE: a mapping may be left unused, same as in Python
+	if next < len(arguments) && mapping == nil {
+		return "", fmt.Errorf("not all arguments converted during string formatting")
+	}
+
+	return out.String(), nil
+}
+
+// jinjaCheckOverflow
+// Python integers are unbounded, so results which don't fit into 64 bits are reported instead of being wrapped
+func jinjaCheckOverflow(result int64, overflow bool) (any, error) {
+	if overflow {
+		return nil, fmt.Errorf("integer overflow, results beyond 64 bits are not supported")
+	}
+
+	return result, nil
+}
+
+func jinjaMultiply(x, y int64) (int64, bool) {
+	if x == 0 || y == 0 {
+		return 0, false
+	}
+
+	result := x * y
+
+	return result, result/y != x || (x == -1 && y == math.MinInt64) || (y == -1 && x == math.MinInt64)
+}
+
+// jinjaPower
+// Exponentiation by squaring, so large exponents take a few steps and overflow early
+func jinjaPower(x, y int64) (int64, bool) {
+	result, base := int64(1), x
+
+	for y > 0 {
+		var overflow bool
+
+		if y&1 == 1 {
+			if result, overflow = jinjaMultiply(result, base); overflow {
+				return 0, true
+			}
+		}
+
+		y >>= 1
+
+		if y > 0 {
+			if base, overflow = jinjaMultiply(base, base); overflow {
+				return 0, true
+			}
+		}
+	}
+
+	return result, false
+}
+
+// jinjaEqual
+// Same as `==` in Python, ints and floats are compared by value
+func jinjaEqual(left, right any) bool {
+	if a, _, ok := jinjaNumber(left); ok {
+		b, _, ok := jinjaNumber(right)
+		return ok && a == b
+	}
+
+	switch left := left.(type) {
+	case nil:
+		return right == nil
+	case string:
+		right, ok := right.(string)
+		return ok && left == right
+	case []any:
+		right, ok := right.([]any)
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		for i := range left {
+			if !jinjaEqual(left[i], right[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		right, ok := right.(map[string]any)
+		if !ok || len(left) != len(right) {
+			return false
+		}
+		for key, value := range left {
+			other, ok := right[key]
+			if !ok || !jinjaEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case jinjaUndefined:
+		_, ok := right.(jinjaUndefined)
+		return ok
+	}
+
+	return false
+}
+
+// jinjaCompareOrder
+// Negative, zero or positive, when the left value is less, equal or greater
+func jinjaCompareOrder(left, right any) (int, error) {
+	if a, _, ok := jinjaNumber(left); ok {
+		if b, _, ok := jinjaNumber(right); ok {
+			switch {
+			case a < b:
+				return -1, nil
+			case a > b:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+
+	switch left := left.(type) {
+	case string:
+		if right, ok := right.(string); ok {
+			return strings.Compare(left, right), nil
+		}
+	case []any:
+		if right, ok := right.([]any); ok {
+			for i := 0; i < len(left) && i < len(right); i++ {
+				order, err := jinjaCompareOrder(left[i], right[i])
+				if err != nil || order != 0 {
+					return order, err
+				}
+			}
+			return len(left) - len(right), nil
+		}
+	}
+
+	return 0, fmt.Errorf("'<' not supported between instances of '%s' and '%s'", jinjaTypeName(left), jinjaTypeName(right))
+}
+
+func jinjaComparison(operator string, left, right any) (bool, error) {
+	switch operator {
+	case "==":
+		return jinjaEqual(left, right), nil
+	case "!=":
+		return !jinjaEqual(left, right), nil
+	case "in", "not in":
+		contains, err := jinjaContains(right, left)
+		return contains == (operator == "in"), err
+	}
+
+	order, err := jinjaCompareOrder(left, right)
+	if err != nil {
+		return false, err
+	}
+
+	switch operator {
+	case "<":
+		return order < 0, nil
+	case ">":
+		return order > 0, nil
+	case "<=":
+		return order <= 0, nil
+	}
+
+	return order >= 0, nil
+}
+
+// jinjaContains
+// Same as `in` in Python: substrings, items of lists and keys of dicts
+func jinjaContains(container, value any) (bool, error) {
+	switch container := container.(type) {
+	case string:
+		value, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("'in <string>' requires string as left operand, not '%s'", jinjaTypeName(value))
+		}
+		return strings.Contains(container, value), nil
+	case []any:
+		for _, item := range container {
+			if jinjaEqual(item, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := value.(string)
+		if !ok {
+			return false, nil
+		}
+		_, ok = container[key]
+		return ok, nil
+	case jinjaUndefined:
+		return false, fmt.Errorf("'%s' is undefined", container.name)
+	}
+
+	return false, fmt.Errorf("argument of type '%s' is not iterable", jinjaTypeName(container))
+}
+
+// jinjaLength
+// Same as `len()` in Python, characters of strings are counted
+func jinjaLength(value any) (int, error) {
+	switch value := value.(type) {
+	case string:
+		return utf8.RuneCountInString(value), nil
+	case []any:
+		return len(value), nil
+	case map[string]any:
+		return len(value), nil
+	case jinjaUndefined:
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("object of type '%s' has no len()", jinjaTypeName(value))
+}
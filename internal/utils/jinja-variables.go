@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"maps"
+	"slices"
+)
+
+// JinjaVariable
+// Variable, which the template reads from its context, with attributes and constant keys read from it,
+// e.g. `region` of `v1.region` or `v1['region']`
+type JinjaVariable struct {
+	Name string
+	Keys []string
+}
+
+// JinjaVariables
+// Variables, which the template reads from its context, in order of first use.
+// Names bound by `for` and `set` statements anywhere in the template, as well as globals
+// like `range()`, are left out. Text, comments and `raw` blocks are never read
+func JinjaVariables(template string) ([]JinjaVariable, error) {
+	tokens, err := jinjaLex(template)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &jinjaParser{tokens: tokens}
+
+	nodes, _, err := parser.parseBody()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := &jinjaVariableCollector{bound: map[string]bool{}}
+	collector.bind(nodes)
+	collector.nodes(nodes)
+
+	return collector.used, nil
+}
+
+type jinjaVariableCollector struct {
+	bound map[string]bool
+	used  []JinjaVariable
+}
+
+// bind
+// Marks targets of `for` and `set` statements, so they aren't reported wherever they are read
+func (c *jinjaVariableCollector) bind(nodes []jinjaNode) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *jinjaIfNode:
+			for _, branch := range node.branches {
+				c.bind(branch.body)
+			}
+			c.bind(node.orElse)
+		case *jinjaForNode:
+			c.bound["loop"] = true
+			for _, target := range node.targets {
+				c.bound[target] = true
+			}
+			c.bind(node.body)
+			c.bind(node.orElse)
+		case *jinjaSetNode:
+			for _, target := range node.targets {
+				if variable, ok := target.(*jinjaVariable); ok {
+					c.bound[variable.name] = true
+				}
+			}
+			c.bind(node.body)
+		}
+	}
+}
+
+func (c *jinjaVariableCollector) nodes(nodes []jinjaNode) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *jinjaOutputNode:
+			c.expression(node.expression)
+		case *jinjaIfNode:
+			for _, branch := range node.branches {
+				c.expression(branch.condition)
+				c.nodes(branch.body)
+			}
+			c.nodes(node.orElse)
+		case *jinjaForNode:
+			c.expression(node.iterable)
+			c.expression(node.condition)
+			c.nodes(node.body)
+			c.nodes(node.orElse)
+		case *jinjaSetNode:
+			for _, target := range node.targets {
+				// NOTE: `set ns.attribute = ...` reads `ns`
+				if attribute, ok := target.(*jinjaGetAttr); ok {
+					c.expression(attribute.object)
+				}
+			}
+			c.expression(node.value)
+			c.nodes(node.body)
+		case *jinjaDoNode:
+			c.expression(node.expression)
+		}
+	}
+}
+
+func (c *jinjaVariableCollector) expression(expression jinjaExpression) {
+	switch node := expression.(type) {
+	case *jinjaVariable:
+		c.variable(node.name)
+	case *jinjaGetAttr:
+		c.expression(node.object)
+		c.key(node.object, node.name)
+	case *jinjaGetItem:
+		c.expression(node.object)
+		c.expression(node.key)
+		if literal, ok := node.key.(*jinjaLiteral); ok {
+			if key, ok := literal.value.(string); ok {
+				c.key(node.object, key)
+			}
+		}
+	case *jinjaSlice:
+		c.expression(node.object)
+		c.expression(node.start)
+		c.expression(node.stop)
+	case *jinjaCall:
+		// NOTE: method calls, e.g. `v1.get('region')`, don't read keys
+		if method, ok := node.function.(*jinjaGetAttr); ok {
+			c.expression(method.object)
+		} else {
+			c.expression(node.function)
+		}
+		c.arguments(node.jinjaArguments)
+	case *jinjaFilter:
+		c.expression(node.value)
+		c.arguments(node.jinjaArguments)
+	case *jinjaTest:
+		c.expression(node.value)
+		c.arguments(node.jinjaArguments)
+	case *jinjaUnary:
+		c.expression(node.operand)
+	case *jinjaBinary:
+		c.expression(node.left)
+		c.expression(node.right)
+	case *jinjaCompare:
+		c.expression(node.left)
+		for _, right := range node.rights {
+			c.expression(right)
+		}
+	case *jinjaConditional:
+		c.expression(node.condition)
+		c.expression(node.then)
+		c.expression(node.orElse)
+	case *jinjaList:
+		for _, item := range node.items {
+			c.expression(item)
+		}
+	case *jinjaTuple:
+		for _, item := range node.items {
+			c.expression(item)
+		}
+	case *jinjaDict:
+		for i := range node.keys {
+			c.expression(node.keys[i])
+			c.expression(node.values[i])
+		}
+	}
+}
+
+// variable
+// Entry of a variable read from the context, nil for bound names and globals
+func (c *jinjaVariableCollector) variable(name string) *JinjaVariable {
+	if _, ok := jinjaGlobals[name]; ok || c.bound[name] {
+		return nil
+	}
+
+	index := slices.IndexFunc(c.used, func(variable JinjaVariable) bool { return variable.Name == name })
+	if index < 0 {
+		c.used = append(c.used, JinjaVariable{Name: name})
+		index = len(c.used) - 1
+	}
+
+	return &c.used[index]
+}
+
+// key
+// Records a key read directly from a context variable, keys of nested values are left out
+func (c *jinjaVariableCollector) key(object jinjaExpression, key string) {
+	variable, ok := object.(*jinjaVariable)
+	if !ok {
+		return
+	}
+
+	if entry := c.variable(variable.name); entry != nil && !slices.Contains(entry.Keys, key) {
+		entry.Keys = append(entry.Keys, key)
+	}
+}
+
+func (c *jinjaVariableCollector) arguments(arguments jinjaArguments) {
+	for _, argument := range arguments.positional {
+		c.expression(argument)
+	}
+
+	// NOTE: map order is random, names are sorted so the result is stable
+	for _, name := range slices.Sorted(maps.Keys(arguments.keywords)) {
+		c.expression(arguments.keywords[name])
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJinjaVariables(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		expected []JinjaVariable
+	}{
+		{name: "output", template: "{{ region }} {{ v1.instance_id }} {{ region }}", expected: []JinjaVariable{{Name: "region"}, {Name: "v1", Keys: []string{"instance_id"}}}},
+		{name: "text and comments", template: "region v1.zone {# {{ v1.region }} #} {% raw %}{{ v1.zone }}{% endraw %}", expected: nil},
+		{name: "attributes and keys", template: "{{ ds.meta_data['name'] }} {{ a.b.c }} {{ v1['cloud-name'] ~ v1.region ~ v1['region'] }}", expected: []JinjaVariable{{Name: "ds", Keys: []string{"meta_data"}}, {Name: "a", Keys: []string{"b"}}, {Name: "v1", Keys: []string{"cloud-name", "region"}}}},
+		{name: "dynamic keys and methods", template: "{{ v1[key] }} {{ v1[0] }} {{ v1.get('region') }}", expected: []JinjaVariable{{Name: "v1"}, {Name: "key"}}},
+		{name: "filters and tests", template: "{{ x | default(y) | join(sep=z) }} {{ w is divisibleby n }}", expected: []JinjaVariable{{Name: "x"}, {Name: "y"}, {Name: "z"}, {Name: "w"}, {Name: "n"}}},
+		{name: "literals", template: "{{ 'region' ~ none ~ true }}", expected: nil},
+		{name: "globals", template: "{% for i in range(3) %}{{ dict(a=i) }}{% endfor %}", expected: nil},
+		{name: "loop variables", template: "{% for k, v in items if v %}{{ k }}{{ loop.index }}{% else %}{{ empty }}{% endfor %}", expected: []JinjaVariable{{Name: "items"}, {Name: "empty"}}},
+		{name: "set", template: "{{ name }}{% set name = hostname %}{% set ns = namespace(a=1) %}{% set ns.a = b %}", expected: []JinjaVariable{{Name: "hostname"}, {Name: "b"}}},
+		{name: "set block", template: "{% set text %}{{ region }}{% endset %}{{ text }}", expected: []JinjaVariable{{Name: "region"}}},
+		{name: "shadowed", template: "{% set v1 = {} %}{{ v1.region }}", expected: nil},
+		{name: "if", template: "{% if a %}{{ b }}{% elif c %}{% else %}{{ d }}{% endif %}", expected: []JinjaVariable{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}},
+		{name: "expressions", template: "{{ -a + b[c:d] if e in [f, (g, h)] else {i: j}.get(k) }}", expected: []JinjaVariable{{Name: "e"}, {Name: "f"}, {Name: "g"}, {Name: "h"}, {Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "i"}, {Name: "j"}, {Name: "k"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			variables, err := JinjaVariables(tc.template)
+			if err != nil {
+				t.Fatalf("JinjaVariables: %v", err)
+			}
+
+			if !reflect.DeepEqual(variables, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, variables)
+			}
+		})
+	}
+
+	if _, err := JinjaVariables("{% if %}"); err == nil {
+		t.Errorf("expected error of invalid template")
+	}
+}
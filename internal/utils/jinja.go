@@ -0,0 +1,1221 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Jinja
+// Renders a template with the subset of Jinja, which cloud-init templates rely on:
+//   - `{{ ... }}` expressions, `{# ... #}` comments and `{% raw %}` blocks
+//   - `if`, `for`, `set` and `do` statements, `-` and `+` whitespace control
+//   - `append()` and `extend()` of lists, `update()` of dicts, e.g. in `do` statements
+//   - attributes, subscripts, arithmetic, comparisons, common filters and tests
+//
+// Same as cloud-init, blocks are trimmed (`trim_blocks`), and undefined variables are rendered
+// as `CI_MISSING_JINJA_VAR/<name>`.
+func Jinja(template string, variables map[string]any) (string, error) {
+	tokens, err := jinjaLex(template)
+	if err != nil {
+		return "", err
+	}
+
+	parser := &jinjaParser{tokens: tokens}
+
+	nodes, _, err := parser.parseBody()
+	if err != nil {
+		return "", err
+	}
+
+	// NOTE: top-level `set` goes to its own scope, so variables of the caller are never modified
+	renderer := &jinjaRenderer{scopes: []map[string]any{variables, {}}}
+
+	var out strings.Builder
+	if err := renderer.render(&out, nodes); err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+type jinjaTokenKind int
+
+const (
+	jinjaTokenText jinjaTokenKind = iota
+	jinjaTokenVariableBegin
+	jinjaTokenVariableEnd
+	jinjaTokenBlockBegin
+	jinjaTokenBlockEnd
+	jinjaTokenName
+	jinjaTokenString
+	jinjaTokenInteger
+	jinjaTokenFloat
+	jinjaTokenOperator
+	jinjaTokenEOF
+)
+
+type jinjaToken struct {
+	kind  jinjaTokenKind
+	value string
+	line  int
+}
+
+const jinjaWhitespace = " \t\r\n"
+
+var (
+	jinjaRaw    = regexp.MustCompile(`^{%[-+]?\s*raw\s*([-+]?)%}`)
+	jinjaEndRaw = regexp.MustCompile(`{%([-+]?)\s*endraw\s*([-+]?)%}`)
+
+	jinjaExponent = regexp.MustCompile(`^[eE][+-]?[0-9]+`)
+
+	// NOTE: longer operators go first
+	jinjaOperators = []string{"**", "//", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "~", "<", ">", "=", "(", ")", "[", "]", "{", "}", ".", ",", ":", "|"}
+)
+
+// jinjaLex
+// Splits the template into text and tokens of expressions and statements
+func jinjaLex(source string) ([]jinjaToken, error) {
+	tokens := []jinjaToken{}
+	pos := 0
+
+	// NOTE: set by the previous tag, `-` strips all whitespace, blocks strip a single newline unless they end with `+`
+	lstrip, trimBlock := false, false
+
+	lineAt := func(pos int) int {
+		return strings.Count(source[:pos], "\n") + 1
+	}
+
+	emitText := func(start int, text string, rstrip bool) {
+		if lstrip {
+			text = strings.TrimLeft(text, jinjaWhitespace)
+		} else if trimBlock {
+			text = strings.TrimPrefix(strings.TrimPrefix(text, "\r"), "\n")
+		}
+
+		if rstrip {
+			text = strings.TrimRight(text, jinjaWhitespace)
+		}
+
+		lstrip, trimBlock = false, false
+
+		if text != "" {
+			tokens = append(tokens, jinjaToken{kind: jinjaTokenText, value: text, line: lineAt(start)})
+		}
+	}
+
+	for {
+		start := jinjaTagStart(source, pos)
+		if start < 0 {
+			emitText(pos, source[pos:], false)
+			break
+		}
+
+		rstrip := start+2 < len(source) && source[start+2] == '-'
+		emitText(pos, source[pos:start], rstrip)
+
+		switch source[start+1] {
+		case '#':
+			end := strings.Index(source[start+2:], "#}")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: missing end of comment tag", lineAt(start))
+			}
+			end += start + 2
+
+			lstrip = end > start+2 && source[end-1] == '-'
+			trimBlock = end == start+2 || source[end-1] != '+'
+			pos = end + 2
+
+			continue
+		case '%':
+			if match := jinjaRaw.FindStringSubmatch(source[start:]); match != nil {
+				lstrip = match[1] == "-"
+				trimBlock = match[1] != "+"
+
+				body := start + len(match[0])
+				loc := jinjaEndRaw.FindStringSubmatchIndex(source[body:])
+				if loc == nil {
+					return nil, fmt.Errorf("line %d: missing endraw tag", lineAt(start))
+				}
+
+				emitText(body, source[body:body+loc[0]], source[body+loc[2]:body+loc[3]] == "-")
+
+				lstrip = source[body+loc[4]:body+loc[5]] == "-"
+				trimBlock = source[body+loc[4]:body+loc[5]] != "+"
+				pos = body + loc[1]
+
+				continue
+			}
+		}
+
+		begin, end, block := jinjaTokenVariableBegin, "}}", false
+		if source[start+1] == '%' {
+			begin, end, block = jinjaTokenBlockBegin, "%}", true
+		}
+
+		tokens = append(tokens, jinjaToken{kind: begin, line: lineAt(start)})
+
+		pos = start + 2
+		// NOTE: `{%+` only disables `lstrip_blocks`, which cloud-init doesn't enable
+		if rstrip || (block && pos < len(source) && source[pos] == '+') {
+			pos++
+		}
+
+		var modifier byte
+		var err error
+		tokens, pos, modifier, err = jinjaLexExpression(tokens, source, pos, end, lineAt(pos))
+		if err != nil {
+			return nil, err
+		}
+
+		lstrip = modifier == '-'
+		trimBlock = block && modifier != '+'
+	}
+
+	tokens = append(tokens, jinjaToken{kind: jinjaTokenEOF, line: lineAt(len(source))})
+
+	return tokens, nil
+}
+
+// jinjaTagStart
+// Position of the next `{{`, `{%` or `{#`, -1 if there is none
+func jinjaTagStart(source string, pos int) int {
+	for {
+		index := strings.IndexByte(source[pos:], '{')
+		if index < 0 || pos+index+1 >= len(source) {
+			return -1
+		}
+
+		pos += index
+		switch source[pos+1] {
+		case '{', '%', '#':
+			return pos
+		}
+		pos++
+	}
+}
+
+// jinjaLexExpression
+// Tokenizes the inside of a tag until `end` delimiter, which is only matched outside of brackets.
+// Returns `-` or `+` modifier of the delimiter, if any
+func jinjaLexExpression(tokens []jinjaToken, source string, pos int, end string, line int) ([]jinjaToken, int, byte, error) {
+	depth := 0
+
+	for {
+		for pos < len(source) && strings.IndexByte(jinjaWhitespace, source[pos]) >= 0 {
+			if source[pos] == '\n' {
+				line++
+			}
+			pos++
+		}
+
+		if pos >= len(source) {
+			return nil, pos, 0, fmt.Errorf("line %d: unexpected end of template, expected '%s'", line, end)
+		}
+
+		rest := source[pos:]
+
+		if depth == 0 {
+			if strings.HasPrefix(rest, "-"+end) || (end == "%}" && strings.HasPrefix(rest, "+"+end)) {
+				return append(tokens, jinjaToken{kind: jinjaEndKind(end), line: line}), pos + 3, rest[0], nil
+			}
+			if strings.HasPrefix(rest, end) {
+				return append(tokens, jinjaToken{kind: jinjaEndKind(end), line: line}), pos + 2, 0, nil
+			}
+		}
+
+		c := rest[0]
+
+		switch {
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			n := 1
+			for n < len(rest) && (rest[n] == '_' || (rest[n] >= 'a' && rest[n] <= 'z') || (rest[n] >= 'A' && rest[n] <= 'Z') || (rest[n] >= '0' && rest[n] <= '9')) {
+				n++
+			}
+			tokens = append(tokens, jinjaToken{kind: jinjaTokenName, value: rest[:n], line: line})
+			pos += n
+		case c >= '0' && c <= '9':
+			n := 1
+			for n < len(rest) && ((rest[n] >= '0' && rest[n] <= '9') || rest[n] == '_') {
+				n++
+			}
+			kind := jinjaTokenInteger
+			if n+1 < len(rest) && rest[n] == '.' && rest[n+1] >= '0' && rest[n+1] <= '9' {
+				kind = jinjaTokenFloat
+				n++
+				for n < len(rest) && ((rest[n] >= '0' && rest[n] <= '9') || rest[n] == '_') {
+					n++
+				}
+			}
+			if exponent := jinjaExponent.FindString(rest[n:]); exponent != "" {
+				kind = jinjaTokenFloat
+				n += len(exponent)
+			}
+			tokens = append(tokens, jinjaToken{kind: kind, value: strings.ReplaceAll(rest[:n], "_", ""), line: line})
+			pos += n
+		case c == '\'' || c == '"':
+			value, n, err := jinjaLexString(rest)
+			if err != nil {
+				return nil, pos, 0, fmt.Errorf("line %d: %w", line, err)
+			}
+			tokens = append(tokens, jinjaToken{kind: jinjaTokenString, value: value, line: line})
+			line += strings.Count(rest[:n], "\n")
+			pos += n
+		default:
+			operator := ""
+			for _, candidate := range jinjaOperators {
+				if strings.HasPrefix(rest, candidate) {
+					operator = candidate
+					break
+				}
+			}
+
+			if operator == "" {
+				return nil, pos, 0, fmt.Errorf("line %d: unexpected char %q", line, c)
+			}
+
+			switch operator {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			}
+
+			tokens = append(tokens, jinjaToken{kind: jinjaTokenOperator, value: operator, line: line})
+			pos += len(operator)
+		}
+	}
+}
+
+func jinjaEndKind(end string) jinjaTokenKind {
+	if end == "%}" {
+		return jinjaTokenBlockEnd
+	}
+
+	return jinjaTokenVariableEnd
+}
+
+// jinjaLexString
+// Reads a quoted string literal, returns its value and length in the source
+func jinjaLexString(source string) (string, int, error) {
+	quote := source[0]
+
+	var value strings.Builder
+
+	for n := 1; n < len(source); n++ {
+		switch source[n] {
+		case quote:
+			return value.String(), n + 1, nil
+		case '\\':
+			n++
+			if n >= len(source) {
+				break
+			}
+
+			switch source[n] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case 'r':
+				value.WriteByte('\r')
+			case '\\', '\'', '"':
+				value.WriteByte(source[n])
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(source[n])
+			}
+		default:
+			value.WriteByte(source[n])
+		}
+	}
+
+	return "", len(source), fmt.Errorf("unterminated string")
+}
+
+// Nodes of the template
+
+type jinjaNode interface{}
+
+type jinjaTextNode struct {
+	text string
+}
+
+type jinjaOutputNode struct {
+	expression jinjaExpression
+}
+
+type jinjaIfBranch struct {
+	condition jinjaExpression
+	body      []jinjaNode
+}
+
+type jinjaIfNode struct {
+	branches []jinjaIfBranch
+	orElse   []jinjaNode
+}
+
+type jinjaForNode struct {
+	targets   []string
+	iterable  jinjaExpression
+	condition jinjaExpression
+	body      []jinjaNode
+	orElse    []jinjaNode
+}
+
+type jinjaSetNode struct {
+	targets []jinjaExpression
+	value   jinjaExpression
+	body    []jinjaNode
+}
+
+type jinjaDoNode struct {
+	expression jinjaExpression
+}
+
+// Nodes of expressions
+
+type jinjaExpression interface{}
+
+type jinjaLiteral struct {
+	value any
+}
+
+type jinjaVariable struct {
+	name string
+}
+
+type jinjaGetAttr struct {
+	object jinjaExpression
+	name   string
+}
+
+type jinjaGetItem struct {
+	object jinjaExpression
+	key    jinjaExpression
+}
+
+type jinjaSlice struct {
+	object      jinjaExpression
+	start, stop jinjaExpression
+}
+
+type jinjaArguments struct {
+	positional []jinjaExpression
+	keywords   map[string]jinjaExpression
+}
+
+type jinjaCall struct {
+	function jinjaExpression
+	jinjaArguments
+}
+
+type jinjaFilter struct {
+	value jinjaExpression
+	name  string
+	jinjaArguments
+}
+
+type jinjaTest struct {
+	value   jinjaExpression
+	name    string
+	negated bool
+	jinjaArguments
+}
+
+type jinjaUnary struct {
+	operator string
+	operand  jinjaExpression
+}
+
+type jinjaBinary struct {
+	operator    string
+	left, right jinjaExpression
+}
+
+type jinjaCompare struct {
+	left      jinjaExpression
+	operators []string
+	rights    []jinjaExpression
+}
+
+type jinjaConditional struct {
+	condition, then, orElse jinjaExpression
+}
+
+type jinjaList struct {
+	items []jinjaExpression
+}
+
+type jinjaTuple struct {
+	items []jinjaExpression
+}
+
+type jinjaDict struct {
+	keys, values []jinjaExpression
+}
+
+type jinjaParser struct {
+	tokens []jinjaToken
+	pos    int
+}
+
+func (p *jinjaParser) peek() jinjaToken {
+	return p.tokens[p.pos]
+}
+
+func (p *jinjaParser) next() jinjaToken {
+	token := p.tokens[p.pos]
+	if token.kind != jinjaTokenEOF {
+		p.pos++
+	}
+
+	return token
+}
+
+func (p *jinjaParser) isOperator(operator string) bool {
+	token := p.peek()
+	return token.kind == jinjaTokenOperator && token.value == operator
+}
+
+func (p *jinjaParser) isName(name string) bool {
+	token := p.peek()
+	return token.kind == jinjaTokenName && token.value == name
+}
+
+func (p *jinjaParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+func (p *jinjaParser) describe(token jinjaToken) string {
+	switch token.kind {
+	case jinjaTokenVariableEnd:
+		return "'}}'"
+	case jinjaTokenBlockEnd:
+		return "'%}'"
+	case jinjaTokenEOF:
+		return "end of template"
+	}
+
+	return fmt.Sprintf("'%s'", token.value)
+}
+
+func (p *jinjaParser) expectOperator(operator string) error {
+	if !p.isOperator(operator) {
+		return p.errorf("expected '%s', got %s", operator, p.describe(p.peek()))
+	}
+
+	p.next()
+
+	return nil
+}
+
+func (p *jinjaParser) expectKind(kind jinjaTokenKind, expected string) (jinjaToken, error) {
+	token := p.peek()
+	if token.kind != kind {
+		return token, p.errorf("expected %s, got %s", expected, p.describe(token))
+	}
+
+	return p.next(), nil
+}
+
+// parseBody
+// Parses nodes until one of `endTags` statements, which is consumed along with its name
+func (p *jinjaParser) parseBody(endTags ...string) ([]jinjaNode, string, error) {
+	nodes := []jinjaNode{}
+
+	for {
+		token := p.next()
+
+		switch token.kind {
+		case jinjaTokenEOF:
+			if len(endTags) > 0 {
+				return nil, "", fmt.Errorf("line %d: unexpected end of template, expected '%s'", token.line, strings.Join(endTags, "' or '"))
+			}
+			return nodes, "", nil
+		case jinjaTokenText:
+			nodes = append(nodes, &jinjaTextNode{text: token.value})
+		case jinjaTokenVariableBegin:
+			expression, err := p.parseExpression()
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := p.expectKind(jinjaTokenVariableEnd, "'}}'"); err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, &jinjaOutputNode{expression: expression})
+		case jinjaTokenBlockBegin:
+			name, err := p.expectKind(jinjaTokenName, "statement")
+			if err != nil {
+				return nil, "", err
+			}
+
+			for _, tag := range endTags {
+				if name.value == tag {
+					return nodes, tag, nil
+				}
+			}
+
+			node, err := p.parseStatement(name)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, node)
+		default:
+			return nil, "", fmt.Errorf("line %d: unexpected %s", token.line, p.describe(token))
+		}
+	}
+}
+
+func (p *jinjaParser) parseStatement(name jinjaToken) (jinjaNode, error) {
+	switch name.value {
+	case "if":
+		return p.parseIf()
+	case "for":
+		return p.parseFor()
+	case "set":
+		return p.parseSet()
+	case "do":
+		expression, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectKind(jinjaTokenBlockEnd, "'%}'"); err != nil {
+			return nil, err
+		}
+		return &jinjaDoNode{expression: expression}, nil
+	}
+
+	return nil, fmt.Errorf("line %d: unknown tag '%s'", name.line, name.value)
+}
+
+func (p *jinjaParser) parseIf() (jinjaNode, error) {
+	node := &jinjaIfNode{}
+
+	for {
+		condition, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectKind(jinjaTokenBlockEnd, "'%}'"); err != nil {
+			return nil, err
+		}
+
+		body, tag, err := p.parseBody("elif", "else", "endif")
+		if err != nil {
+			return nil, err
+		}
+
+		node.branches = append(node.branches, jinjaIfBranch{condition: condition, body: body})
+
+		switch tag {
+		case "elif":
+			continue
+		case "else":
+			if _, err := p.expectKind(jinjaTokenBlockEnd, "'%}'"); err != nil {
+				return nil, err
+			}
+			if node.orElse, _, err = p.parseBody("endif"); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = p.expectKind(jinjaTokenBlockEnd, "'%}'")
+
+		return node, err
+	}
+}
+
+func (p *jinjaParser) parseFor() (jinjaNode, error) {
+	node := &jinjaForNode{}
+
+	for {
+		target, err := p.expectKind(jinjaTokenName, "loop variable")
+		if err != nil {
+			return nil, err
+		}
+		node.targets = append(node.targets, target.value)
+
+		if !p.isOperator(",") {
+			break
+		}
+		p.next()
+	}
+
+	if !p.isName("in") {
+		return nil, p.errorf("expected 'in', got %s", p.describe(p.peek()))
+	}
+	p.next()
+
+	var err error
+	if node.iterable, err = p.parseOr(); err != nil {
+		return nil, err
+	}
+
+	if p.isName("if") {
+		p.next()
+		if node.condition, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.expectKind(jinjaTokenBlockEnd, "'%}'"); err != nil {
+		return nil, err
+	}
+
+	body, tag, err := p.parseBody("else", "endfor")
+	if err != nil {
+		return nil, err
+	}
+	node.body = body
+
+	if tag == "else" {
+		if _, err := p.expectKind(jinjaTokenBlockEnd, "'%}'"); err != nil {
+			return nil, err
+		}
+		if node.orElse, _, err = p.parseBody("endfor"); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.expectKind(jinjaTokenBlockEnd, "'%}'")
+
+	return node, err
+}
+
+func (p *jinjaParser) parseSet() (jinjaNode, error) {
+	node := &jinjaSetNode{}
+
+	for {
+		name, err := p.expectKind(jinjaTokenName, "variable name")
+		if err != nil {
+			return nil, err
+		}
+
+		var target jinjaExpression = &jinjaVariable{name: name.value}
+
+		// NOTE: `ns.attribute` of `namespace()`
+		if p.isOperator(".") {
+			p.next()
+			attribute, err := p.expectKind(jinjaTokenName, "attribute name")
+			if err != nil {
+				return nil, err
+			}
+			target = &jinjaGetAttr{object: target, name: attribute.value}
+		}
+
+		node.targets = append(node.targets, target)
+
+		if !p.isOperator(",") {
+			break
+		}
+		p.next()
+	}
+
+	var err error
+
+	if p.isOperator("=") {
+		p.next()
+		if node.value, err = p.parseTuple(); err != nil {
+			return nil, err
+		}
+
+		_, err = p.expectKind(jinjaTokenBlockEnd, "'%}'")
+
+		return node, err
+	}
+
+	if _, err := p.expectKind(jinjaTokenBlockEnd, "'%}'"); err != nil {
+		return nil, err
+	}
+
+	if node.body, _, err = p.parseBody("endset"); err != nil {
+		return nil, err
+	}
+
+	_, err = p.expectKind(jinjaTokenBlockEnd, "'%}'")
+
+	return node, err
+}
+
+// parseTuple
+// Expression, or a tuple of comma separated ones, e.g. `set a, b = 1, 2` or `('a',)`
+func (p *jinjaParser) parseTuple() (jinjaExpression, error) {
+	expression, err := p.parseExpression()
+	if err != nil || !p.isOperator(",") {
+		return expression, err
+	}
+
+	tuple := &jinjaTuple{items: []jinjaExpression{expression}}
+
+	for p.isOperator(",") {
+		p.next()
+
+		if p.isOperator(")") {
+			break
+		}
+
+		item, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		tuple.items = append(tuple.items, item)
+	}
+
+	return tuple, nil
+}
+
+func (p *jinjaParser) parseExpression() (jinjaExpression, error) {
+	expression, err := p.parseOr()
+	if err != nil || !p.isName("if") {
+		return expression, err
+	}
+
+	p.next()
+
+	node := &jinjaConditional{then: expression}
+
+	if node.condition, err = p.parseOr(); err != nil {
+		return nil, err
+	}
+
+	if p.isName("else") {
+		p.next()
+		if node.orElse, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+func (p *jinjaParser) parseOr() (jinjaExpression, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.isName("or") {
+		p.next()
+
+		var right jinjaExpression
+		right, err = p.parseAnd()
+		left = &jinjaBinary{operator: "or", left: left, right: right}
+	}
+
+	return left, err
+}
+
+func (p *jinjaParser) parseAnd() (jinjaExpression, error) {
+	left, err := p.parseNot()
+	for err == nil && p.isName("and") {
+		p.next()
+
+		var right jinjaExpression
+		right, err = p.parseNot()
+		left = &jinjaBinary{operator: "and", left: left, right: right}
+	}
+
+	return left, err
+}
+
+func (p *jinjaParser) parseNot() (jinjaExpression, error) {
+	if p.isName("not") {
+		p.next()
+
+		operand, err := p.parseNot()
+
+		return &jinjaUnary{operator: "not", operand: operand}, err
+	}
+
+	return p.parseCompare()
+}
+
+func (p *jinjaParser) parseCompare() (jinjaExpression, error) {
+	left, err := p.parseMath1()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &jinjaCompare{left: left}
+
+	for {
+		token := p.peek()
+
+		operator := ""
+		switch {
+		case token.kind == jinjaTokenOperator && (token.value == "==" || token.value == "!=" || token.value == "<" || token.value == ">" || token.value == "<=" || token.value == ">="):
+			operator = token.value
+			p.next()
+		case p.isName("in"):
+			operator = "in"
+			p.next()
+		case p.isName("not") && p.tokens[p.pos+1].kind == jinjaTokenName && p.tokens[p.pos+1].value == "in":
+			operator = "not in"
+			p.next()
+			p.next()
+		}
+
+		if operator == "" {
+			break
+		}
+
+		right, err := p.parseMath1()
+		if err != nil {
+			return nil, err
+		}
+
+		node.operators = append(node.operators, operator)
+		node.rights = append(node.rights, right)
+	}
+
+	if len(node.operators) == 0 {
+		return left, nil
+	}
+
+	return node, nil
+}
+
+// parseBinary
+// Left-associative operators of the same precedence
+func (p *jinjaParser) parseBinary(operand func() (jinjaExpression, error), operators ...string) (jinjaExpression, error) {
+	left, err := operand()
+
+	for err == nil {
+		token := p.peek()
+		if token.kind != jinjaTokenOperator || !slices.Contains(operators, token.value) {
+			break
+		}
+
+		p.next()
+
+		var right jinjaExpression
+		right, err = operand()
+		left = &jinjaBinary{operator: token.value, left: left, right: right}
+	}
+
+	return left, err
+}
+
+func (p *jinjaParser) parseMath1() (jinjaExpression, error) {
+	return p.parseBinary(p.parseConcat, "+", "-")
+}
+
+func (p *jinjaParser) parseConcat() (jinjaExpression, error) {
+	return p.parseBinary(p.parseMath2, "~")
+}
+
+func (p *jinjaParser) parseMath2() (jinjaExpression, error) {
+	return p.parseBinary(p.parsePow, "*", "/", "//", "%")
+}
+
+func (p *jinjaParser) parsePow() (jinjaExpression, error) {
+	return p.parseBinary(func() (jinjaExpression, error) { return p.parseUnary(true) }, "**")
+}
+
+func (p *jinjaParser) parseUnary(withFilter bool) (jinjaExpression, error) {
+	var node jinjaExpression
+	var err error
+
+	if p.isOperator("-") || p.isOperator("+") {
+		operator := p.next().value
+
+		operand, err := p.parseUnary(false)
+		if err != nil {
+			return nil, err
+		}
+
+		node = &jinjaUnary{operator: operator, operand: operand}
+	} else if node, err = p.parsePrimary(); err != nil {
+		return nil, err
+	}
+
+	if node, err = p.parsePostfix(node); err != nil {
+		return nil, err
+	}
+
+	if withFilter {
+		return p.parseFilters(node)
+	}
+
+	return node, nil
+}
+
+func (p *jinjaParser) parsePrimary() (jinjaExpression, error) {
+	token := p.next()
+
+	switch token.kind {
+	case jinjaTokenName:
+		switch token.value {
+		case "true", "True":
+			return &jinjaLiteral{value: true}, nil
+		case "false", "False":
+			return &jinjaLiteral{value: false}, nil
+		case "none", "None":
+			return &jinjaLiteral{value: nil}, nil
+		}
+		return &jinjaVariable{name: token.value}, nil
+	case jinjaTokenString:
+		value := token.value
+		// NOTE: adjacent literals are concatenated, same as in Python
+		for p.peek().kind == jinjaTokenString {
+			value += p.next().value
+		}
+		return &jinjaLiteral{value: value}, nil
+	case jinjaTokenInteger:
+		value, err := strconv.ParseInt(token.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", token.line, err)
+		}
+		return &jinjaLiteral{value: value}, nil
+	case jinjaTokenFloat:
+		value, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", token.line, err)
+		}
+		return &jinjaLiteral{value: value}, nil
+	case jinjaTokenOperator:
+		switch token.value {
+		case "(":
+			if p.isOperator(")") {
+				p.next()
+				return &jinjaTuple{}, nil
+			}
+
+			expression, err := p.parseTuple()
+			if err != nil {
+				return nil, err
+			}
+
+			return expression, p.expectOperator(")")
+		case "[":
+			items, err := p.parseItems("]")
+			return &jinjaList{items: items}, err
+		case "{":
+			node := &jinjaDict{}
+			for !p.isOperator("}") {
+				key, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectOperator(":"); err != nil {
+					return nil, err
+				}
+				value, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+
+				node.keys = append(node.keys, key)
+				node.values = append(node.values, value)
+
+				if !p.isOperator(",") {
+					break
+				}
+				p.next()
+			}
+			return node, p.expectOperator("}")
+		}
+	}
+
+	return nil, fmt.Errorf("line %d: unexpected %s", token.line, p.describe(token))
+}
+
+// parseItems
+// Comma separated expressions until `end` operator, a trailing comma is allowed
+func (p *jinjaParser) parseItems(end string) ([]jinjaExpression, error) {
+	items := []jinjaExpression{}
+
+	for !p.isOperator(end) {
+		item, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if !p.isOperator(",") {
+			break
+		}
+		p.next()
+	}
+
+	return items, p.expectOperator(end)
+}
+
+func (p *jinjaParser) parsePostfix(node jinjaExpression) (jinjaExpression, error) {
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+
+			token := p.next()
+			switch token.kind {
+			case jinjaTokenName:
+				node = &jinjaGetAttr{object: node, name: token.value}
+			case jinjaTokenInteger:
+				index, _ := strconv.ParseInt(token.value, 10, 64)
+				node = &jinjaGetItem{object: node, key: &jinjaLiteral{value: index}}
+			default:
+				return nil, fmt.Errorf("line %d: expected attribute name, got %s", token.line, p.describe(token))
+			}
+		case p.isOperator("["):
+			p.next()
+
+			var start, stop jinjaExpression
+			var err error
+
+			if !p.isOperator(":") {
+				if start, err = p.parseExpression(); err != nil {
+					return nil, err
+				}
+			}
+
+			if !p.isOperator(":") {
+				node = &jinjaGetItem{object: node, key: start}
+			} else {
+				p.next()
+				if !p.isOperator("]") {
+					if stop, err = p.parseExpression(); err != nil {
+						return nil, err
+					}
+				}
+				node = &jinjaSlice{object: node, start: start, stop: stop}
+			}
+
+			if err := p.expectOperator("]"); err != nil {
+				return nil, err
+			}
+		case p.isOperator("("):
+			p.next()
+
+			arguments, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+
+			node = &jinjaCall{function: node, jinjaArguments: arguments}
+		default:
+			return node, nil
+		}
+	}
+}
+
+// parseArguments
+// Positional and keyword arguments of a call, after the opening parenthesis
+func (p *jinjaParser) parseArguments() (jinjaArguments, error) {
+	arguments := jinjaArguments{keywords: map[string]jinjaExpression{}}
+
+	for !p.isOperator(")") {
+		token := p.peek()
+		if token.kind == jinjaTokenName && p.tokens[p.pos+1].kind == jinjaTokenOperator && p.tokens[p.pos+1].value == "=" {
+			p.next()
+			p.next()
+
+			value, err := p.parseExpression()
+			if err != nil {
+				return arguments, err
+			}
+			arguments.keywords[token.value] = value
+		} else {
+			value, err := p.parseExpression()
+			if err != nil {
+				return arguments, err
+			}
+			arguments.positional = append(arguments.positional, value)
+		}
+
+		if !p.isOperator(",") {
+			break
+		}
+		p.next()
+	}
+
+	return arguments, p.expectOperator(")")
+}
+
+func (p *jinjaParser) parseFilters(node jinjaExpression) (jinjaExpression, error) {
+	for {
+		switch {
+		case p.isOperator("|"):
+			p.next()
+
+			name, err := p.expectKind(jinjaTokenName, "filter name")
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := jinjaFilters[name.value]; !ok {
+				return nil, fmt.Errorf("line %d: no filter named '%s'", name.line, name.value)
+			}
+
+			filter := &jinjaFilter{value: node, name: name.value, jinjaArguments: jinjaArguments{keywords: map[string]jinjaExpression{}}}
+
+			if p.isOperator("(") {
+				p.next()
+				if filter.jinjaArguments, err = p.parseArguments(); err != nil {
+					return nil, err
+				}
+			}
+
+			node = filter
+		case p.isName("is"):
+			p.next()
+
+			test := &jinjaTest{value: node, jinjaArguments: jinjaArguments{keywords: map[string]jinjaExpression{}}}
+
+			if p.isName("not") {
+				p.next()
+				test.negated = true
+			}
+
+			name := p.next()
+			switch {
+			case name.kind == jinjaTokenName:
+				test.name = name.value
+			case name.kind == jinjaTokenOperator && slices.Contains([]string{"==", "!=", "<", ">", "<=", ">="}, name.value):
+				test.name = name.value
+			default:
+				return nil, fmt.Errorf("line %d: expected test name, got %s", name.line, p.describe(name))
+			}
+
+			// NOTE: literals are parsed as names
+			switch test.name {
+			case "none", "None":
+				test.name = "none"
+			case "true", "True":
+				test.name = "true"
+			case "false", "False":
+				test.name = "false"
+			}
+
+			if _, ok := jinjaTests[test.name]; !ok {
+				return nil, fmt.Errorf("line %d: no test named '%s'", name.line, test.name)
+			}
+
+			var err error
+
+			// NOTE: a single argument can go without parentheses, e.g. `is divisibleby 3`
+			switch next := p.peek(); {
+			case next.kind == jinjaTokenOperator && next.value == "(":
+				p.next()
+				if test.jinjaArguments, err = p.parseArguments(); err != nil {
+					return nil, err
+				}
+			case next.kind == jinjaTokenString || next.kind == jinjaTokenInteger || next.kind == jinjaTokenFloat:
+				argument, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				test.positional = []jinjaExpression{argument}
+			case next.kind == jinjaTokenName && !slices.Contains([]string{"and", "or", "if", "else", "in", "not", "is"}, next.value):
+				argument, err := p.parseUnary(false)
+				if err != nil {
+					return nil, err
+				}
+				test.positional = []jinjaExpression{argument}
+			}
+
+			node = test
+		default:
+			return node, nil
+		}
+	}
+}
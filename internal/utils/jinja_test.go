@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"testing"
+)
+
+type jinjaTestCase struct {
+	name      string
+	template  string
+	variables map[string]any
+	expected  string
+}
+
+func testJinja(t *testing.T, testCases []jinjaTestCase) {
+	t.Helper()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := Jinja(tc.template, tc.variables)
+			if err != nil {
+				t.Fatalf("Jinja: %v", err)
+			}
+
+			if rendered != tc.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tc.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestJinjaExpressions(t *testing.T) {
+	variables := map[string]any{
+		"v1": map[string]any{
+			"region":        "us-east-1",
+			"instance_id":   "i-123",
+			"public_keys":   []any{"ssh-rsa a", "ssh-rsa b"},
+			"cpu_count":     int64(4),
+			"not_a_default": nil,
+		},
+		"name": "web",
+	}
+
+	testJinja(t, []jinjaTestCase{
+		{name: "attribute", template: "{{ v1.region }}", variables: variables, expected: "us-east-1"},
+		{name: "item", template: "{{ v1['instance_id'] }}", variables: variables, expected: "i-123"},
+		{name: "index", template: "{{ v1.public_keys[1] }}", variables: variables, expected: "ssh-rsa b"},
+		{name: "dotted index", template: "{{ v1.public_keys.0 }}", variables: variables, expected: "ssh-rsa a"},
+		{name: "slice", template: "{{ 'abcdef'[1:3] }}", expected: "bc"},
+		{name: "undefined", template: "{{ missing }}", expected: "CI_MISSING_JINJA_VAR/missing"},
+		{name: "none", template: "{{ v1.not_a_default }}", variables: variables, expected: "None"},
+		{name: "integer arithmetic", template: "{{ 7 // 2 }} {{ -7 // 2 }} {{ 7 % 3 }} {{ 2 ** 10 }}", expected: "3 -4 1 1024"},
+		{name: "float arithmetic", template: "{{ 7 / 2 }} {{ 4 / 2 }} {{ 1.5 * 2 }}", expected: "3.5 2.0 3.0"},
+		{name: "precedence", template: "{{ 1 + 2 * 3 }} {{ (1 + 2) * 3 }}", expected: "7 9"},
+		{name: "concat", template: "{{ name ~ '-' ~ v1.cpu_count }}", variables: variables, expected: "web-4"},
+		{name: "repeat", template: "{{ '-' * 3 }}", expected: "---"},
+		{name: "comparison", template: "{{ 1 < 2 < 3 }} {{ 'a' in 'abc' }} {{ 4 not in [1, 2] }}", expected: "True True True"},
+		{name: "logic", template: "{{ 0 or 'x' }} {{ 1 and 'y' }} {{ not 0 }}", expected: "x y True"},
+		{name: "conditional", template: "{{ 'big' if v1.cpu_count > 2 else 'small' }}", variables: variables, expected: "big"},
+		{name: "conditional without else", template: "{{ 1 if missing }}", expected: "CI_MISSING_JINJA_VAR/None"},
+		{name: "big integers", template: "{{ 2 ** 62 }} {{ 3 ** 39 }} {{ (-2) ** 63 }} {{ 1 ** 1000000000000 }}", expected: "4611686018427387904 4052555153018976267 -9223372036854775808 1"},
+		{name: "list", template: "{{ [1, 'a', none, true] }}", expected: "[1, 'a', None, True]"},
+		{name: "dict", template: "{{ {'a': 1}['a'] }}", expected: "1"},
+		{name: "tuple", template: "{{ (1, 2)[1] }} {{ ('a',) | length }}", expected: "2 1"},
+		{name: "string formatting", template: "{{ '%s-%d' % ('a', 2) }}", expected: "a-2"},
+		{name: "string formatting of a single argument", template: "{{ 'host-%s' % name }}", variables: variables, expected: "host-web"},
+		{name: "string formatting of a list", template: "{{ '%s' % [1, 2] }}", expected: "[1, 2]"},
+		{name: "string formatting of a mapping", template: "{{ '%(a)s/%(b)03d' % {'a': 'x', 'b': 7} }}", expected: "x/007"},
+		{name: "string formatting flags", template: "{{ '[%-4s|%4s|%.2f|%x|%#o|%r|%%]' % ('a', 'b', 3.14159, 255, 8, 'c') }}", expected: "[a   |   b|3.14|ff|0o10|'c'|%]"},
+		{name: "test", template: "{{ v1.cpu_count is divisibleby 2 }} {{ missing is defined }} {{ name is not none }}", variables: variables, expected: "True False True"},
+		{name: "string method", template: "{{ name.upper() }} {{ 'a,b'.split(',') }}", variables: variables, expected: "WEB ['a', 'b']"},
+		{name: "if", template: "{% if v1.cpu_count > 8 %}big{% elif v1.cpu_count > 2 %}medium{% else %}small{% endif %}", variables: variables, expected: "medium"},
+		{name: "for", template: "{% for key in v1.public_keys %}{{ loop.index }}:{{ key }};{% endfor %}", variables: variables, expected: "1:ssh-rsa a;2:ssh-rsa b;"},
+		{name: "for else", template: "{% for item in [] %}{{ item }}{% else %}empty{% endfor %}", expected: "empty"},
+		{name: "for unpacking", template: "{% for k, v in {'a': 1}.items() %}{{ k }}={{ v }}{% endfor %}", expected: "a=1"},
+		{name: "set", template: "{% set a, b = 1, 2 %}{{ a + b }}", expected: "3"},
+		{name: "set block", template: "{% set greeting %}hi {{ name }}{% endset %}{{ greeting }}", variables: variables, expected: "hi web"},
+		{name: "namespace", template: "{% set ns = namespace(total=0) %}{% for i in range(4) %}{% set ns.total = ns.total + i %}{% endfor %}{{ ns.total }}", expected: "6"},
+		{name: "do append", template: "{% set keys = ['a'] %}{% do keys.append('b') %}{% do keys.extend(['c', 'd']) %}{{ keys }}", expected: "['a', 'b', 'c', 'd']"},
+		{name: "do append to an attribute", template: "{% do v1.public_keys.append('ssh-rsa c') %}{{ v1.public_keys | length }}", variables: map[string]any{"v1": map[string]any{"public_keys": []any{"ssh-rsa a"}}}, expected: "2"},
+		{name: "do append in a loop", template: "{% set ns = namespace(found=[]) %}{% for i in range(3) %}{% do ns.found.append(i * 2) %}{% endfor %}{{ ns.found }}", expected: "[0, 2, 4]"},
+		{name: "do append to an item", template: "{% set d = {'k': []} %}{% do d['k'].append(1) %}{{ d }}", expected: "{'k': [1]}"},
+		{name: "do update", template: "{% set d = {'a': 1} %}{% do d.update({'b': 2}, c=3) %}{{ d }}", expected: "{'a': 1, 'b': 2, 'c': 3}"},
+		{name: "append renders None", template: "{% set keys = [] %}{{ keys.append(1) }} {{ keys }}", expected: "None [1]"},
+		{name: "comment", template: "a{# ignored #}b", expected: "ab"},
+		{name: "raw", template: "{% raw %}{{ v1.region }}{% endraw %}", expected: "{{ v1.region }}"},
+	})
+}
+
+func TestJinjaWhitespaceControl(t *testing.T) {
+	testJinja(t, []jinjaTestCase{
+		{name: "trim blocks", template: "{% if true %}\na\n{% endif %}\nb", expected: "a\nb"},
+		{name: "trim comments", template: "{# comment #}\na", expected: "a"},
+		{name: "keep newline after expressions", template: "{{ 1 }}\n{{ 2 }}", expected: "1\n2"},
+		{name: "strip before", template: "a  \n  {{- 1 }}", expected: "a1"},
+		{name: "strip after", template: "{{ 1 -}}  \n  b", expected: "1b"},
+		{name: "strip blocks", template: "a\n  {%- if true -%}\n  b\n  {%- endif %}", expected: "ab"},
+		{name: "strip comments", template: "a \n{#- comment -#}\n b", expected: "ab"},
+		{name: "keep newline of a block", template: "{% if true +%}\na{% endif %}", expected: "\na"},
+		{name: "keep newline of a comment", template: "{# comment +#}\na", expected: "\na"},
+		{name: "lstrip disabled", template: "  {%+ if true %}a{% endif %}", expected: "  a"},
+		{name: "raw strip", template: "a \n{%- raw -%}\n {{ x }} \n{%- endraw -%}\n b", expected: "a{{ x }}b"},
+		{name: "raw keep newline", template: "{% raw +%}\nx{% endraw %}", expected: "\nx"},
+		{name: "plus operator", template: "{% set a = 1 + 2 %}{{ a }}", expected: "3"},
+	})
+}
+
+func TestJinjaErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "unknown tag", template: "a\n{% macro x() %}", expected: "line 2: unknown tag 'macro'"},
+		{name: "unclosed block", template: "{% if true %}\n\na", expected: "line 3: unexpected end of template, expected 'elif' or 'else' or 'endif'"},
+		{name: "unclosed expression", template: "\n{{ 1 +", expected: "line 2: unexpected end of template, expected '}}'"},
+		{name: "unclosed comment", template: "a\n{# comment", expected: "line 2: missing end of comment tag"},
+		{name: "missing endraw", template: "{% raw %}\na", expected: "line 1: missing endraw tag"},
+		{name: "unexpected token", template: "\n\n{{ 1 2 }}", expected: "line 3: expected '}}', got '2'"},
+		{name: "unexpected char", template: "{{ a ? b }}", expected: "line 1: unexpected char '?'"},
+		{name: "unknown filter", template: "\n{{ 1 | nope }}", expected: "line 2: no filter named 'nope'"},
+		{name: "unknown test", template: "{{ 1 is nope }}", expected: "line 1: no test named 'nope'"},
+		{name: "unterminated string", template: "\n{{ 'a }}", expected: "line 2: unterminated string"},
+		{name: "undefined arithmetic", template: "{{ missing + 1 }}", expected: "'missing' is undefined"},
+		{name: "type mismatch", template: "{{ 'a' + 1 }}", expected: "unsupported operand type(s) for +: 'str' and 'int'"},
+		{name: "division by zero", template: "{{ 1 // 0 }}", expected: "integer division or modulo by zero"},
+		{name: "power overflow", template: "{{ 2 ** 63 }}", expected: "integer overflow, results beyond 64 bits are not supported"},
+		{name: "large power overflow", template: "{{ 10 ** 100 }}", expected: "integer overflow, results beyond 64 bits are not supported"},
+		{name: "huge exponent", template: "{{ 2 ** 9223372036854775807 }}", expected: "integer overflow, results beyond 64 bits are not supported"},
+		{name: "addition overflow", template: "{{ 9223372036854775807 + 1 }}", expected: "integer overflow, results beyond 64 bits are not supported"},
+		{name: "subtraction overflow", template: "{{ -9223372036854775807 - 2 }}", expected: "integer overflow, results beyond 64 bits are not supported"},
+		{name: "multiplication overflow", template: "{{ 4294967296 * 4294967296 }}", expected: "integer overflow, results beyond 64 bits are not supported"},
+		{name: "append arguments", template: "{% set keys = [] %}{% do keys.append() %}", expected: "append() takes exactly one argument (0 given)"},
+		{name: "undefined method", template: "{% do missing.append(1) %}", expected: "'missing' is undefined"},
+		{name: "not enough arguments", template: "{{ '%s %s' % ('a',) }}", expected: "not enough arguments for format string"},
+		{name: "too many arguments", template: "{{ '%s' % ('a', 'b') }}", expected: "not all arguments converted during string formatting"},
+		{name: "number required", template: "{{ '%d' % 'a' }}", expected: "%d format: a real number is required, not str"},
+		{name: "filter error", template: "{{ 'a' | int(base='x') }}", expected: "filter 'int': expected an integer, got 'str'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Jinja(tc.template, nil)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tc.expected)
+			}
+
+			if err.Error() != tc.expected {
+				t.Errorf("expected error:\n%s\ngot:\n%s", tc.expected, err)
+			}
+		})
+	}
+}
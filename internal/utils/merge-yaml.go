@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeOptions
+// How `MergeYAML` resolves values present on both sides
+type MergeOptions struct {
+	KeepDst      bool // conflicting values are resolved in favour of `dst`
+	KeepSrc      bool // conflicting values are resolved in favour of `src`
+	AppendLists  bool // items of `src` lists go after the items of `dst` lists
+	PrependLists bool // items of `src` lists go before the items of `dst` lists
+	UniqueItems  bool // items of `src` lists already present in `dst` lists are skipped
+}
+
+// MergeConflictError
+// Both sides set the same key to different values
+type MergeConflictError struct {
+	Path string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("conflicting values of `%s`", e.Path)
+}
+
+// MergeYAML
+// Deep-merges `src` into `dst` in place. Mappings are merged key by key, new keys are
+// added after the existing ones, so the order of `dst` is preserved
+func MergeYAML(dst *yaml.Node, src *yaml.Node, options MergeOptions) error {
+	return mergeNodes(dst, resolve(src), options, nil)
+}
+
+func mergeNodes(dst *yaml.Node, src *yaml.Node, options MergeOptions, location []string) error {
+	if dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode {
+		// NOTE: empty mappings are encoded as `{}`, which would keep the merged keys inline
+		dst.Style &^= yaml.FlowStyle
+
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+
+			existing := lookup(dst, key.Value)
+			if existing == nil {
+				dst.Content = append(dst.Content, key, value)
+				continue
+			}
+
+			if err := mergeNodes(existing, value, options, append(location, key.Value)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode && (options.AppendLists || options.PrependLists) {
+		items := make([]*yaml.Node, 0, len(src.Content))
+		for _, item := range src.Content {
+			if options.UniqueItems && slices.ContainsFunc(dst.Content, func(existing *yaml.Node) bool { return equalNodes(existing, item) }) {
+				continue
+			}
+			items = append(items, item)
+		}
+
+		dst.Style &^= yaml.FlowStyle
+
+		if options.PrependLists {
+			dst.Content = append(items, dst.Content...)
+		} else {
+			dst.Content = append(dst.Content, items...)
+		}
+
+		return nil
+	}
+
+	if equalNodes(dst, src) {
+		return nil
+	}
+
+	switch {
+	case options.KeepDst:
+		return nil
+	case options.KeepSrc:
+		*dst = *src
+		return nil
+	}
+
+	return &MergeConflictError{Path: strings.Join(location, ".")}
+}
+
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func equalNodes(a *yaml.Node, b *yaml.Node) bool {
+	var left, right any
+
+	if err := a.Decode(&left); err != nil {
+		return false
+	}
+
+	if err := b.Decode(&right); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// resolve
+// Copy of the node with aliases replaced by the nodes they point to,
+// as anchors might not end up in the merged document.
+// Flow style is dropped to match the rest of the document
+func resolve(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		return resolve(node.Alias)
+	}
+
+	result := *node
+	result.Anchor = ""
+	result.Style &^= yaml.FlowStyle
+	result.Content = make([]*yaml.Node, len(node.Content))
+
+	for i, child := range node.Content {
+		result.Content[i] = resolve(child)
+	}
+
+	return &result
+}
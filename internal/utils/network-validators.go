@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// CIDR
+// Validates that a string is an IPv4 or IPv6 address with prefix length, e.g. `192.168.1.10/24`.
+// Host bits may be set, as addresses of interfaces are written this way
+func CIDR() validator.String {
+	return networkValidator{
+		description: "value must be an IP address with prefix length, e.g. `192.168.1.10/24`",
+		valid: func(value string) bool {
+			_, err := netip.ParsePrefix(value)
+			return err == nil
+		},
+	}
+}
+
+// IPAddress
+// Validates that a string is an IPv4 or IPv6 address
+func IPAddress() validator.String {
+	return networkValidator{
+		description: "value must be an IP address",
+		valid: func(value string) bool {
+			_, err := netip.ParseAddr(value)
+			return err == nil
+		},
+	}
+}
+
+// IPv4Address
+// Validates that a string is an IPv4 address
+func IPv4Address() validator.String {
+	return networkValidator{
+		description: "value must be an IPv4 address",
+		valid: func(value string) bool {
+			addr, err := netip.ParseAddr(value)
+			return err == nil && addr.Is4()
+		},
+	}
+}
+
+// IPv6Address
+// Validates that a string is an IPv6 address
+func IPv6Address() validator.String {
+	return networkValidator{
+		description: "value must be an IPv6 address",
+		valid: func(value string) bool {
+			addr, err := netip.ParseAddr(value)
+			return err == nil && addr.Is6()
+		},
+	}
+}
+
+// MACAddress
+// Validates that a string is a colon-separated MAC address, e.g. `52:54:00:12:34:56`
+func MACAddress() validator.String {
+	return networkValidator{
+		description: "value must be a colon-separated MAC address, e.g. `52:54:00:12:34:56`",
+		valid: func(value string) bool {
+			mac, err := net.ParseMAC(value)
+			return err == nil && strings.Count(value, ":") == len(mac)-1
+		},
+	}
+}
+
+// networkValidator implements the validator.
+type networkValidator struct {
+	description string
+	valid       func(value string) bool
+}
+
+// Description returns a human-readable description of the validator.
+func (v networkValidator) Description(_ context.Context) string {
+	return v.description
+}
+
+// MarkdownDescription returns a markdown description of the validator.
+func (v networkValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString implements the validation logic.
+func (v networkValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !v.valid(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+		)
+	}
+}
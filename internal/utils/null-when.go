@@ -3,7 +3,6 @@ package utils
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -15,15 +14,12 @@ import (
 //	https://github.com/opa-oz/terraform-provider-cloud-config/actions/runs/17017998097/job/48243130914
 //
 // @see https://github.com/hashicorp/terraform-plugin-framework/issues/603
-func NullWhen(path path.Path) planmodifier.Object {
-	return nullWhen{
-		path: path,
-	}
+func NullWhen() planmodifier.Object {
+	return nullWhen{}
 }
 
 // nullWhen implements the plan modifier.
 type nullWhen struct {
-	path path.Path
 }
 
 // Description returns a human-readable description of the plan modifier.
@@ -39,19 +35,12 @@ func (m nullWhen) MarkdownDescription(ctx context.Context) string {
 // PlanModifyObject implements the plan modification logic.
 func (m nullWhen) PlanModifyObject(ctx context.Context, req planmodifier.ObjectRequest, resp *planmodifier.ObjectResponse) {
 	if !req.PlanValue.IsUnknown() && (req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull()) {
-
-		other := &types.Object{}
-		ds := req.Plan.GetAttribute(ctx, m.path, other)
-		resp.Diagnostics.Append(ds...)
-
-		if ds.HasError() {
-			return
-		}
-
-		if other.IsNull() || other.IsUnknown() {
+		// NOTE: `req.PlanValue` is used rather than an absolute path,
+		// so the same block can be nested anywhere in the schema
+		if req.PlanValue.IsNull() {
 			return
 		}
 
-		resp.PlanValue = types.ObjectNull(other.AttributeTypes(ctx))
+		resp.PlanValue = types.ObjectNull(req.PlanValue.AttributeTypes(ctx))
 	}
 }
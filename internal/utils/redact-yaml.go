@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactYAML
+// Replaces scalar values found at `paths` with `replacement` in place, reports whether anything was replaced.
+// Path is a dot-separated list of keys, `*` matches every item of a list
+func RedactYAML(node *yaml.Node, paths []string, replacement string) bool {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	redacted := false
+
+	for _, path := range paths {
+		if redactNode(node, strings.Split(path, "."), replacement) {
+			redacted = true
+		}
+	}
+
+	return redacted
+}
+
+func redactNode(node *yaml.Node, path []string, replacement string) bool {
+	if len(path) == 0 {
+		if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+			return false
+		}
+
+		node.Value = replacement
+		node.Tag = "!!str"
+		node.Style = 0
+
+		return true
+	}
+
+	redacted := false
+
+	switch {
+	case node.Kind == yaml.SequenceNode && path[0] == "*":
+		for _, item := range node.Content {
+			if redactNode(item, path[1:], replacement) {
+				redacted = true
+			}
+		}
+	case node.Kind == yaml.MappingNode:
+		if value := lookup(node, path[0]); value != nil {
+			redacted = redactNode(value, path[1:], replacement)
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"strings"
+)
+
+// cryptAlphabet
+// Base64 alphabet of crypt(3), also the set of characters allowed in salts
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const shaCryptSaltLength = 16
+
+// Order in which bytes of the final digest are encoded, three bytes per four characters
+var (
+	sha256CryptOrder = [][]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+		{-1, 31, 30},
+	}
+	sha512CryptOrder = [][]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41}, {-1, -1, 63},
+	}
+)
+
+// SHA256Crypt
+// `$5$` hash of crypt(3) with the default number of rounds
+func SHA256Crypt(password string, salt string) (string, error) {
+	return shaCrypt("$5$", sha256.New, sha256CryptOrder, password, salt)
+}
+
+// SHA512Crypt
+// `$6$` hash of crypt(3) with the default number of rounds
+func SHA512Crypt(password string, salt string) (string, error) {
+	return shaCrypt("$6$", sha512.New, sha512CryptOrder, password, salt)
+}
+
+// ValidateCryptSalt
+// Validates salt of crypt(3) hashes, it may only contain characters of the crypt base64 alphabet
+func ValidateCryptSalt(salt string) error {
+	if salt == "" {
+		return errors.New("salt must not be empty")
+	}
+
+	for _, c := range salt {
+		if !strings.ContainsRune(cryptAlphabet, c) {
+			return errors.New("salt may only contain characters `./0-9A-Za-z`")
+		}
+	}
+
+	return nil
+}
+
+// RandomCryptSalt
+// Random salt of the maximal length used by SHA-crypt
+func RandomCryptSalt() (string, error) {
+	b := make([]byte, shaCryptSaltLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encodeCryptSalt(b), nil
+}
+
+// DeriveCryptSalt
+// Salt of the maximal length used by SHA-crypt, derived from `seed` and `key`.
+// Stays the same as long as both of them do, while different keys get unrelated salts
+func DeriveCryptSalt(seed string, key string) string {
+	mac := hmac.New(sha256.New, []byte(seed))
+	mac.Write([]byte(key))
+
+	return encodeCryptSalt(mac.Sum(nil)[:shaCryptSaltLength])
+}
+
+func encodeCryptSalt(b []byte) string {
+	salt := make([]byte, len(b))
+	for i, c := range b {
+		salt[i] = cryptAlphabet[c&0x3f]
+	}
+
+	return string(salt)
+}
+
+// shaCrypt
+// SHA-crypt as specified by https://www.akkadia.org/drepper/SHA-crypt.txt
+func shaCrypt(prefix string, newHash func() hash.Hash, order [][]int, password string, salt string) (string, error) {
+	const rounds = 5000
+
+	if err := ValidateCryptSalt(salt); err != nil {
+		return "", err
+	}
+
+	if len(salt) > shaCryptSaltLength {
+		salt = salt[:shaCryptSaltLength]
+	}
+
+	key := []byte(password)
+	s := []byte(salt)
+
+	digest := func(parts ...[]byte) []byte {
+		h := newHash()
+		for _, part := range parts {
+			h.Write(part)
+		}
+		return h.Sum(nil)
+	}
+
+	// repeat
+	// First `length` bytes of `value` repeated as many times as needed
+	repeat := func(value []byte, length int) []byte {
+		result := make([]byte, 0, length)
+		for len(result) < length {
+			result = append(result, value[:min(len(value), length-len(result))]...)
+		}
+		return result
+	}
+
+	b := digest(key, s, key)
+
+	h := newHash()
+	h.Write(key)
+	h.Write(s)
+	h.Write(repeat(b, len(key)))
+
+	for n := len(key); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(key)
+		}
+	}
+
+	a := h.Sum(nil)
+
+	h = newHash()
+	for range len(key) {
+		h.Write(key)
+	}
+	p := repeat(h.Sum(nil), len(key))
+
+	h = newHash()
+	for range 16 + int(a[0]) {
+		h.Write(s)
+	}
+	ds := repeat(h.Sum(nil), len(s))
+
+	for i := range rounds {
+		h = newHash()
+
+		if i%2 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(a)
+		}
+
+		if i%3 != 0 {
+			h.Write(ds)
+		}
+
+		if i%7 != 0 {
+			h.Write(p)
+		}
+
+		if i%2 != 0 {
+			h.Write(a)
+		} else {
+			h.Write(p)
+		}
+
+		a = h.Sum(nil)
+	}
+
+	var result strings.Builder
+
+	result.WriteString(prefix)
+	result.WriteString(salt)
+	result.WriteString("$")
+
+	for _, group := range order {
+		var value uint32
+		length := 0
+
+		for _, index := range group {
+			value <<= 8
+			if index >= 0 {
+				value |= uint32(a[index])
+				length++
+			}
+		}
+
+		for range length + 1 {
+			result.WriteByte(cryptAlphabet[value&0x3f])
+			value >>= 6
+		}
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,400 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+)
+
+// Parameters of `$y$j9T$`, the default of libxcrypt and mkpasswd:
+// flavor YESCRYPT_DEFAULTS, N = 4096, r = 32, p = 1
+const (
+	yescryptSetting = "$y$j9T$"
+	yescryptN       = 4096
+	yescryptR       = 32
+
+	// pwxform parameters of YESCRYPT_DEFAULTS
+	pwxSimple = 2
+	pwxGather = 4
+	pwxRounds = 6
+	sWidth    = 8
+
+	pwxWords = pwxGather * pwxSimple * 2
+	sWords   = 3 * (1 << sWidth) * pwxSimple * 2
+	sMask    = ((1 << sWidth) - 1) * pwxSimple * 8
+)
+
+// Yescrypt
+// `$y$` hash of crypt(3) with the default parameters of libxcrypt.
+// Salt is given in the crypt base64 encoding, as it appears in the hash
+func Yescrypt(password string, salt string) (string, error) {
+	if err := ValidateCryptSalt(salt); err != nil {
+		return "", err
+	}
+
+	saltBytes, err := yescryptDecode(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := []byte(password)
+
+	// NOTE: large enough N and r enable pre-hashing with N / 64, see `yescrypt_kdf`
+	prehashed, err := yescryptKDF(key, saltBytes, yescryptN>>6, yescryptR, true)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := yescryptKDF(prehashed, saltBytes, yescryptN, yescryptR, false)
+	if err != nil {
+		return "", err
+	}
+
+	return yescryptSetting + salt + "$" + yescryptEncode(hash), nil
+}
+
+// yescryptKDF
+// `yescrypt_kdf_body` of the reference implementation for p = 1, t = 0 and no ROM
+func yescryptKDF(password []byte, salt []byte, n int, r int, prehash bool) ([]byte, error) {
+	hmacKey := "yescrypt"
+	if prehash {
+		hmacKey = "yescrypt-prehash"
+	}
+
+	mac := hmac.New(sha256.New, []byte(hmacKey))
+	mac.Write(password)
+	passwd := mac.Sum(nil)
+
+	b, err := pbkdf2.Key(sha256.New, string(passwd), salt, 1, 128*r)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: the reference implementation reuses the buffer of `passwd` for the first 32 bytes of B
+	passwd = yescryptSMix(b, r, n, append([]byte(nil), b[:32]...))
+
+	dk, err := pbkdf2.Key(sha256.New, string(passwd), b, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	if prehash {
+		return dk, nil
+	}
+
+	// NOTE: final steps match SCRAM, ClientKey and StoredKey
+	mac = hmac.New(sha256.New, dk)
+	mac.Write([]byte("Client Key"))
+	stored := sha256.Sum256(mac.Sum(nil))
+
+	return stored[:], nil
+}
+
+// pwxformContext
+// S-boxes of pwxform, S0, S1 and S2 are rotated after every block
+type pwxformContext struct {
+	s0, s1, s2 []uint32
+	w          int
+}
+
+// yescryptSMix
+// `smix` of the reference implementation for p = 1, t = 0 and YESCRYPT_RW flavor.
+// Updates `b` in place and returns updated `passwd`
+func yescryptSMix(b []byte, r int, n int, passwd []byte) []byte {
+	s := 32 * r
+
+	// NOTE: N / 3 iterations of SMix2, rounded up to an even number
+	nloop := (n + 2) / 3
+	nloop = (nloop + 1) &^ 1
+
+	x := make([]uint32, s)
+	v := make([]uint32, s*n)
+	sbox := make([]uint32, sWords)
+
+	// S-boxes are initialised by SMix1 with r = 1 from the first 128 bytes of B
+	x1 := make([]uint32, 32)
+	decodeBlock(x1, b[:128])
+	smix1(x1, 1, sWords/32, sbox, nil)
+	encodeBlock(b[:128], x1)
+
+	mac := hmac.New(sha256.New, b[len(b)-64:])
+	mac.Write(passwd)
+	passwd = mac.Sum(nil)
+
+	ctx := &pwxformContext{
+		s2: sbox[:sWords/3],
+		s1: sbox[sWords/3 : 2*sWords/3],
+		s0: sbox[2*sWords/3:],
+	}
+
+	decodeBlock(x, b)
+	smix1(x, r, n, v, ctx)
+	smix2(x, r, p2floor(n), nloop, v, ctx)
+	encodeBlock(b, x)
+
+	return passwd
+}
+
+func smix1(x []uint32, r int, n int, v []uint32, ctx *pwxformContext) {
+	s := 32 * r
+	y := make([]uint32, s)
+
+	for i := range n {
+		copy(v[i*s:], x)
+
+		if ctx != nil && i > 1 {
+			j := wrap(integerify(x, r), i)
+			xorBlock(x, v[j*s:(j+1)*s])
+		}
+
+		if ctx != nil {
+			blockmixPwxform(x, r, ctx)
+		} else {
+			blockmixSalsa8(x, y, r)
+		}
+	}
+}
+
+func smix2(x []uint32, r int, n int, nloop int, v []uint32, ctx *pwxformContext) {
+	s := 32 * r
+
+	for range nloop {
+		j := int(integerify(x, r) & uint64(n-1))
+
+		xorBlock(x, v[j*s:(j+1)*s])
+		copy(v[j*s:], x)
+
+		blockmixPwxform(x, r, ctx)
+	}
+}
+
+func blockmixSalsa8(b []uint32, y []uint32, r int) {
+	x := make([]uint32, 16)
+	copy(x, b[(2*r-1)*16:])
+
+	for i := range 2 * r {
+		xorBlock(x, b[i*16:(i+1)*16])
+		salsa20(x, 8)
+		copy(y[i*16:], x)
+	}
+
+	for i := range r {
+		copy(b[i*16:], y[(2*i)*16:(2*i+1)*16])
+	}
+
+	for i := range r {
+		copy(b[(i+r)*16:], y[(2*i+1)*16:(2*i+2)*16])
+	}
+}
+
+func blockmixPwxform(b []uint32, r int, ctx *pwxformContext) {
+	r1 := 128 * r / (pwxWords * 4)
+
+	x := make([]uint32, pwxWords)
+	copy(x, b[(r1-1)*pwxWords:])
+
+	for i := range r1 {
+		if r1 > 1 {
+			xorBlock(x, b[i*pwxWords:(i+1)*pwxWords])
+		}
+
+		pwxform(x, ctx)
+		copy(b[i*pwxWords:], x)
+	}
+
+	i := (r1 - 1) * pwxWords * 4 / 64
+	salsa20(b[i*16:(i+1)*16], 2)
+
+	for i++; i < 2*r; i++ {
+		xorBlock(b[i*16:(i+1)*16], b[(i-1)*16:i*16])
+		salsa20(b[i*16:(i+1)*16], 2)
+	}
+}
+
+func pwxform(x []uint32, ctx *pwxformContext) {
+	s0, s1, s2, w := ctx.s0, ctx.s1, ctx.s2, ctx.w
+
+	for i := range pwxRounds {
+		for j := range pwxGather {
+			lane := x[j*pwxSimple*2:]
+
+			p0 := s0[(lane[0]&sMask)/4:]
+			p1 := s1[(lane[1]&sMask)/4:]
+
+			for k := range pwxSimple {
+				v0 := uint64(p0[2*k+1])<<32 | uint64(p0[2*k])
+				v1 := uint64(p1[2*k+1])<<32 | uint64(p1[2*k])
+
+				value := uint64(lane[2*k+1]) * uint64(lane[2*k])
+				value += v0
+				value ^= v1
+
+				lane[2*k] = uint32(value)
+				lane[2*k+1] = uint32(value >> 32)
+
+				if i != 0 && i != pwxRounds-1 {
+					s2[2*w] = uint32(value)
+					s2[2*w+1] = uint32(value >> 32)
+					w++
+				}
+			}
+		}
+	}
+
+	ctx.s0, ctx.s1, ctx.s2 = s2, s0, s1
+	ctx.w = w & ((1<<sWidth)*pwxSimple - 1)
+}
+
+func salsa20(b []uint32, rounds int) {
+	var x [16]uint32
+
+	// NOTE: blocks are kept SIMD-shuffled, see `decodeBlock`
+	for i := range 16 {
+		x[i*5%16] = b[i]
+	}
+
+	for i := 0; i < rounds; i += 2 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+
+	for i := range 16 {
+		b[i] += x[i*5%16]
+	}
+}
+
+// decodeBlock
+// Little-endian words of `src`, SIMD-shuffled within every 64-byte block the same way as the reference implementation.
+// The shuffle is invisible to Salsa20, but pwxform and Integerify operate on the shuffled words
+func decodeBlock(dst []uint32, src []byte) {
+	for k := 0; k < len(dst); k += 16 {
+		for i := range 16 {
+			dst[k+i] = binary.LittleEndian.Uint32(src[(k+i*5%16)*4:])
+		}
+	}
+}
+
+func encodeBlock(dst []byte, src []uint32) {
+	for k := 0; k < len(src); k += 16 {
+		for i := range 16 {
+			binary.LittleEndian.PutUint32(dst[(k+i*5%16)*4:], src[k+i])
+		}
+	}
+}
+
+func xorBlock(dst []uint32, src []uint32) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+func integerify(b []uint32, r int) uint64 {
+	x := b[(2*r-1)*16:]
+	return uint64(x[13])<<32 | uint64(x[0])
+}
+
+func p2floor(x int) int {
+	for y := x & (x - 1); y != 0; y = x & (x - 1) {
+		x = y
+	}
+	return x
+}
+
+func wrap(x uint64, i int) int {
+	n := p2floor(i)
+	return int(x&uint64(n-1)) + (i - n)
+}
+
+// yescryptEncode
+// Base64 of yescrypt, little-endian groups of three bytes
+func yescryptEncode(src []byte) string {
+	var result strings.Builder
+
+	for i := 0; i < len(src); {
+		var value, length uint32
+
+		for length < 24 && i < len(src) {
+			value |= uint32(src[i]) << length
+			length += 8
+			i++
+		}
+
+		for n := uint32(0); n < length; n += 6 {
+			result.WriteByte(cryptAlphabet[value&0x3f])
+			value >>= 6
+		}
+	}
+
+	return result.String()
+}
+
+// yescryptDecode
+// Reverse of `yescryptEncode`, the encoded salt must map to whole bytes
+func yescryptDecode(src string) ([]byte, error) {
+	var result []byte
+
+	for i := 0; i < len(src); i += 4 {
+		group := src[i:min(i+4, len(src))]
+
+		if len(group) == 1 {
+			return nil, errors.New("yescrypt salt length must not be 1 modulo 4")
+		}
+
+		var value uint32
+		for j, c := range []byte(group) {
+			value |= uint32(strings.IndexByte(cryptAlphabet, c)) << (6 * j)
+		}
+
+		for length := 6 * len(group); length >= 8; length -= 8 {
+			result = append(result, byte(value))
+			value >>= 8
+		}
+
+		if value != 0 {
+			return nil, errors.New("yescrypt salt is not a valid encoding, its last character doesn't map to whole bytes")
+		}
+	}
+
+	return result, nil
+}